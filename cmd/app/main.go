@@ -1,24 +1,48 @@
 package main
 
 import (
+	"context"
 	"effective-project/internal/cache"
 	"effective-project/internal/config"
+	"effective-project/internal/dto"
+	"effective-project/internal/gateway"
 	handlers "effective-project/internal/http"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/i18n"
+	"effective-project/internal/migrate"
 	"effective-project/internal/models"
-	"effective-project/internal/redis"
+	"effective-project/internal/notification"
 	"effective-project/internal/repository"
 	"effective-project/internal/service"
+	"effective-project/internal/worker"
 	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func main() {
 	// инициализация логгера
 	logger := config.InitLogger()
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// app migrate up|down|status|create — управление схемой без запуска сервера
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		db := func() *gorm.DB { return config.SetUpDatabaseConnection(logger) }
+		if err := migrate.Command(ctx, os.Args[2:], db, os.Stdout, logger); err != nil {
+			logger.Error("migrate failed", slog.Any("error", err))
+			os.Exit(1)
+		}
+		return
+	}
+
 	// gin router
 	router := gin.New()
 	router.Use(gin.Recovery())
@@ -34,35 +58,45 @@ func main() {
 		return
 	}
 
-	if err := db.AutoMigrate(
-		&models.User{},
-		&models.Subscription{},
-		&models.Service{},
-		&models.Payment{},
-		&models.Category{},
-		&models.Order{},
-	); err != nil {
+	// миграции применяются под advisory lock: одновременно запущенные реплики ждут друг друга
+	migrations, err := migrate.Embedded()
+	if err != nil {
+		logger.Error("failed to load migrations", slog.Any("error", err))
+		os.Exit(1)
+	}
+	if _, err := migrate.New(db, migrations, logger).Up(ctx); err != nil {
 		logger.Error("failed to migrate database", slog.Any("error", err))
 		os.Exit(1)
 	}
 
 	logger.Info("migrations completed")
 
-	// redis
-	redisAddr := os.Getenv("REDIS_ADDR")
-	if redisAddr == "" {
-		redisAddr = "redis:6379"
+	// кеш: redis, memory или none
+	// недавно просмотренные хранятся 90 дней с последнего просмотра
+	cacheBackend, err := config.SetUpCache(ctx, service.RecentlyViewedLimit, 90*24*time.Hour, logger)
+	if err != nil {
+		logger.Error("failed to set up cache", slog.Any("error", err))
+		os.Exit(1)
 	}
+	cacheStore := cacheBackend.Store
 
-	redisClient := redis.New(redisAddr)
-
-	ttl := 5 * time.Minute
+	cacheOpts := cache.Options{
+		Jitter:      0.1,
+		NotFound:    gorm.ErrRecordNotFound,
+		NegativeTTL: 30 * time.Second,
+		Codec:       cacheBackend.Codec,
+		Logger:      logger,
+	}
 
-	userCache := cache.NewUserRedisCache(redisClient)
-	paymentCache := cache.NewPaymentRedisCache(redisClient)
-	subscriptionCache := cache.NewSubscriptionRedisCache(redisClient)
-	serviceCache := cache.NewServiceCache(redisClient, ttl)
-	categoryCache := cache.NewCategoryRedisCache(redisClient)
+	userCache := cache.NewTagged(cacheStore, cache.PrefixUser, service.UserTags, cacheOpts)
+	userEmailCache := cache.NewTagged(cacheStore, cache.PrefixUserEmail, service.UserTags, cacheOpts)
+	paymentCache := cache.New[*models.Payment](cacheStore, cache.PrefixPayment, cacheOpts)
+	subscriptionCache := cache.New[*dto.SubscriptionResponse](cacheStore, cache.PrefixSubscription, cacheOpts)
+	serviceCache := cache.NewTagged(cacheStore, cache.PrefixService, service.ServiceTags, cacheOpts)
+	serviceListCache := cache.NewTagged(cacheStore, cache.PrefixServicePage, service.ServicePageTags, cacheOpts)
+	categoryCache := cache.New[*models.Category](cacheStore, cache.PrefixCategory, cacheOpts)
+	orderCache := cache.New[*models.Order](cacheStore, cache.PrefixOrder, cacheOpts)
+	catalogCache := cache.New[map[string]dto.CategoryCounts](cacheStore, cache.PrefixCatalog, cacheOpts)
 
 	// repositories
 	userRepo := repository.NewUserRepository(db, logger)
@@ -70,11 +104,64 @@ func main() {
 	serviceRepo := repository.NewServiceRepository(db, logger)
 	paymentRepo := repository.NewPaymentRepository(db, logger)
 	categoryRepo := repository.NewCategoryRepository(db, logger)
+	metricsRepo := repository.NewMetricsRepository(db, logger)
+	budgetRepo := repository.NewBudgetRepository(db, logger)
+	orderRepo := repository.NewOrderRepository(db, logger)
+	couponRepo := repository.NewCouponRepository(db, logger)
+	taxRepo := repository.NewTaxRepository(db, logger)
+	ledgerRepo := repository.NewLedgerRepository(db, logger)
+	dunningRepo := repository.NewDunningRepository(db, logger)
+	catalogRepo := repository.NewCatalogRepository(db, logger)
+	searchRepo := repository.NewSearchRepository(db, logger)
+	txManager := repository.NewTxManager(db, logger)
+
+	blobStore, err := config.SetUpBlobStore(logger)
+	if err != nil {
+		logger.Error("failed to set up media storage", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	notifier := notification.NewLogNotifier(logger)
+	paymentGateway := gateway.NewDeclineGateway(logger)
+
+	// auth
+	jwtCfg := service.JWTConfig{
+		SecretKey:      os.Getenv("JWT_SECRET"),
+		AccessTokenTTL: 24 * time.Hour,
+	}
+
+	// ссылки на календарь подписываются своим ключом: с пустым ключом подпись
+	// подделывается, а смена JWT_SECRET не должна ломать выданные ссылки
+	calendarSecret := os.Getenv("CALENDAR_SECRET")
+	if calendarSecret == "" {
+		logger.Error("CALENDAR_SECRET is not set")
+		os.Exit(1)
+	}
 
 	// services
+	authService := service.NewAuthService(
+		userRepo,
+		jwtCfg,
+		logger,
+	)
+
 	userService := service.NewUserService(
 		userRepo,
 		userCache,
+		userEmailCache,
+		logger,
+	)
+
+	couponService := service.NewCouponService(
+		couponRepo,
+		serviceRepo,
+		categoryRepo,
+		logger,
+	)
+
+	taxService := service.NewTaxService(
+		taxRepo,
+		userRepo,
 		logger,
 	)
 
@@ -82,6 +169,9 @@ func main() {
 		subscriptionRepo,
 		serviceRepo,
 		paymentRepo,
+		txManager,
+		couponService,
+		taxService,
 		subscriptionCache,
 		logger,
 	)
@@ -89,31 +179,212 @@ func main() {
 	serviceService := service.NewServiceService(
 		serviceRepo,
 		serviceCache,
+		serviceListCache,
 		logger,
 	)
 
 	paymentService := service.NewPaymentService(
 		paymentRepo,
+		txManager,
 		paymentCache,
 		logger,
 	)
 
+	ledgerService := service.NewLedgerService(
+		ledgerRepo,
+		paymentRepo,
+		logger,
+	)
+
 	categoryService := service.NewCategoryService(
 		categoryRepo,
+		txManager,
 		categoryCache,
 		logger,
 	)
 
+	orderService := service.NewOrderService(
+		orderRepo,
+		serviceRepo,
+		txManager,
+		orderCache,
+		couponService,
+		taxService,
+		logger,
+	)
+
+	metricsService := service.NewMetricsService(
+		metricsRepo,
+		cacheStore,
+		logger,
+	)
+
+	upcomingService := service.NewUpcomingService(
+		subscriptionRepo,
+		calendarSecret,
+		logger,
+	)
+
+	budgetService := service.NewBudgetService(
+		budgetRepo,
+		subscriptionService,
+		notifier,
+		logger,
+	)
+
+	searchService := service.NewSearchService(
+		searchRepo,
+		logger,
+	)
+
+	dunningService := service.NewDunningService(
+		dunningRepo,
+		subscriptionRepo,
+		paymentRepo,
+		txManager,
+		paymentGateway,
+		notifier,
+		subscriptionCache,
+		logger,
+	)
+
+	catalogService := service.NewCatalogService(
+		catalogRepo,
+		categoryRepo,
+		serviceRepo,
+		catalogCache,
+		serviceCache,
+		logger,
+	)
+
+	// агрегаты категорий пересчитываются при изменении дерева категорий, сервисов и подписок
+	categoryService = service.NewCountsInvalidatingCategoryService(categoryService, catalogService)
+	serviceService = service.NewCountsInvalidatingServiceService(serviceService, catalogService)
+	subscriptionService = service.NewCountsInvalidatingSubscriptionService(subscriptionService, catalogService)
+	dunningService = service.NewCountsInvalidatingDunningService(dunningService, catalogService)
+
+	mediaURL := os.Getenv("MEDIA_BASE_URL")
+	if mediaURL == "" {
+		mediaURL = "/media"
+	}
+
+	logoService := service.NewLogoService(
+		serviceService,
+		blobStore,
+		mediaURL,
+		logger,
+	)
+
+	// названия каталога на языке по умолчанию хранятся в записях, остальные — в переводах
+	defaultLocale := os.Getenv("DEFAULT_LOCALE")
+	if defaultLocale == "" {
+		defaultLocale = "ru"
+	}
+	supportedLocales := os.Getenv("LOCALES")
+	if supportedLocales == "" {
+		supportedLocales = "ru,en"
+	}
+	locales, err := i18n.NewLocales(defaultLocale, strings.Split(supportedLocales, ","))
+	if err != nil {
+		logger.Error("invalid locale settings", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	translationService := service.NewTranslationService(
+		repository.NewTranslationRepository(db, logger),
+		categoryRepo,
+		serviceRepo,
+		locales,
+		logger,
+	)
+
+	favoriteService := service.NewFavoriteService(
+		repository.NewFavoriteRepository(db, logger),
+		serviceRepo,
+		logger,
+	)
+
+	recentService := service.NewRecentService(
+		cacheBackend.Recent,
+		serviceRepo,
+		logger,
+	)
+
+	cacheService := service.NewCacheService(
+		cacheBackend.Stats,
+		cacheBackend.Inspector,
+		catalogRepo,
+		userRepo,
+		categoryCache,
+		serviceCache,
+		userCache,
+		logger,
+	)
+
+	// неудачный платёж запускает повторные попытки списания
+	paymentService = service.NewDunningPaymentService(
+		paymentService,
+		dunningService,
+		logger,
+	)
+
+	// бюджеты пересчитываются при изменении подписок и платежей
+	subscriptionService = service.NewBudgetTrackingSubscriptionService(
+		subscriptionService,
+		subscriptionRepo,
+		budgetService,
+		logger,
+	)
+	paymentService = service.NewBudgetTrackingPaymentService(
+		paymentService,
+		subscriptionRepo,
+		budgetService,
+		logger,
+	)
+
+	// workers
+	go worker.NewBudgetWorker(budgetService, time.Hour, logger).Run(ctx)
+	go worker.NewDunningWorker(dunningService, time.Hour, logger).Run(ctx)
+	go cacheBackend.Run(ctx)
+
+	// запросы к базе и Redis отменяются вместе с запросом клиента или по истечении таймаута
+	requestTimeout := 30 * time.Second
+	if v := os.Getenv("REQUEST_TIMEOUT"); v != "" {
+		requestTimeout, err = time.ParseDuration(v)
+		if err != nil || requestTimeout <= 0 {
+			logger.Error("invalid REQUEST_TIMEOUT", slog.String("value", v))
+			os.Exit(1)
+		}
+	}
+
 	api := router.Group("")
+	api.Use(middleware.Timeout(requestTimeout))
 	// handlers / routes
 	handlers.RegisterRoutes(
 		api,
 		logger,
+		jwtCfg,
+		authService,
 		userService,
 		paymentService,
 		subscriptionService,
 		serviceService,
 		categoryService,
+		metricsService,
+		upcomingService,
+		budgetService,
+		orderService,
+		couponService,
+		taxService,
+		ledgerService,
+		dunningService,
+		searchService,
+		catalogService,
+		logoService,
+		translationService,
+		favoriteService,
+		recentService,
+		cacheService,
 	)
 
 	port := os.Getenv("PORT")
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"effective-project/internal/cache"
+	"effective-project/internal/config"
+	"effective-project/internal/migrate"
+	"effective-project/internal/seed"
+	"effective-project/internal/service"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	defaults := seed.DefaultOptions()
+
+	opts := defaults
+	flag.Int64Var(&opts.Seed, "seed", defaults.Seed, "зерно генератора: один и тот же набор данных при одном зерне")
+	flag.IntVar(&opts.Users, "users", defaults.Users, "число обычных пользователей, кроме администратора")
+	flag.IntVar(&opts.ServicesPerCategory, "services", defaults.ServicesPerCategory, "число сервисов в каждой корневой категории")
+	flag.IntVar(&opts.SubscriptionsPerUser, "subscriptions", defaults.SubscriptionsPerUser, "наибольшее число подписок пользователя")
+	flag.IntVar(&opts.CartOrders, "cart", defaults.CartOrders, "наибольшее число неоплаченных заказов пользователя")
+	reset := flag.Bool("reset", false, "очистить все таблицы перед наполнением")
+	flag.Parse()
+
+	logger := config.InitLogger()
+
+	if opts.Users < 0 || opts.ServicesPerCategory < 0 || opts.SubscriptionsPerUser < 0 || opts.CartOrders < 0 {
+		logger.Error("volumes must not be negative")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db := config.SetUpDatabaseConnection(logger)
+
+	migrations, err := migrate.Embedded()
+	if err != nil {
+		logger.Error("failed to load migrations", slog.Any("error", err))
+		os.Exit(1)
+	}
+	if _, err := migrate.New(db, migrations, logger).Up(ctx); err != nil {
+		logger.Error("failed to migrate database", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	blobStore, err := config.SetUpBlobStore(logger)
+	if err != nil {
+		logger.Error("failed to set up media storage", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	mediaURL := os.Getenv("MEDIA_BASE_URL")
+	if mediaURL == "" {
+		mediaURL = "/media"
+	}
+
+	seeder := seed.New(db, blobStore, mediaURL, logger)
+
+	if *reset {
+		if err := seeder.Reset(ctx); err != nil {
+			logger.Error("failed to reset database", slog.Any("error", err))
+			os.Exit(1)
+		}
+	}
+
+	report, err := seeder.Run(ctx, seed.Generate(opts))
+	if err != nil {
+		logger.Error("failed to seed database", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	logger.Info("seed completed",
+		slog.Int64("seed", opts.Seed),
+		slog.Int("users", report.Users),
+		slog.Int("categories", report.Categories),
+		slog.Int("services", report.Services),
+		slog.Int("subscriptions", report.Subscriptions),
+		slog.Int("orders", report.Orders),
+		slog.Int("payments", report.Payments),
+		slog.Int("ledger_entries", report.LedgerEntries),
+		slog.String("admin", seed.AdminEmail),
+	)
+
+	flushCache(ctx, logger)
+}
+
+// flushCache очищает кеш приложения, чтобы оно не отдавало списки и
+// негативные записи, закешированные до наполнения
+func flushCache(ctx context.Context, logger *slog.Logger) {
+	backend, err := config.SetUpCache(ctx, service.RecentlyViewedLimit, time.Hour, logger)
+	if err != nil {
+		logger.Warn("cache is not flushed", slog.Any("error", err))
+		return
+	}
+
+	for _, prefix := range cache.Prefixes {
+		if _, err := backend.Inspector.DeletePrefix(ctx, prefix); err != nil {
+			logger.Warn("cache is not flushed", slog.String("prefix", prefix), slog.Any("error", err))
+			return
+		}
+	}
+}
@@ -3,5 +3,6 @@ package cache
 import "errors"
 
 var (
+	// ErrCacheMiss — ключа нет в кеше. Любая другая ошибка означает сбой хранилища
 	ErrCacheMiss = errors.New("cache miss")
 )
@@ -0,0 +1,364 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrCircuitOpen — хранилище недоступно, обращения к нему временно не выполняются
+var ErrCircuitOpen = errors.New("cache circuit open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	// пробное обращение после паузы: успех закрывает автомат, ошибка снова открывает
+	breakerHalfOpen
+)
+
+// Breaker — автомат защиты: после threshold ошибок подряд отказывает сразу,
+// не дожидаясь таймаутов, а через cooldown пропускает одно пробное обращение
+type Breaker struct {
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	logger    *slog.Logger
+}
+
+func NewBreaker(threshold int, cooldown time.Duration, logger *slog.Logger) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// Allow сообщает, можно ли обращаться к хранилищу
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// Record учитывает результат обращения. Промах и отмена запроса клиентом сбоем не считаются
+func (b *Breaker) Record(err error) {
+	if err != nil && (errors.Is(err, ErrCacheMiss) || errors.Is(err, context.Canceled)) {
+		err = nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		if b.state != breakerClosed {
+			b.logger.Info("cache: storage is back, circuit closed")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= b.threshold) {
+		b.logger.Warn("cache: storage unavailable, circuit opened",
+			slog.Duration("cooldown", b.cooldown), slog.Any("error", err))
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Trip открывает автомат сразу, например если хранилище недоступно уже при запуске
+func (b *Breaker) Trip(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.logger.Warn("cache: storage unavailable, circuit opened",
+		slog.Duration("cooldown", b.cooldown), slog.Any("error", err))
+	b.state = breakerOpen
+	b.openedAt = b.now()
+}
+
+// maxPendingInvalidations — сколько ключей и тегов BreakerStore помнит, пока
+// хранилище недоступно. При переполнении после восстановления пространства
+// кеша очищаются целиком
+const maxPendingInvalidations = 10000
+
+// BreakerStore защищает хранилище автоматом: пока он открыт, все операции
+// сразу возвращают ErrCircuitOpen, и Cache[T] читает из источника.
+// Несостоявшиеся удаления запоминаются и досылаются первым же успешным
+// обращением, иначе старые значения пережили бы сбой до своего TTL
+type BreakerStore struct {
+	store   Store
+	breaker *Breaker
+
+	mu       sync.Mutex
+	keys     map[string]struct{}
+	tags     map[string]struct{}
+	overflow bool
+}
+
+func NewBreakerStore(store Store, breaker *Breaker) *BreakerStore {
+	return &BreakerStore{
+		store:   store,
+		breaker: breaker,
+	}
+}
+
+func (s *BreakerStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if !s.breaker.Allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	data, err := s.store.Get(ctx, key)
+	s.record(ctx, err)
+
+	return data, err
+}
+
+func (s *BreakerStore) GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	if !s.breaker.Allow() {
+		return nil, 0, ErrCircuitOpen
+	}
+
+	data, ttl, err := getWithTTL(ctx, s.store, key)
+	s.record(ctx, err)
+
+	return data, ttl, err
+}
+
+func (s *BreakerStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if !s.breaker.Allow() {
+		return ErrCircuitOpen
+	}
+
+	err := s.store.Set(ctx, key, value, ttl, tags...)
+	s.record(ctx, err)
+
+	return err
+}
+
+func (s *BreakerStore) Delete(ctx context.Context, key string) error {
+	if !s.breaker.Allow() {
+		s.remember([]string{key}, nil)
+		return ErrCircuitOpen
+	}
+
+	err := s.store.Delete(ctx, key)
+	if err != nil {
+		s.remember([]string{key}, nil)
+	}
+	s.record(ctx, err)
+
+	return err
+}
+
+func (s *BreakerStore) Invalidate(ctx context.Context, tags ...string) ([]string, error) {
+	if !s.breaker.Allow() {
+		s.remember(nil, tags)
+		return nil, ErrCircuitOpen
+	}
+
+	keys, err := s.store.Invalidate(ctx, tags...)
+	if err != nil {
+		s.remember(nil, tags)
+	}
+	s.record(ctx, err)
+
+	return keys, err
+}
+
+func (s *BreakerStore) Usage(ctx context.Context, prefix string) (Usage, error) {
+	inspector, err := inspect(s.store)
+	if err != nil {
+		return Usage{}, err
+	}
+	if !s.breaker.Allow() {
+		return Usage{}, ErrCircuitOpen
+	}
+
+	usage, err := inspector.Usage(ctx, prefix)
+	s.record(ctx, err)
+
+	return usage, err
+}
+
+func (s *BreakerStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	inspector, err := inspect(s.store)
+	if err != nil {
+		return 0, err
+	}
+	if !s.breaker.Allow() {
+		return 0, ErrCircuitOpen
+	}
+
+	deleted, err := inspector.DeletePrefix(ctx, prefix)
+	s.record(ctx, err)
+
+	return deleted, err
+}
+
+// record учитывает результат обращения и, если хранилище снова отвечает,
+// досылает отложенные удаления
+func (s *BreakerStore) record(ctx context.Context, err error) {
+	s.breaker.Record(err)
+
+	if err == nil || errors.Is(err, ErrCacheMiss) {
+		s.replay(ctx)
+	}
+}
+
+func (s *BreakerStore) remember(keys, tags []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.overflow {
+		return
+	}
+	if len(s.keys)+len(s.tags)+len(keys)+len(tags) > maxPendingInvalidations {
+		s.breaker.logger.Warn("cache: too many pending invalidations, cache will be flushed on recovery")
+		s.keys, s.tags, s.overflow = nil, nil, true
+		return
+	}
+
+	if s.keys == nil {
+		s.keys = make(map[string]struct{})
+		s.tags = make(map[string]struct{})
+	}
+	for _, key := range keys {
+		s.keys[key] = struct{}{}
+	}
+	for _, tag := range tags {
+		s.tags[tag] = struct{}{}
+	}
+}
+
+// replay досылает удаления, накопленные за время сбоя. Забирает их под
+// мьютексом, поэтому одну порцию досылает только одно обращение; то, что
+// снова не удалось, возвращается в очередь
+func (s *BreakerStore) replay(ctx context.Context) {
+	s.mu.Lock()
+	keys, tags, overflow := s.keys, s.tags, s.overflow
+	s.keys, s.tags, s.overflow = nil, nil, false
+	s.mu.Unlock()
+
+	if len(keys) == 0 && len(tags) == 0 && !overflow {
+		return
+	}
+
+	// клиент, чей запрос досылает удаления, может уже уйти
+	ctx = context.WithoutCancel(ctx)
+
+	if overflow {
+		s.flush(ctx)
+		return
+	}
+
+	for key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			s.remember([]string{key}, nil)
+		}
+	}
+
+	if len(tags) > 0 {
+		list := make([]string, 0, len(tags))
+		for tag := range tags {
+			list = append(list, tag)
+		}
+		if _, err := s.store.Invalidate(ctx, list...); err != nil {
+			s.remember(nil, list)
+		}
+	}
+}
+
+// flush очищает все пространства кеша, кроме истории просмотров: она не кеш
+// и в источнике не хранится
+func (s *BreakerStore) flush(ctx context.Context) {
+	inspector, err := inspect(s.store)
+	if err != nil {
+		return
+	}
+
+	for _, prefix := range Prefixes {
+		if prefix == PrefixRecent {
+			continue
+		}
+		if _, err := inspector.DeletePrefix(ctx, prefix); err != nil {
+			s.mu.Lock()
+			s.keys, s.tags, s.overflow = nil, nil, true
+			s.mu.Unlock()
+			return
+		}
+	}
+
+	s.breaker.logger.Info("cache: flushed after pending invalidations overflow")
+}
+
+// BreakerRecentlyViewed защищает историю просмотров тем же автоматом, что и кеш:
+// они живут в одном Redis
+type BreakerRecentlyViewed struct {
+	recent  RecentlyViewed
+	breaker *Breaker
+}
+
+func NewBreakerRecentlyViewed(recent RecentlyViewed, breaker *Breaker) *BreakerRecentlyViewed {
+	return &BreakerRecentlyViewed{
+		recent:  recent,
+		breaker: breaker,
+	}
+}
+
+func (r *BreakerRecentlyViewed) Add(ctx context.Context, userID, serviceID uuid.UUID, at time.Time) error {
+	if !r.breaker.Allow() {
+		return ErrCircuitOpen
+	}
+
+	err := r.recent.Add(ctx, userID, serviceID, at)
+	r.breaker.Record(err)
+
+	return err
+}
+
+func (r *BreakerRecentlyViewed) List(ctx context.Context, userID uuid.UUID, limit int) ([]View, error) {
+	if !r.breaker.Allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	views, err := r.recent.List(ctx, userID, limit)
+	r.breaker.Record(err)
+
+	return views, err
+}
+
+func (r *BreakerRecentlyViewed) Clear(ctx context.Context, userID uuid.UUID) error {
+	if !r.breaker.Allow() {
+		return ErrCircuitOpen
+	}
+
+	err := r.recent.Clear(ctx, userID)
+	r.breaker.Record(err)
+
+	return err
+}
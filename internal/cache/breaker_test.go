@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyStore отвечает ошибкой, пока down
+type flakyStore struct {
+	*testStore
+	down  bool
+	calls int
+}
+
+func (s *flakyStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.calls++
+	if s.down {
+		return nil, errors.New("connection refused")
+	}
+	return s.testStore.Get(ctx, key)
+}
+
+func newTestBreaker() (*Breaker, *time.Time) {
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(3, 10*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	breaker.now = func() time.Time { return now }
+	return breaker, &now
+}
+
+func TestBreakerStore_OpensAfterThreshold(t *testing.T) {
+	breaker, _ := newTestBreaker()
+	remote := &flakyStore{testStore: newTestStore(), down: true}
+	store := NewBreakerStore(remote, breaker)
+
+	for range 3 {
+		_, err := store.Get(context.Background(), "k")
+		assert.Error(t, err)
+	}
+	_, err := store.Get(context.Background(), "k")
+
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, remote.calls)
+}
+
+func TestBreakerStore_MissIsNotFailure(t *testing.T) {
+	breaker, _ := newTestBreaker()
+	remote := &flakyStore{testStore: newTestStore()}
+	store := NewBreakerStore(remote, breaker)
+
+	for range 5 {
+		_, err := store.Get(context.Background(), "k")
+		assert.ErrorIs(t, err, ErrCacheMiss)
+	}
+
+	assert.Equal(t, 5, remote.calls)
+}
+
+func TestBreakerStore_HalfOpenProbe(t *testing.T) {
+	breaker, now := newTestBreaker()
+	remote := &flakyStore{testStore: newTestStore(), down: true}
+	store := NewBreakerStore(remote, breaker)
+	breaker.Trip(errors.New("connection refused"))
+
+	*now = now.Add(10 * time.Second)
+	_, err := store.Get(context.Background(), "k")
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+	_, err = store.Get(context.Background(), "k")
+	assert.ErrorIs(t, err, ErrCircuitOpen, "failed probe reopens the circuit")
+
+	remote.down = false
+	*now = now.Add(10 * time.Second)
+	_, err = store.Get(context.Background(), "k")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	_, err = store.Get(context.Background(), "k")
+	assert.ErrorIs(t, err, ErrCacheMiss, "successful probe closes the circuit")
+}
+
+func TestBreakerStore_ReplaysDeletesAfterRecovery(t *testing.T) {
+	ctx := context.Background()
+	breaker, now := newTestBreaker()
+	remote := newTestStore()
+	store := NewBreakerStore(remote, breaker)
+	require.NoError(t, store.Set(ctx, "category:1", []byte("1"), time.Minute))
+	require.NoError(t, store.Set(ctx, "user:id:1", []byte("2"), time.Minute, "user:1"))
+
+	breaker.Trip(errors.New("connection refused"))
+	assert.ErrorIs(t, store.Delete(ctx, "category:1"), ErrCircuitOpen)
+	_, err := store.Invalidate(ctx, "user:1")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Len(t, remote.values, 2)
+
+	*now = now.Add(10 * time.Second)
+	_, err = store.Get(ctx, "other")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	assert.Empty(t, remote.values, "deletes are replayed once storage is back")
+}
+
+func TestCache_GetOrLoad_ReadsSourceWhenCircuitOpen(t *testing.T) {
+	breaker, _ := newTestBreaker()
+	breaker.Trip(errors.New("connection refused"))
+	c := New[*item](NewBreakerStore(newTestStore(), breaker), "item:", Options{})
+
+	got, err := c.GetOrLoad(context.Background(), "1", func(ctx context.Context) (*item, error) {
+		return &item{Name: "Музыка"}, nil
+	}, time.Minute)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Музыка", got.Name)
+}
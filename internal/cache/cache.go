@@ -0,0 +1,286 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache — типизированный read-through кеш.
+//
+// Контракт промаха один для всех ключей:
+//   - Get возвращает ErrCacheMiss, если ключа нет;
+//   - для записи, которой нет в источнике, Get возвращает Options.NotFound;
+//   - любая другая ошибка означает сбой хранилища.
+type Cache[T any] interface {
+	Get(ctx context.Context, key string) (T, error)
+
+	Set(ctx context.Context, key string, value T, ttl time.Duration) error
+
+	Delete(ctx context.Context, key string) error
+
+	// Invalidate удаляет все ключи хранилища, привязанные к тегам,
+	// в том числе ключи других кешей
+	Invalidate(ctx context.Context, tags ...string) error
+
+	// GetOrLoad отдаёт значение из кеша, а при промахе вызывает load и сохраняет
+	// результат. Одновременные промахи по одному ключу вызывают load один раз.
+	// Сбой хранилища, в том числе открытый автомат защиты, не прерывает
+	// чтение: значение берётся из load
+	GetOrLoad(ctx context.Context, key string, load Loader[T], ttl time.Duration) (T, error)
+}
+
+// Loader достаёт значение из источника при промахе
+type Loader[T any] func(ctx context.Context) (T, error)
+
+// TagFunc возвращает теги записи, например user:<id> для пользователя,
+// найденного по email. Изменение сущности удаляет все ключи её тега
+type TagFunc[T any] func(key string, value T) []string
+
+type Options struct {
+	// Jitter — доля TTL, на которую случайно сдвигается срок жизни ключа,
+	// чтобы записанные вместе ключи не истекали одновременно
+	Jitter float64
+
+	// NotFound — ошибка загрузчика, означающая, что записи нет в источнике.
+	// Такой ответ кешируется на NegativeTTL; nil или нулевой NegativeTTL
+	// отключают негативный кеш
+	NotFound    error
+	NegativeTTL time.Duration
+
+	// Codec кодирует значения; по умолчанию JSON
+	Codec Codec
+
+	// Version поднимают вручную, когда меняется смысл полей, а не их набор:
+	// изменения набора полей версия схемы учитывает сама
+	Version uint64
+
+	Logger *slog.Logger
+}
+
+// negative — маркер отсутствующей записи. Конверт не начинается с нулевого байта
+var negative = []byte{0}
+
+// flights общий для всех кешей: ключи разных кешей различаются префиксом
+var flights singleflight.Group
+
+// prefixCounters — попадания кешей этой реплики по префиксам: string → *tierCounters
+var prefixCounters sync.Map
+
+func countersFor(prefix string) *tierCounters {
+	counters, _ := prefixCounters.LoadOrStore(prefix, &tierCounters{})
+	return counters.(*tierCounters)
+}
+
+// PrefixStats возвращает попадания и промахи кешей с префиксом с момента запуска.
+// Негативная запись считается попаданием: источник не читается
+func PrefixStats(prefix string) TierStats {
+	return countersFor(prefix).snapshot()
+}
+
+// Значения лежат в конверте с версией схемы T. Значение другой версии или
+// другого кодека считается промахом, и загрузчик перезаписывает его:
+// после выкладки новая форма не читается из записей старого кода.
+// Пока в работе реплики обеих версий, они перезаписывают значения друг друга
+type typedCache[T any] struct {
+	store   Store
+	prefix  string
+	tags    TagFunc[T]
+	version uint64
+	stats   *tierCounters
+	opts    Options
+}
+
+// New создаёт кеш значений T с ключами вида prefix + key
+func New[T any](store Store, prefix string, opts Options) Cache[T] {
+	return NewTagged[T](store, prefix, nil, opts)
+}
+
+// NewTagged создаёт кеш, записи которого привязаны к тегам из tags.
+// Негативные записи тегов не получают и живут NegativeTTL
+func NewTagged[T any](store Store, prefix string, tags TagFunc[T], opts Options) Cache[T] {
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.DiscardHandler)
+	}
+	if opts.Codec == nil {
+		opts.Codec = JSON
+	}
+
+	return &typedCache[T]{
+		store:   store,
+		prefix:  prefix,
+		tags:    tags,
+		version: SchemaVersion(reflect.TypeFor[T](), opts.Version),
+		stats:   countersFor(prefix),
+		opts:    opts,
+	}
+}
+
+func (c *typedCache[T]) key(key string) string {
+	return c.prefix + key
+}
+
+func (c *typedCache[T]) Get(ctx context.Context, key string) (T, error) {
+	value, err := c.get(ctx, key)
+	if err == nil || c.isNotFound(err) {
+		c.stats.hits.Add(1)
+	} else {
+		c.stats.misses.Add(1)
+	}
+
+	return value, err
+}
+
+func (c *typedCache[T]) get(ctx context.Context, key string) (T, error) {
+	var value T
+
+	data, err := c.store.Get(ctx, c.key(key))
+	if err != nil {
+		return value, err
+	}
+
+	if bytes.Equal(data, negative) {
+		if c.opts.NotFound == nil {
+			return value, ErrCacheMiss
+		}
+		return value, c.opts.NotFound
+	}
+
+	env, err := DecodeEnvelope(data)
+	if err != nil {
+		// значение, записанное до конвертов, считаем промахом: его перезапишет загрузчик
+		c.opts.Logger.Debug("cache: value without envelope", slog.String("key", c.key(key)))
+		return value, ErrCacheMiss
+	}
+
+	if env.Version != c.version || env.Codec != c.opts.Codec.ID() {
+		c.opts.Logger.Debug("cache: schema version mismatch", slog.String("key", c.key(key)),
+			slog.Uint64("version", env.Version), slog.Time("encoded_at", env.EncodedAt))
+		return value, ErrCacheMiss
+	}
+
+	if err := c.opts.Codec.Unmarshal(env.Payload, &value); err != nil {
+		c.opts.Logger.Warn("cache: failed to decode value", slog.String("key", c.key(key)), slog.Any("error", err))
+		var zero T
+		return zero, ErrCacheMiss
+	}
+
+	return value, nil
+}
+
+func (c *typedCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, tags, err := c.encode(key, value)
+	if err != nil {
+		return err
+	}
+
+	return c.store.Set(ctx, c.key(key), data, c.jitter(ttl), tags...)
+}
+
+// fill записывает значение, загруженное после промаха
+func (c *typedCache[T]) fill(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, tags, err := c.encode(key, value)
+	if err != nil {
+		return err
+	}
+
+	return fill(ctx, c.store, c.key(key), data, c.jitter(ttl), tags...)
+}
+
+func (c *typedCache[T]) encode(key string, value T) ([]byte, []string, error) {
+	payload, err := c.opts.Codec.Marshal(value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := Envelope{
+		Codec:     c.opts.Codec.ID(),
+		Version:   c.version,
+		EncodedAt: time.Now(),
+		Payload:   payload,
+	}.Encode()
+
+	var tags []string
+	if c.tags != nil {
+		tags = c.tags(key, value)
+	}
+
+	return data, tags, nil
+}
+
+func (c *typedCache[T]) Delete(ctx context.Context, key string) error {
+	return c.store.Delete(ctx, c.key(key))
+}
+
+func (c *typedCache[T]) Invalidate(ctx context.Context, tags ...string) error {
+	_, err := c.store.Invalidate(ctx, tags...)
+	return err
+}
+
+func (c *typedCache[T]) GetOrLoad(ctx context.Context, key string, load Loader[T], ttl time.Duration) (T, error) {
+	value, err := c.Get(ctx, key)
+	switch {
+	case err == nil:
+		return value, nil
+	case c.isNotFound(err):
+		return value, err
+	case !errors.Is(err, ErrCacheMiss) && !errors.Is(err, ErrCircuitOpen):
+		c.opts.Logger.Warn("cache: failed to get value", slog.String("key", c.key(key)), slog.Any("error", err))
+	}
+
+	result := flights.DoChan(c.key(key), func() (any, error) {
+		// загрузка общая для всех ожидающих и не обрывается, если первый из них ушёл
+		ctx := context.WithoutCancel(ctx)
+
+		value, err := load(ctx)
+		if err != nil {
+			if c.isNotFound(err) && c.opts.NegativeTTL > 0 {
+				err := fill(ctx, c.store, c.key(key), negative, c.jitter(c.opts.NegativeTTL))
+				if err != nil && !errors.Is(err, ErrCircuitOpen) {
+					c.opts.Logger.Warn("cache: failed to set negative value", slog.String("key", c.key(key)), slog.Any("error", err))
+				}
+			}
+			return value, err
+		}
+
+		if err := c.fill(ctx, key, value, ttl); err != nil && !errors.Is(err, ErrCircuitOpen) {
+			c.opts.Logger.Warn("cache: failed to set value", slog.String("key", c.key(key)), slog.Any("error", err))
+		}
+
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case res := <-result:
+		value, ok := res.Val.(T)
+		if !ok {
+			// тот же ключ загружает кеш другого типа — грузим сами
+			return load(ctx)
+		}
+		return value, res.Err
+	}
+}
+
+func (c *typedCache[T]) isNotFound(err error) bool {
+	return c.opts.NotFound != nil && errors.Is(err, c.opts.NotFound)
+}
+
+// jitter сдвигает ttl на случайную величину в пределах ±Jitter·ttl
+func (c *typedCache[T]) jitter(ttl time.Duration) time.Duration {
+	delta := time.Duration(float64(ttl) * c.opts.Jitter)
+	if delta <= 0 {
+		return ttl
+	}
+
+	return ttl - delta + rand.N(2*delta+1)
+}
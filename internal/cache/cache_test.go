@@ -0,0 +1,263 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("not found")
+
+type testStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	tags   map[string][]string
+	getErr error
+}
+
+func newTestStore() *testStore {
+	return &testStore{values: map[string][]byte{}, ttls: map[string]time.Duration{}, tags: map[string][]string{}}
+}
+
+func (s *testStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+	data, ok := s.values[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return data, nil
+}
+
+func (s *testStore) GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	data, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return data, s.ttls[key], nil
+}
+
+func (s *testStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	s.ttls[key] = ttl
+	for _, tag := range tags {
+		s.tags[tag] = append(s.tags[tag], key)
+	}
+	return nil
+}
+
+func (s *testStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+	return nil
+}
+
+func (s *testStore) Invalidate(ctx context.Context, tags ...string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted []string
+	for _, tag := range tags {
+		for _, key := range s.tags[tag] {
+			delete(s.values, key)
+			deleted = append(deleted, key)
+		}
+		delete(s.tags, tag)
+	}
+	return deleted, nil
+}
+
+type item struct {
+	Name string
+}
+
+func TestCache_GetMiss(t *testing.T) {
+	c := New[*item](newTestStore(), "item:", Options{})
+
+	got, err := c.Get(context.Background(), "1")
+
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	assert.Nil(t, got)
+}
+
+func TestCache_GetOrLoad_ReadThrough(t *testing.T) {
+	store := newTestStore()
+	c := New[*item](store, "item:", Options{})
+
+	calls := 0
+	load := func(ctx context.Context) (*item, error) {
+		calls++
+		return &item{Name: "Музыка"}, nil
+	}
+
+	first, err := c.GetOrLoad(context.Background(), "1", load, time.Minute)
+	require.NoError(t, err)
+	second, err := c.GetOrLoad(context.Background(), "1", load, time.Minute)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "Музыка", first.Name)
+	assert.Equal(t, first, second)
+	assert.Contains(t, store.values, "item:1")
+}
+
+func TestCache_GetOrLoad_Coalesces(t *testing.T) {
+	c := New[*item](newTestStore(), "item:", Options{})
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (*item, error) {
+		calls.Add(1)
+		<-release
+		return &item{Name: "Спорт"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := c.GetOrLoad(context.Background(), "1", load, time.Minute)
+			assert.NoError(t, err)
+			assert.Equal(t, "Спорт", got.Name)
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCache_GetOrLoad_NegativeCaching(t *testing.T) {
+	store := newTestStore()
+	c := New[*item](store, "item:", Options{NotFound: errNotFound, NegativeTTL: time.Second})
+
+	calls := 0
+	load := func(ctx context.Context) (*item, error) {
+		calls++
+		return nil, errNotFound
+	}
+
+	_, err := c.GetOrLoad(context.Background(), "1", load, time.Minute)
+	assert.ErrorIs(t, err, errNotFound)
+	_, err = c.GetOrLoad(context.Background(), "1", load, time.Minute)
+	assert.ErrorIs(t, err, errNotFound)
+	_, err = c.Get(context.Background(), "1")
+	assert.ErrorIs(t, err, errNotFound)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, time.Second, store.ttls["item:1"])
+}
+
+func TestCache_GetOrLoad_OtherErrorsNotCached(t *testing.T) {
+	c := New[*item](newTestStore(), "item:", Options{NotFound: errNotFound, NegativeTTL: time.Second})
+
+	calls := 0
+	load := func(ctx context.Context) (*item, error) {
+		calls++
+		return nil, errors.New("db error")
+	}
+
+	_, err := c.GetOrLoad(context.Background(), "1", load, time.Minute)
+	assert.Error(t, err)
+	_, err = c.GetOrLoad(context.Background(), "1", load, time.Minute)
+	assert.Error(t, err)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestCache_GetOrLoad_StoreFailureFallsBack(t *testing.T) {
+	store := newTestStore()
+	store.getErr = errors.New("connection refused")
+	c := New[*item](store, "item:", Options{})
+
+	got, err := c.GetOrLoad(context.Background(), "1", func(ctx context.Context) (*item, error) {
+		return &item{Name: "Кино"}, nil
+	}, time.Minute)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Кино", got.Name)
+}
+
+func TestCache_GetUndecodableIsMiss(t *testing.T) {
+	store := newTestStore()
+	store.values["item:1"] = []byte(`"not an object"`)
+	c := New[*item](store, "item:", Options{})
+
+	_, err := c.Get(context.Background(), "1")
+
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestCache_Jitter(t *testing.T) {
+	store := newTestStore()
+	c := New[*item](store, "item:", Options{Jitter: 0.1})
+
+	for range 100 {
+		require.NoError(t, c.Set(context.Background(), "1", &item{}, 100*time.Second))
+
+		ttl := store.ttls["item:1"]
+		assert.GreaterOrEqual(t, ttl, 90*time.Second)
+		assert.LessOrEqual(t, ttl, 110*time.Second)
+	}
+}
+
+func TestCache_InvalidateRemovesTaggedKeysOfAllCaches(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+
+	byTag := func(key string, value item) []string { return []string{"item:" + value.Name} }
+	byID := NewTagged(store, "item:id:", byTag, Options{})
+	byName := NewTagged(store, "item:name:", byTag, Options{})
+	other := New[item](store, "other:", Options{})
+
+	require.NoError(t, byID.Set(ctx, "1", item{Name: "a"}, time.Minute))
+	require.NoError(t, byName.Set(ctx, "a", item{Name: "a"}, time.Minute))
+	require.NoError(t, byID.Set(ctx, "2", item{Name: "b"}, time.Minute))
+	require.NoError(t, other.Set(ctx, "1", item{Name: "a"}, time.Minute))
+
+	require.NoError(t, byID.Invalidate(ctx, "item:a"))
+
+	_, err := byID.Get(ctx, "1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	_, err = byName.Get(ctx, "a")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	_, err = byID.Get(ctx, "2")
+	assert.NoError(t, err)
+	_, err = other.Get(ctx, "1")
+	assert.NoError(t, err, "untagged keys survive")
+}
+
+func TestCache_CountsHitsPerPrefix(t *testing.T) {
+	ctx := context.Background()
+	c := New[item](newTestStore(), "counted:", Options{})
+
+	_, _ = c.Get(ctx, "1")
+	require.NoError(t, c.Set(ctx, "1", item{Name: "a"}, time.Minute))
+	_, _ = c.Get(ctx, "1")
+	_, _ = c.Get(ctx, "1")
+
+	assert.Equal(t, TierStats{Hits: 2, Misses: 1}, PrefixStats("counted:"))
+}
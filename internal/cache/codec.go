@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ugorji/go/codec"
+)
+
+// Codec переводит значения кеша в байты и обратно
+type Codec interface {
+	// ID записывается в конверт: значение, записанное другим кодеком, считается промахом
+	ID() byte
+
+	Marshal(v any) ([]byte, error)
+
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	// JSON — кодек по умолчанию: значения читаются глазами в redis-cli
+	JSON Codec = jsonCodec{}
+
+	// MsgPack компактнее и быстрее JSON. Имена полей берутся из тегов json,
+	// поля с json:"-" так же не попадают в кеш
+	MsgPack Codec = msgpackCodec{}
+)
+
+// CodecByName возвращает кодек по имени из конфигурации: json или msgpack
+func CodecByName(name string) (Codec, error) {
+	switch name {
+	case "", "json":
+		return JSON, nil
+	case "msgpack":
+		return MsgPack, nil
+	default:
+		return nil, fmt.Errorf("unknown cache codec %q", name)
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ID() byte {
+	return 1
+}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// WriteExt включает актуальную спецификацию msgpack: отдельные типы
+// для строк и байтов и расширение для time.Time
+var msgpackHandle = &codec.MsgpackHandle{WriteExt: true}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) ID() byte {
+	return 2
+}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	var data []byte
+	if err := codec.NewEncoderBytes(&data, msgpackHandle).Encode(v); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v any) error {
+	return codec.NewDecoderBytes(data, msgpackHandle).Decode(v)
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var ErrInvalidEnvelope = errors.New("cache value is not an envelope")
+
+// Заголовок конверта: метка, кодек, версия схемы (8 байт), время записи
+// в unix-наносекундах (8 байт). Метка отличает конверт от значений,
+// записанных до появления конвертов, и от маркера отсутствующей записи
+const (
+	envelopeMagic      byte = 0xCE
+	envelopeHeaderSize      = 1 + 1 + 8 + 8
+)
+
+// Envelope — значение кеша вместе с версией схемы, по которой оно записано
+type Envelope struct {
+	Codec     byte
+	Version   uint64
+	EncodedAt time.Time
+	Payload   []byte
+}
+
+func (e Envelope) Encode() []byte {
+	data := make([]byte, envelopeHeaderSize, envelopeHeaderSize+len(e.Payload))
+	data[0] = envelopeMagic
+	data[1] = e.Codec
+	binary.BigEndian.PutUint64(data[2:10], e.Version)
+	binary.BigEndian.PutUint64(data[10:18], uint64(e.EncodedAt.UnixNano()))
+
+	return append(data, e.Payload...)
+}
+
+// DecodeEnvelope разбирает заголовок; Payload ссылается на data
+func DecodeEnvelope(data []byte) (Envelope, error) {
+	if len(data) < envelopeHeaderSize || data[0] != envelopeMagic {
+		return Envelope{}, ErrInvalidEnvelope
+	}
+
+	return Envelope{
+		Codec:     data[1],
+		Version:   binary.BigEndian.Uint64(data[2:10]),
+		EncodedAt: time.Unix(0, int64(binary.BigEndian.Uint64(data[10:18]))),
+		Payload:   data[envelopeHeaderSize:],
+	}, nil
+}
+
+// SchemaVersion вычисляет версию схемы по форме типа: именам и тегам полей
+// и их типам, рекурсивно. Добавление, удаление или смена типа поля меняют
+// версию, и значения, записанные прежним кодом, перестают читаться.
+// manual добавляется к форме для изменений смысла полей, которые форму не меняют
+func SchemaVersion(t reflect.Type, manual uint64) uint64 {
+	var b strings.Builder
+	fmt.Fprintf(&b, "v%d:", manual)
+	describeType(&b, t, map[reflect.Type]bool{})
+
+	h := fnv.New64a()
+	h.Write([]byte(b.String()))
+	return h.Sum64()
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[interface{ MarshalText() ([]byte, error) }]()
+)
+
+func describeType(b *strings.Builder, t reflect.Type, seen map[reflect.Type]bool) {
+	// типы со своей сериализацией (time.Time, uuid.UUID) описываются именем
+	if t.Name() != "" && (t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)) {
+		b.WriteString(t.PkgPath() + "." + t.Name())
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		b.WriteByte('*')
+		describeType(b, t.Elem(), seen)
+	case reflect.Slice:
+		b.WriteString("[]")
+		describeType(b, t.Elem(), seen)
+	case reflect.Array:
+		fmt.Fprintf(b, "[%d]", t.Len())
+		describeType(b, t.Elem(), seen)
+	case reflect.Map:
+		b.WriteString("map[")
+		describeType(b, t.Key(), seen)
+		b.WriteByte(']')
+		describeType(b, t.Elem(), seen)
+	case reflect.Struct:
+		if seen[t] {
+			b.WriteString(t.PkgPath() + "." + t.Name())
+			return
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		b.WriteByte('{')
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			fmt.Fprintf(b, "%s %q ", f.Name, f.Tag.Get("json"))
+			describeType(b, f.Type, seen)
+			b.WriteByte(';')
+		}
+		b.WriteByte('}')
+	default:
+		b.WriteString(t.Kind().String())
+	}
+}
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type itemV1 struct {
+	Name string `json:"name"`
+}
+
+type itemV2 struct {
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+type tree struct {
+	Name     string  `json:"name"`
+	Children []*tree `json:"children"`
+}
+
+// record похож на модели, которые лежат в кеше
+type record struct {
+	ID        uuid.UUID         `json:"id"`
+	CreatedAt time.Time         `json:"created_at"`
+	Name      string            `json:"name"`
+	Secret    string            `json:"-"`
+	ParentID  *uuid.UUID        `json:"parent_id"`
+	Price     int64             `json:"price"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+}
+
+func newRecord() record {
+	parent := uuid.New()
+	return record{
+		ID:        uuid.New(),
+		CreatedAt: time.Date(2025, time.March, 1, 12, 30, 0, 0, time.UTC),
+		Name:      "Яндекс Плюс",
+		Secret:    "hash",
+		ParentID:  &parent,
+		Price:     39900,
+		Tags:      []string{"музыка", "кино"},
+		Labels:    map[string]string{"country": "RU"},
+	}
+}
+
+func TestCache_SchemaChangeIsBypassed(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+
+	old := New[itemV1](store, "item:", Options{})
+	require.NoError(t, old.Set(ctx, "1", itemV1{Name: "Кино"}, time.Minute))
+
+	current := New[itemV2](store, "item:", Options{})
+	_, err := current.Get(ctx, "1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	got, err := current.GetOrLoad(ctx, "1", func(ctx context.Context) (itemV2, error) {
+		return itemV2{Name: "Кино", Price: 100}, nil
+	}, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 100, got.Price)
+
+	got, err = current.Get(ctx, "1")
+	require.NoError(t, err, "value is rewritten in the new shape")
+	assert.Equal(t, 100, got.Price)
+}
+
+func TestCache_ManualVersionAndCodecChangeAreBypassed(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore()
+
+	require.NoError(t, New[itemV1](store, "item:", Options{}).Set(ctx, "1", itemV1{Name: "Кино"}, time.Minute))
+
+	_, err := New[itemV1](store, "item:", Options{Version: 2}).Get(ctx, "1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	_, err = New[itemV1](store, "item:", Options{Codec: MsgPack}).Get(ctx, "1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	_, err = New[itemV1](store, "item:", Options{}).Get(ctx, "1")
+	assert.NoError(t, err)
+}
+
+func TestCache_ValueWithoutEnvelopeIsMiss(t *testing.T) {
+	store := newTestStore()
+	store.values["item:1"] = []byte(`{"name":"Кино"}`)
+
+	_, err := New[itemV1](store, "item:", Options{}).Get(context.Background(), "1")
+
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	at := time.Date(2025, time.March, 1, 12, 0, 0, 42, time.UTC)
+	env := Envelope{Codec: 2, Version: 7, EncodedAt: at, Payload: []byte("payload")}
+
+	got, err := DecodeEnvelope(env.Encode())
+
+	require.NoError(t, err)
+	assert.Equal(t, env.Codec, got.Codec)
+	assert.Equal(t, env.Version, got.Version)
+	assert.True(t, at.Equal(got.EncodedAt))
+	assert.Equal(t, env.Payload, got.Payload)
+
+	_, err = DecodeEnvelope(negative)
+	assert.ErrorIs(t, err, ErrInvalidEnvelope)
+}
+
+func TestSchemaVersion(t *testing.T) {
+	assert.Equal(t, SchemaVersion(reflect.TypeFor[itemV1](), 0), SchemaVersion(reflect.TypeFor[itemV1](), 0))
+	assert.NotEqual(t, SchemaVersion(reflect.TypeFor[itemV1](), 0), SchemaVersion(reflect.TypeFor[itemV2](), 0))
+	assert.NotEqual(t, SchemaVersion(reflect.TypeFor[itemV1](), 0), SchemaVersion(reflect.TypeFor[itemV1](), 1))
+	assert.NotEqual(t, SchemaVersion(reflect.TypeFor[*itemV1](), 0), SchemaVersion(reflect.TypeFor[[]itemV1](), 0))
+
+	// рекурсивный тип не зацикливает обход
+	assert.NotZero(t, SchemaVersion(reflect.TypeFor[tree](), 0))
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	want := newRecord()
+
+	for _, codec := range []Codec{JSON, MsgPack} {
+		data, err := codec.Marshal(want)
+		require.NoError(t, err)
+
+		var got record
+		require.NoError(t, codec.Unmarshal(data, &got))
+
+		assert.Equal(t, want.ID, got.ID)
+		assert.True(t, want.CreatedAt.Equal(got.CreatedAt))
+		assert.Equal(t, *want.ParentID, *got.ParentID)
+		assert.Equal(t, want.Tags, got.Tags)
+		assert.Equal(t, want.Labels, got.Labels)
+		assert.Empty(t, got.Secret, "fields hidden from JSON are not cached")
+	}
+}
+
+func BenchmarkCodec(b *testing.B) {
+	value := newRecord()
+
+	for _, bc := range []struct {
+		name  string
+		codec Codec
+	}{{"json", JSON}, {"msgpack", MsgPack}} {
+		data, err := bc.codec.Marshal(value)
+		require.NoError(b, err)
+
+		b.Run(bc.name+"/marshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := bc.codec.Marshal(value); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/value")
+		})
+
+		b.Run(bc.name+"/unmarshal", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				var got record
+				if err := bc.codec.Unmarshal(data, &got); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrInspectUnsupported = errors.New("cache store does not support inspection")
+
+// Usage — число ключей пространства и занятая ими память в байтах
+type Usage struct {
+	Keys  int64
+	Bytes int64
+}
+
+// Inspector обходит ключи хранилища по префиксу. Обход не блокирует
+// хранилище, поэтому ключи, записанные во время обхода, могут не попасть в него
+type Inspector interface {
+	Usage(ctx context.Context, prefix string) (Usage, error)
+
+	// DeletePrefix удаляет все ключи с префиксом и возвращает их число
+	DeletePrefix(ctx context.Context, prefix string) (int64, error)
+}
+
+func inspect(store Store) (Inspector, error) {
+	inspector, ok := store.(Inspector)
+	if !ok {
+		return nil, ErrInspectUnsupported
+	}
+	return inspector, nil
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Invalidator рассылает другим репликам ключи, которые нужно выбросить из локального кеша
+type Invalidator interface {
+	Publish(ctx context.Context, key string) error
+
+	// Listen вызывает evict для ключей, опубликованных другими репликами.
+	// Блокируется до отмены ctx
+	Listen(ctx context.Context, evict func(key string)) error
+}
+
+// RedisInvalidator рассылает ключи через Redis pub/sub. Сообщение — "<реплика> <ключ>":
+// собственные сообщения реплика пропускает, её локальный кеш уже обновлён
+type RedisInvalidator struct {
+	client  *redis.Client
+	channel string
+	origin  string
+}
+
+func NewRedisInvalidator(client *redis.Client, channel string) *RedisInvalidator {
+	return &RedisInvalidator{
+		client:  client,
+		channel: channel,
+		origin:  uuid.NewString(),
+	}
+}
+
+func (i *RedisInvalidator) Publish(ctx context.Context, key string) error {
+	return i.client.Publish(ctx, i.channel, i.origin+" "+key).Err()
+}
+
+func (i *RedisInvalidator) Listen(ctx context.Context, evict func(key string)) error {
+	sub := i.client.Subscribe(ctx, i.channel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+
+	// после обрыва соединения go-redis переподписывается сам; пропущенные
+	// сообщения живут в локальном кеше не дольше его TTL
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			origin, key, found := strings.Cut(msg.Payload, " ")
+			if !found || origin == i.origin {
+				continue
+			}
+			evict(key)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package cache
+
+import "strings"
+
+// Префиксы ключей кеша. Все пространства перечислены здесь: по этому списку
+// строится статистика и проверяется префикс при очистке
+const (
+	PrefixUser         = "user:id:"
+	PrefixUserEmail    = "user:email:"
+	PrefixPayment      = "payment:"
+	PrefixSubscription = "subscription:id:"
+	PrefixService      = "service:"
+	PrefixServicePage  = "services:page:"
+	PrefixCategory     = "category:"
+	PrefixOrder        = "order:"
+	PrefixCatalog      = "catalog:"
+	PrefixMetrics      = "metrics:"
+	PrefixRecent       = "recent:"
+	PrefixTag          = "tag:"
+)
+
+// Теги записей кеша. В общем хранилище множество ключей тега лежит
+// под PrefixTag + тег, поэтому теги разных сущностей не должны совпадать
+const (
+	// TagUser + id — все записи о пользователе: по id и по email
+	TagUser = "user:"
+	// TagService + id — сервис и страницы списка, на которых он есть
+	TagService = "service:"
+	// TagServices — все страницы списка сервисов
+	TagServices = "services"
+)
+
+// Prefixes — все зарегистрированные префиксы
+var Prefixes = []string{
+	PrefixUser,
+	PrefixUserEmail,
+	PrefixPayment,
+	PrefixSubscription,
+	PrefixService,
+	PrefixServicePage,
+	PrefixCategory,
+	PrefixOrder,
+	PrefixCatalog,
+	PrefixMetrics,
+	PrefixRecent,
+	PrefixTag,
+}
+
+// IsPrefix сообщает, зарегистрирован ли префикс. Очищать можно только
+// пространство целиком или его часть, поэтому подходит и префикс,
+// который начинается с зарегистрированного
+func IsPrefix(prefix string) bool {
+	for _, p := range Prefixes {
+		if strings.HasPrefix(prefix, p) {
+			return true
+		}
+	}
+	return false
+}
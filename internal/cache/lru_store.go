@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LRUStore хранит значения в памяти процесса. Размер ограничен числом записей:
+// при переполнении вытесняется давно не читанная. Срок жизни записи не больше maxTTL
+type LRUStore struct {
+	mu     sync.Mutex
+	size   int
+	maxTTL time.Duration
+	items  map[string]*list.Element
+	// ключи каждого тега; запись выписывается из тегов при вытеснении
+	tags map[string]map[string]struct{}
+	// спереди — недавно использованные записи
+	order *list.List
+	now   func() time.Time
+	stats tierCounters
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+	tags      []string
+}
+
+// NewLRUStore создаёт хранилище на size записей; нулевой maxTTL не ограничивает срок жизни
+func NewLRUStore(size int, maxTTL time.Duration) *LRUStore {
+	return &LRUStore{
+		size:   size,
+		maxTTL: maxTTL,
+		items:  make(map[string]*list.Element, size),
+		tags:   map[string]map[string]struct{}{},
+		order:  list.New(),
+		now:    time.Now,
+	}
+}
+
+func (s *LRUStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		s.stats.misses.Add(1)
+		return nil, ErrCacheMiss
+	}
+
+	entry := el.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !s.now().Before(entry.expiresAt) {
+		s.remove(el)
+		s.stats.misses.Add(1)
+		return nil, ErrCacheMiss
+	}
+
+	s.order.MoveToFront(el)
+	s.stats.hits.Add(1)
+	return entry.value, nil
+}
+
+func (s *LRUStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if s.size <= 0 {
+		return nil
+	}
+
+	if s.maxTTL > 0 && (ttl <= 0 || ttl > s.maxTTL) {
+		ttl = s.maxTTL
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = s.now().Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		s.untag(entry)
+		entry.tags = tags
+		s.tag(entry)
+		s.order.MoveToFront(el)
+		return nil
+	}
+
+	entry := &lruEntry{key: key, value: value, expiresAt: expiresAt, tags: tags}
+	s.items[key] = s.order.PushFront(entry)
+	s.tag(entry)
+
+	for s.order.Len() > s.size {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+func (s *LRUStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.remove(el)
+	}
+
+	return nil
+}
+
+func (s *LRUStore) Invalidate(ctx context.Context, tags ...string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted []string
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if el, ok := s.items[key]; ok {
+				s.remove(el)
+				deleted = append(deleted, key)
+			}
+		}
+		delete(s.tags, tag)
+	}
+
+	return deleted, nil
+}
+
+// Usage считает память как длину ключа и значения, без накладных расходов
+func (s *LRUStore) Usage(ctx context.Context, prefix string) (Usage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var usage Usage
+	for key, el := range s.items {
+		if strings.HasPrefix(key, prefix) {
+			usage.Keys++
+			usage.Bytes += int64(len(key) + len(el.Value.(*lruEntry).value))
+		}
+	}
+
+	return usage, nil
+}
+
+func (s *LRUStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, el := range s.items {
+		if strings.HasPrefix(key, prefix) {
+			s.remove(el)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// Len возвращает число записей, включая ещё не вытесненные просроченные
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+// Stats отдаёт счётчики как единственный, локальный уровень
+func (s *LRUStore) Stats() Stats {
+	return Stats{Local: s.stats.snapshot()}
+}
+
+func (s *LRUStore) remove(el *list.Element) {
+	entry := el.Value.(*lruEntry)
+	s.order.Remove(el)
+	delete(s.items, entry.key)
+	s.untag(entry)
+}
+
+func (s *LRUStore) tag(entry *lruEntry) {
+	for _, tag := range entry.tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = map[string]struct{}{}
+			s.tags[tag] = keys
+		}
+		keys[entry.key] = struct{}{}
+	}
+}
+
+func (s *LRUStore) untag(entry *lruEntry) {
+	for _, tag := range entry.tags {
+		delete(s.tags[tag], entry.key)
+		if len(s.tags[tag]) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
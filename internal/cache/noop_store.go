@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// NoopStore ничего не хранит: каждое чтение — промах. Используется с CACHE_BACKEND=none
+type NoopStore struct{}
+
+func (NoopStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, ErrCacheMiss
+}
+
+func (NoopStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	return nil
+}
+
+func (NoopStore) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func (NoopStore) Invalidate(ctx context.Context, tags ...string) ([]string, error) {
+	return nil, nil
+}
+
+func (NoopStore) Usage(ctx context.Context, prefix string) (Usage, error) {
+	return Usage{}, nil
+}
+
+func (NoopStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	return 0, nil
+}
+
+func (NoopStore) Stats() Stats {
+	return Stats{}
+}
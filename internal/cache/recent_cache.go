@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Просмотр сервиса пользователем
+type View struct {
+	ServiceID uuid.UUID
+	ViewedAt  time.Time
+}
+
+// RecentlyViewed хранит последние просмотренные пользователем сервисы
+type RecentlyViewed interface {
+	// Add отмечает просмотр; повторный просмотр поднимает сервис наверх
+	Add(ctx context.Context, userID, serviceID uuid.UUID, at time.Time) error
+
+	// List возвращает просмотры, последние первыми
+	List(ctx context.Context, userID uuid.UUID, limit int) ([]View, error)
+
+	Clear(ctx context.Context, userID uuid.UUID) error
+}
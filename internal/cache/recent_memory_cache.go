@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryRecentlyViewed хранит просмотры в памяти процесса: для разработки
+// и одиночной реплики. История пользователя пропадает через ttl после последнего просмотра
+type MemoryRecentlyViewed struct {
+	mu      sync.Mutex
+	views   map[uuid.UUID]*memoryViews
+	limit   int
+	ttl     time.Duration
+	sweepAt time.Time
+	now     func() time.Time
+}
+
+type memoryViews struct {
+	// последние первыми
+	items     []View
+	expiresAt time.Time
+}
+
+func NewMemoryRecentlyViewed(limit int, ttl time.Duration) *MemoryRecentlyViewed {
+	return &MemoryRecentlyViewed{
+		views: map[uuid.UUID]*memoryViews{},
+		limit: limit,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+func (c *MemoryRecentlyViewed) Add(ctx context.Context, userID, serviceID uuid.UUID, at time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictExpired()
+
+	current := c.views[userID]
+	items := make([]View, 0, c.limit)
+	items = append(items, View{ServiceID: serviceID, ViewedAt: at.UTC()})
+	if current != nil {
+		for _, v := range current.items {
+			if v.ServiceID != serviceID && len(items) < c.limit {
+				items = append(items, v)
+			}
+		}
+	}
+
+	c.views[userID] = &memoryViews{items: items, expiresAt: c.now().Add(c.ttl)}
+
+	return nil
+}
+
+func (c *MemoryRecentlyViewed) List(ctx context.Context, userID uuid.UUID, limit int) ([]View, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if limit <= 0 || limit > c.limit {
+		limit = c.limit
+	}
+
+	current, ok := c.views[userID]
+	if !ok || !c.now().Before(current.expiresAt) {
+		return []View{}, nil
+	}
+
+	items := current.items
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return append([]View(nil), items...), nil
+}
+
+func (c *MemoryRecentlyViewed) Clear(ctx context.Context, userID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.views, userID)
+
+	return nil
+}
+
+// evictExpired не даёт истории ушедших пользователей копиться в памяти.
+// Полный обход делается не чаще раза в минуту
+func (c *MemoryRecentlyViewed) evictExpired() {
+	now := c.now()
+	if now.Before(c.sweepAt) {
+		return
+	}
+	c.sweepAt = now.Add(time.Minute)
+
+	for userID, v := range c.views {
+		if !now.Before(v.expiresAt) {
+			delete(c.views, userID)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRecentlyViewed(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	recent := NewMemoryRecentlyViewed(2, time.Hour)
+	recent.now = func() time.Time { return now }
+
+	userID := uuid.New()
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	for i, id := range []uuid.UUID{a, b, a, c} {
+		require.NoError(t, recent.Add(ctx, userID, id, now.Add(time.Duration(i)*time.Minute)))
+	}
+
+	views, err := recent.List(ctx, userID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []View{
+		{ServiceID: c, ViewedAt: now.Add(3 * time.Minute)},
+		{ServiceID: a, ViewedAt: now.Add(2 * time.Minute)},
+	}, views)
+
+	now = now.Add(2 * time.Hour)
+	views, err = recent.List(ctx, userID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, views)
+}
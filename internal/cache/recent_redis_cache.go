@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRecentlyViewed хранит просмотры в sorted set: member — id сервиса,
+// score — время просмотра в миллисекундах. В наборе остаются limit последних
+type RedisRecentlyViewed struct {
+	client *redis.Client
+	limit  int
+	ttl    time.Duration
+}
+
+func NewRedisRecentlyViewed(client *redis.Client, limit int, ttl time.Duration) *RedisRecentlyViewed {
+	return &RedisRecentlyViewed{
+		client: client,
+		limit:  limit,
+		ttl:    ttl,
+	}
+}
+
+func recentKey(userID uuid.UUID) string {
+	return PrefixRecent + userID.String()
+}
+
+func (c *RedisRecentlyViewed) Add(ctx context.Context, userID, serviceID uuid.UUID, at time.Time) error {
+	key := recentKey(userID)
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: serviceID.String()})
+		// ранги по возрастанию score: удаляем всё, кроме limit самых новых
+		pipe.ZRemRangeByRank(ctx, key, 0, int64(-c.limit-1))
+		pipe.Expire(ctx, key, c.ttl)
+		return nil
+	})
+
+	return err
+}
+
+func (c *RedisRecentlyViewed) List(ctx context.Context, userID uuid.UUID, limit int) ([]View, error) {
+	if limit <= 0 || limit > c.limit {
+		limit = c.limit
+	}
+
+	items, err := c.client.ZRevRangeWithScores(ctx, recentKey(userID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]View, 0, len(items))
+	for _, item := range items {
+		member, ok := item.Member.(string)
+		if !ok {
+			continue
+		}
+		id, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		views = append(views, View{ServiceID: id, ViewedAt: time.UnixMilli(int64(item.Score)).UTC()})
+	}
+
+	return views, nil
+}
+
+func (c *RedisRecentlyViewed) Clear(ctx context.Context, userID uuid.UUID) error {
+	return c.client.Del(ctx, recentKey(userID)).Err()
+}
@@ -0,0 +1,207 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// setTaggedScript сохраняет значение и добавляет ключ в множества тегов
+// с ключами PrefixTag + тег. Множество живёт не меньше самого долгого
+// из своих ключей; удалённые и истёкшие ключи остаются в нём до его
+// истечения или Invalidate
+var setTaggedScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+for i = 2, #KEYS do
+	local existed = redis.call('EXISTS', KEYS[i])
+	redis.call('SADD', KEYS[i], KEYS[1])
+	if ttl <= 0 then
+		redis.call('PERSIST', KEYS[i])
+	else
+		local current = redis.call('PTTL', KEYS[i])
+		if existed == 0 or (current >= 0 and current < ttl) then
+			redis.call('PEXPIRE', KEYS[i], ttl)
+		end
+	end
+end
+return 1
+`)
+
+// invalidateScript атомарно забирает ключи тегов и удаляет их вместе с множествами
+var invalidateScript = redis.NewScript(`
+local deleted = {}
+for i = 1, #KEYS do
+	local keys = redis.call('SMEMBERS', KEYS[i])
+	for _, key in ipairs(keys) do
+		redis.call('DEL', key)
+		table.insert(deleted, key)
+	end
+	redis.call('DEL', KEYS[i])
+end
+return deleted
+`)
+
+// Сколько ключей SCAN просматривает за один вызов
+const scanBatch = 500
+
+// RedisStore хранит значения в Redis как есть
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{
+		client: client,
+	}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetWithTTL читает значение и PTTL в одной транзакции, чтобы ключ не истёк между ними
+func (s *RedisStore) GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	var get *redis.StringCmd
+	var pttl *redis.DurationCmd
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil, 0, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// -1 и -2 — ключ бессрочный или уже удалён
+	ttl := pttl.Val()
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	return data, ttl, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if len(tags) == 0 {
+		return s.client.Set(ctx, key, value, ttl).Err()
+	}
+
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, key)
+	for _, tag := range tags {
+		keys = append(keys, PrefixTag+tag)
+	}
+
+	return setTaggedScript.Run(ctx, s.client, keys, value, ttl.Milliseconds()).Err()
+}
+
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
+
+func (s *RedisStore) Invalidate(ctx context.Context, tags ...string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		keys = append(keys, PrefixTag+tag)
+	}
+
+	return invalidateScript.Run(ctx, s.client, keys).StringSlice()
+}
+
+// Usage обходит ключи через SCAN, а не KEYS: обход идёт частями и не
+// останавливает Redis. Память считается по MEMORY USAGE каждого ключа
+func (s *RedisStore) Usage(ctx context.Context, prefix string) (Usage, error) {
+	var usage Usage
+
+	err := s.scan(ctx, prefix, func(keys []string) error {
+		cmds, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.MemoryUsage(ctx, key)
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		for _, cmd := range cmds {
+			n, err := cmd.(*redis.IntCmd).Result()
+			if errors.Is(err, redis.Nil) {
+				// ключ истёк между SCAN и MEMORY USAGE
+				continue
+			}
+			usage.Keys++
+			usage.Bytes += n
+		}
+		return nil
+	})
+
+	return usage, err
+}
+
+func (s *RedisStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var deleted int64
+
+	err := s.scan(ctx, prefix, func(keys []string) error {
+		n, err := s.client.Unlink(ctx, keys...).Result()
+		deleted += n
+		return err
+	})
+
+	return deleted, err
+}
+
+func (s *RedisStore) scan(ctx context.Context, prefix string, fn func(keys []string) error) error {
+	match := globEscaper.Replace(prefix) + "*"
+
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, match, scanBatch).Result()
+		if err != nil {
+			return err
+		}
+
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// globEscaper экранирует спецсимволы шаблона MATCH
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
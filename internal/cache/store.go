@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Store — хранилище сырых значений, поверх которого работает Cache[T]
+type Store interface {
+	// Get возвращает ErrCacheMiss, если ключа нет
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set сохраняет значение и привязывает ключ к тегам: Invalidate по любому
+	// из них удалит ключ. Привязка живёт не меньше самого ключа
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+
+	// Delete не считает ошибкой отсутствие ключа
+	Delete(ctx context.Context, key string) error
+
+	// Invalidate удаляет все ключи, привязанные к тегам, и возвращает их
+	Invalidate(ctx context.Context, tags ...string) ([]string, error)
+}
+
+// Filler отличает заполнение после промаха от изменения значения: источник
+// не менялся, поэтому другим репликам незачем выбрасывать свои копии
+type Filler interface {
+	Fill(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+}
+
+// TTLReader отдаёт значение вместе с оставшимся сроком жизни ключа;
+// 0 — ключ бессрочный или срок неизвестен
+type TTLReader interface {
+	GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, error)
+}
+
+func fill(ctx context.Context, store Store, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if filler, ok := store.(Filler); ok {
+		return filler.Fill(ctx, key, value, ttl, tags...)
+	}
+	return store.Set(ctx, key, value, ttl, tags...)
+}
+
+func getWithTTL(ctx context.Context, store Store, key string) ([]byte, time.Duration, error) {
+	if reader, ok := store.(TTLReader); ok {
+		return reader.GetWithTTL(ctx, key)
+	}
+
+	data, err := store.Get(ctx, key)
+	return data, 0, err
+}
@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Пауза перед повторной подпиской на изменения после сбоя
+const listenRetryDelay = 5 * time.Second
+
+// Сообщение об очистке пространства — префикс с этим окончанием.
+// Ключи кеша на него не заканчиваются
+const prefixWildcard = "*"
+
+// TierStats — счётчики одного уровня кеша
+type TierStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// Stats — счётчики по уровням: локальному (память процесса) и общему (Redis)
+type Stats struct {
+	Local  TierStats
+	Remote TierStats
+}
+
+// StatsProvider отдаёт счётчики попаданий по уровням кеша
+type StatsProvider interface {
+	Stats() Stats
+}
+
+type tierCounters struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func (c *tierCounters) snapshot() TierStats {
+	return TierStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// TieredStore читает сначала из локального хранилища, затем из общего.
+// Запись и удаление идут в оба уровня, а ключ рассылается другим репликам,
+// чтобы они выбросили свою локальную копию. Локальная копия может отставать
+// от общей не дольше localTTL, если сообщение об изменении потерялось
+type TieredStore struct {
+	local       Store
+	remote      Store
+	localTTL    time.Duration
+	invalidator Invalidator
+	logger      *slog.Logger
+
+	localStats  tierCounters
+	remoteStats tierCounters
+}
+
+// NewTieredStore создаёт двухуровневое хранилище; invalidator может быть nil,
+// если реплика одна
+func NewTieredStore(local, remote Store, localTTL time.Duration, invalidator Invalidator, logger *slog.Logger) *TieredStore {
+	return &TieredStore{
+		local:       local,
+		remote:      remote,
+		localTTL:    localTTL,
+		invalidator: invalidator,
+		logger:      logger,
+	}
+}
+
+func (s *TieredStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if data, err := s.local.Get(ctx, key); err == nil {
+		s.localStats.hits.Add(1)
+		return data, nil
+	}
+	s.localStats.misses.Add(1)
+
+	data, ttl, err := getWithTTL(ctx, s.remote, key)
+	if err != nil {
+		if errors.Is(err, ErrCacheMiss) {
+			s.remoteStats.misses.Add(1)
+		}
+		return nil, err
+	}
+	s.remoteStats.hits.Add(1)
+
+	// копия не должна пережить значение в общем хранилище
+	_ = s.local.Set(ctx, key, data, s.capTTL(ttl))
+
+	return data, nil
+}
+
+// Set меняет значение и рассылает ключ другим репликам
+func (s *TieredStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if err := s.Fill(ctx, key, value, ttl, tags...); err != nil {
+		return err
+	}
+
+	s.publish(ctx, key)
+
+	return nil
+}
+
+// Fill записывает значение, загруженное из источника после промаха. Источник
+// не менялся, поэтому другие реплики о нём не оповещаются
+func (s *TieredStore) Fill(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if err := s.remote.Set(ctx, key, value, ttl, tags...); err != nil {
+		// старая локальная копия не должна пережить неудачную запись
+		_ = s.local.Delete(ctx, key)
+		return err
+	}
+
+	_ = s.local.Set(ctx, key, value, s.capTTL(ttl), tags...)
+
+	return nil
+}
+
+// capTTL ограничивает срок локальной копии сроком значения в общем хранилище
+func (s *TieredStore) capTTL(ttl time.Duration) time.Duration {
+	if ttl > 0 && ttl < s.localTTL {
+		return ttl
+	}
+	return s.localTTL
+}
+
+func (s *TieredStore) Delete(ctx context.Context, key string) error {
+	_ = s.local.Delete(ctx, key)
+
+	err := s.remote.Delete(ctx, key)
+
+	// другие реплики выбрасывают копию, даже если общее хранилище не ответило:
+	// удаление из него BreakerStore дошлёт после восстановления
+	s.publish(ctx, key)
+
+	return err
+}
+
+// Invalidate удаляет ключи тегов в общем хранилище и рассылает их другим
+// репликам. Локальный индекс тегов неполон: копии, поднятые из общего
+// хранилища чтением, тегов не знают, поэтому локально удаляются
+// и ключи, которые вернуло общее хранилище
+func (s *TieredStore) Invalidate(ctx context.Context, tags ...string) ([]string, error) {
+	localKeys, _ := s.local.Invalidate(ctx, tags...)
+
+	keys, err := s.remote.Invalidate(ctx, tags...)
+	if err != nil {
+		// полного списка ключей нет, рассылаются хотя бы известные локально
+		for _, key := range localKeys {
+			s.publish(ctx, key)
+		}
+		return nil, err
+	}
+
+	for _, key := range keys {
+		_ = s.local.Delete(ctx, key)
+		s.publish(ctx, key)
+	}
+
+	return keys, nil
+}
+
+// Usage отдаёт занятость общего хранилища: локальное у каждой реплики своё
+func (s *TieredStore) Usage(ctx context.Context, prefix string) (Usage, error) {
+	inspector, err := inspect(s.remote)
+	if err != nil {
+		return Usage{}, err
+	}
+	return inspector.Usage(ctx, prefix)
+}
+
+// DeletePrefix очищает пространство в обоих уровнях и просит другие реплики
+// очистить его у себя
+func (s *TieredStore) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	s.deleteLocalPrefix(ctx, prefix)
+
+	inspector, err := inspect(s.remote)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := inspector.DeletePrefix(ctx, prefix)
+	if err != nil {
+		return deleted, err
+	}
+
+	s.publish(ctx, prefix+prefixWildcard)
+
+	return deleted, nil
+}
+
+func (s *TieredStore) deleteLocalPrefix(ctx context.Context, prefix string) {
+	if local, err := inspect(s.local); err == nil {
+		_, _ = local.DeletePrefix(ctx, prefix)
+	}
+}
+
+func (s *TieredStore) Stats() Stats {
+	return Stats{
+		Local:  s.localStats.snapshot(),
+		Remote: s.remoteStats.snapshot(),
+	}
+}
+
+// Run слушает изменения с других реплик и блокируется до отмены ctx
+func (s *TieredStore) Run(ctx context.Context) {
+	if s.invalidator == nil {
+		return
+	}
+
+	s.logger.Info("cache invalidation listener started")
+
+	for {
+		err := s.invalidator.Listen(ctx, func(key string) {
+			if prefix, ok := strings.CutSuffix(key, prefixWildcard); ok {
+				s.deleteLocalPrefix(ctx, prefix)
+				return
+			}
+			_ = s.local.Delete(ctx, key)
+		})
+		if ctx.Err() != nil {
+			s.logger.Info("cache invalidation listener stopped")
+			return
+		}
+
+		s.logger.Error("cache: invalidation listener failed, retrying", slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("cache invalidation listener stopped")
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (s *TieredStore) publish(ctx context.Context, key string) {
+	if s.invalidator == nil {
+		return
+	}
+
+	if err := s.invalidator.Publish(ctx, key); err != nil {
+		s.logger.Warn("cache: failed to publish invalidation", slog.String("key", key), slog.Any("error", err))
+	}
+}
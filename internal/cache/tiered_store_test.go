@@ -0,0 +1,306 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBus доставляет ключи всем репликам, кроме отправителя, синхронно
+type testBus struct {
+	mu    sync.Mutex
+	peers []*testInvalidator
+}
+
+type testInvalidator struct {
+	bus   *testBus
+	evict func(key string)
+	ready chan struct{}
+}
+
+func (b *testBus) join() *testInvalidator {
+	i := &testInvalidator{bus: b, ready: make(chan struct{})}
+	b.mu.Lock()
+	b.peers = append(b.peers, i)
+	b.mu.Unlock()
+	return i
+}
+
+func (i *testInvalidator) Publish(ctx context.Context, key string) error {
+	i.bus.mu.Lock()
+	defer i.bus.mu.Unlock()
+
+	for _, peer := range i.bus.peers {
+		if peer != i && peer.evict != nil {
+			peer.evict(key)
+		}
+	}
+	return nil
+}
+
+func (i *testInvalidator) Listen(ctx context.Context, evict func(key string)) error {
+	i.bus.mu.Lock()
+	i.evict = evict
+	i.bus.mu.Unlock()
+	close(i.ready)
+
+	<-ctx.Done()
+	return nil
+}
+
+// recordingInvalidator запоминает разосланные ключи
+type recordingInvalidator struct {
+	mu        sync.Mutex
+	published []string
+}
+
+func (i *recordingInvalidator) Publish(ctx context.Context, key string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.published = append(i.published, key)
+	return nil
+}
+
+func (i *recordingInvalidator) Listen(ctx context.Context, evict func(key string)) error {
+	<-ctx.Done()
+	return nil
+}
+
+func newReplica(ctx context.Context, remote Store, bus *testBus) *TieredStore {
+	invalidator := bus.join()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := NewTieredStore(NewLRUStore(10, time.Minute), remote, time.Minute, invalidator, logger)
+
+	go store.Run(ctx)
+	<-invalidator.ready
+
+	return store
+}
+
+func TestTieredStore_CountsPerTier(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	remote := newTestStore()
+	store := newReplica(ctx, remote, &testBus{})
+
+	_, err := store.Get(ctx, "category:1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	remote.values["category:1"] = []byte(`{"name":"Музыка"}`)
+	_, err = store.Get(ctx, "category:1")
+	require.NoError(t, err)
+	_, err = store.Get(ctx, "category:1")
+	require.NoError(t, err)
+
+	assert.Equal(t, Stats{
+		Local:  TierStats{Hits: 1, Misses: 2},
+		Remote: TierStats{Hits: 1, Misses: 1},
+	}, store.Stats())
+}
+
+func TestTieredStore_InvalidatesOtherReplicas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	remote := newTestStore()
+	bus := &testBus{}
+	first := newReplica(ctx, remote, bus)
+	second := newReplica(ctx, remote, bus)
+
+	require.NoError(t, first.Set(ctx, "category:1", []byte(`"old"`), time.Minute))
+	data, err := second.Get(ctx, "category:1")
+	require.NoError(t, err)
+	assert.Equal(t, `"old"`, string(data))
+
+	require.NoError(t, first.Set(ctx, "category:1", []byte(`"new"`), time.Minute))
+	data, err = second.Get(ctx, "category:1")
+	require.NoError(t, err)
+	assert.Equal(t, `"new"`, string(data))
+
+	require.NoError(t, first.Delete(ctx, "category:1"))
+	_, err = second.Get(ctx, "category:1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestTieredStore_DeletePublishesWhenRemoteFails(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	breaker, _ := newTestBreaker()
+	remote := NewBreakerStore(newTestStore(), breaker)
+	bus := &testBus{}
+	writer := newReplica(ctx, remote, bus)
+	reader := newReplica(ctx, remote, bus)
+
+	require.NoError(t, writer.Set(ctx, "category:1", []byte("1"), time.Minute))
+	_, err := reader.Get(ctx, "category:1")
+	require.NoError(t, err)
+
+	breaker.Trip(errors.New("connection refused"))
+	assert.ErrorIs(t, writer.Delete(ctx, "category:1"), ErrCircuitOpen)
+
+	_, err = reader.Get(ctx, "category:1")
+	assert.ErrorIs(t, err, ErrCircuitOpen, "local copy of the other replica is dropped")
+}
+
+func TestTieredStore_FillDoesNotPublish(t *testing.T) {
+	ctx := context.Background()
+	invalidator := &recordingInvalidator{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := NewTieredStore(NewLRUStore(10, time.Minute), newTestStore(), time.Minute, invalidator, logger)
+	c := New[*item](store, "item:", Options{})
+
+	_, err := c.GetOrLoad(ctx, "1", func(ctx context.Context) (*item, error) {
+		return &item{Name: "Музыка"}, nil
+	}, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, invalidator.published, "read-through fill is not a change")
+
+	require.NoError(t, c.Set(ctx, "1", &item{Name: "Кино"}, time.Minute))
+	assert.Equal(t, []string{"item:1"}, invalidator.published)
+}
+
+func TestTieredStore_LocalCopyExpiresWithRemote(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	local := NewLRUStore(10, time.Minute)
+	local.now = func() time.Time { return now }
+	remote := newTestStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := NewTieredStore(local, remote, time.Minute, nil, logger)
+
+	require.NoError(t, remote.Set(ctx, "category:1", []byte("1"), 10*time.Second))
+	_, err := store.Get(ctx, "category:1")
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Second)
+	_, err = local.Get(ctx, "category:1")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestLRUStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUStore(2, 0)
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, store.Set(ctx, "b", []byte("2"), 0))
+	_, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, store.Set(ctx, "c", []byte("3"), 0))
+
+	_, err = store.Get(ctx, "b")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	_, err = store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestLRUStore_Expires(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	store := NewLRUStore(10, time.Minute)
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Set(ctx, "short", []byte("1"), 10*time.Second))
+	require.NoError(t, store.Set(ctx, "long", []byte("2"), time.Hour))
+
+	now = now.Add(30 * time.Second)
+	_, err := store.Get(ctx, "short")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	_, err = store.Get(ctx, "long")
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = store.Get(ctx, "long")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestTieredStore_InvalidateEvictsCopiesReadFromRemote(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	remote := NewLRUStore(10, 0)
+	bus := &testBus{}
+	writer := newReplica(ctx, remote, bus)
+	reader := newReplica(ctx, remote, bus)
+
+	require.NoError(t, writer.Set(ctx, "user:email:a@b.c", []byte("1"), time.Minute, "user:1"))
+
+	// копия читателя поднята из общего хранилища и тегов не знает
+	_, err := reader.Get(ctx, "user:email:a@b.c")
+	require.NoError(t, err)
+
+	keys, err := writer.Invalidate(ctx, "user:1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user:email:a@b.c"}, keys)
+
+	_, err = reader.Get(ctx, "user:email:a@b.c")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	_, err = writer.Get(ctx, "user:email:a@b.c")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestLRUStore_EvictionDropsTags(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUStore(1, 0)
+
+	require.NoError(t, store.Set(ctx, "a", []byte("1"), 0, "t"))
+	require.NoError(t, store.Set(ctx, "b", []byte("2"), 0))
+
+	assert.Empty(t, store.tags)
+
+	keys, err := store.Invalidate(ctx, "t")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestTieredStore_DeletePrefixClearsOtherReplicas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	remote := NewLRUStore(10, 0)
+	bus := &testBus{}
+	writer := newReplica(ctx, remote, bus)
+	reader := newReplica(ctx, remote, bus)
+
+	require.NoError(t, writer.Set(ctx, "category:1", []byte("1"), time.Minute))
+	require.NoError(t, writer.Set(ctx, "category:2", []byte("2"), time.Minute))
+	require.NoError(t, writer.Set(ctx, "service:1", []byte("3"), time.Minute))
+	_, err := reader.Get(ctx, "category:1")
+	require.NoError(t, err)
+
+	usage, err := writer.Usage(ctx, "category:")
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Keys: 2, Bytes: int64(2*len("category:1") + 2)}, usage)
+
+	deleted, err := writer.DeletePrefix(ctx, "category:")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	_, err = reader.Get(ctx, "category:1")
+	assert.ErrorIs(t, err, ErrCacheMiss, "local copy of the other replica is dropped")
+	_, err = reader.Get(ctx, "service:1")
+	assert.NoError(t, err)
+}
+
+func TestIsPrefix(t *testing.T) {
+	assert.True(t, IsPrefix(PrefixUser))
+	assert.True(t, IsPrefix(PrefixMetrics+"mrr:"))
+	assert.False(t, IsPrefix(""))
+	assert.False(t, IsPrefix("user:"))
+	assert.False(t, IsPrefix("sessions:"))
+}
+
+func TestGlobEscaper(t *testing.T) {
+	assert.Equal(t, `user:email:a\*b\?\[c\]\\`, globEscaper.Replace(`user:email:a*b?[c]\`))
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"effective-project/internal/cache"
+	"effective-project/internal/redis"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Канал Redis, по которому реплики сообщают об изменённых ключах
+const cacheInvalidationChannel = "cache:invalidate"
+
+// Автомат защиты Redis: после стольких ошибок подряд кеш отключается на паузу
+const (
+	cacheBreakerThreshold = 5
+	cacheBreakerCooldown  = 10 * time.Second
+)
+
+// CacheBackend — хранилища, выбранные по CACHE_BACKEND
+type CacheBackend struct {
+	Store     cache.Store
+	Stats     cache.StatsProvider
+	Inspector cache.Inspector
+	Recent    cache.RecentlyViewed
+	Codec     cache.Codec
+
+	// Run слушает изменения с других реплик и блокируется до отмены ctx
+	Run func(ctx context.Context)
+}
+
+// SetUpCache выбирает бэкенд кеша по CACHE_BACKEND:
+//   - redis (по умолчанию) — локальный LRU (CACHE_LOCAL_SIZE записей, не дольше
+//     CACHE_LOCAL_TTL) перед Redis; при недоступности Redis чтения идут в БД;
+//   - memory — LRU в памяти процесса, для разработки и одиночной реплики;
+//   - none — без кеша. История просмотров в режимах memory и none хранится в памяти.
+//
+// CACHE_CODEC выбирает кодирование значений: json (по умолчанию) или msgpack
+func SetUpCache(ctx context.Context, recentLimit int, recentTTL time.Duration, logger *slog.Logger) (*CacheBackend, error) {
+	size := 10000
+	if v := os.Getenv("CACHE_LOCAL_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid CACHE_LOCAL_SIZE %q", v)
+		}
+		size = n
+	}
+
+	ttl := 30 * time.Second
+	if v := os.Getenv("CACHE_LOCAL_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid CACHE_LOCAL_TTL %q", v)
+		}
+		ttl = d
+	}
+
+	codec, err := cache.CodecByName(os.Getenv("CACHE_CODEC"))
+	if err != nil {
+		return nil, err
+	}
+
+	switch backend := os.Getenv("CACHE_BACKEND"); backend {
+	case "", "redis":
+		addr := os.Getenv("REDIS_ADDR")
+		if addr == "" {
+			addr = "redis:6379"
+		}
+
+		breaker := cache.NewBreaker(cacheBreakerThreshold, cacheBreakerCooldown, logger)
+
+		client, err := redis.New(ctx, addr)
+		if err != nil {
+			breaker.Trip(err)
+		}
+
+		store := cache.NewTieredStore(
+			cache.NewLRUStore(size, ttl),
+			cache.NewBreakerStore(cache.NewRedisStore(client), breaker),
+			ttl,
+			cache.NewRedisInvalidator(client, cacheInvalidationChannel),
+			logger,
+		)
+
+		logger.Info("cache: redis with local lru", slog.String("addr", addr), slog.Int("size", size), slog.Duration("ttl", ttl))
+		return &CacheBackend{
+			Store:     store,
+			Stats:     store,
+			Inspector: store,
+			Recent:    cache.NewBreakerRecentlyViewed(cache.NewRedisRecentlyViewed(client, recentLimit, recentTTL), breaker),
+			Codec:     codec,
+			Run:       store.Run,
+		}, nil
+
+	case "memory":
+		store := cache.NewLRUStore(size, 0)
+
+		logger.Info("cache: memory", slog.Int("size", size))
+		return &CacheBackend{
+			Store:     store,
+			Stats:     store,
+			Inspector: store,
+			Recent:    cache.NewMemoryRecentlyViewed(recentLimit, recentTTL),
+			Codec:     codec,
+			Run:       func(ctx context.Context) {},
+		}, nil
+
+	case "none":
+		logger.Info("cache: disabled")
+		return &CacheBackend{
+			Store:     cache.NoopStore{},
+			Stats:     cache.NoopStore{},
+			Inspector: cache.NoopStore{},
+			Recent:    cache.NewMemoryRecentlyViewed(recentLimit, recentTTL),
+			Codec:     codec,
+			Run:       func(ctx context.Context) {},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown CACHE_BACKEND %q", backend)
+	}
+}
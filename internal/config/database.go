@@ -4,17 +4,35 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// SetUpDatabaseConnection открывает подключение к Postgres.
+//
+// DB_STATEMENT_TIMEOUT (например, 5s) ограничивает на стороне сервера время
+// одного SQL-запроса; по умолчанию не задан, чтобы не прерывать долгие миграции
 func SetUpDatabaseConnection(logger *slog.Logger) *gorm.DB {
 	if err := godotenv.Load(); err != nil {
 		logger.Warn("No .env file found, using environment variables")
 	}
 
+	// таймаут разбирается до подключения: неверное значение останавливает запуск
+	// понятной ошибкой, а не паникой
+	var statementTimeout time.Duration
+	if v := os.Getenv("DB_STATEMENT_TIMEOUT"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		// Postgres принимает миллисекунды, а 0 отключает таймаут
+		if err != nil || timeout < time.Millisecond {
+			logger.Error("invalid DB_STATEMENT_TIMEOUT: expected a duration of at least 1ms such as 5s", slog.String("value", v))
+			os.Exit(1)
+		}
+		statementTimeout = timeout
+	}
+
 	dbHost := os.Getenv("DB_HOST")
 	dbPort := os.Getenv("DB_PORT")
 	dbUser := os.Getenv("DB_USER")
@@ -27,6 +45,10 @@ func SetUpDatabaseConnection(logger *slog.Logger) *gorm.DB {
 		dbHost, dbUser, dbPass, dbName, dbPort, dbSSL,
 	)
 
+	if statementTimeout > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", statementTimeout.Milliseconds())
+	}
+
 	db, err := gorm.Open(postgres.New(postgres.Config{
 		DSN:                  dsn,
 		PreferSimpleProtocol: true,
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"effective-project/internal/storage"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// SetUpBlobStore выбирает хранилище медиафайлов по BLOB_BACKEND: local (по умолчанию) или s3
+func SetUpBlobStore(logger *slog.Logger) (storage.BlobStore, error) {
+	switch backend := os.Getenv("BLOB_BACKEND"); backend {
+	case "", "local":
+		dir := os.Getenv("MEDIA_DIR")
+		if dir == "" {
+			dir = "./data/media"
+		}
+
+		logger.Info("media storage: local", slog.String("dir", dir))
+		return storage.NewLocalStore(dir)
+
+	case "s3":
+		cfg := storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}
+
+		logger.Info("media storage: s3", slog.String("endpoint", cfg.Endpoint), slog.String("bucket", cfg.Bucket))
+		return storage.NewS3Store(cfg, nil)
+
+	default:
+		return nil, fmt.Errorf("unknown BLOB_BACKEND %q", backend)
+	}
+}
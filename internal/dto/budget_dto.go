@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// DTO для бюджета пользователя
+type BudgetCreateRequest struct {
+	MonthlyLimit int    `json:"monthly_limit" binding:"required,gt=0"`
+	Currency     string `json:"currency" binding:"required,len=3"`
+
+	CategoryID *uuid.UUID `json:"category_id"`
+	ServiceID  *uuid.UUID `json:"service_id"`
+}
+
+type BudgetUpdateRequest struct {
+	MonthlyLimit *int    `json:"monthly_limit" binding:"omitempty,gt=0"`
+	Currency     *string `json:"currency" binding:"omitempty,len=3"`
+
+	CategoryID *uuid.UUID `json:"category_id"`
+	ServiceID  *uuid.UUID `json:"service_id"`
+}
+
+type BudgetStatus struct {
+	models.Budget
+
+	Period  string  `json:"period"`
+	Spent   int     `json:"spent"`
+	Percent float64 `json:"percent"`
+}
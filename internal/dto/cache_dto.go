@@ -0,0 +1,45 @@
+package dto
+
+// Счётчики одного уровня кеша
+type CacheTierStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// Счётчики кеша реплики с момента запуска и занятость общего хранилища
+// по пространствам ключей. Если хранилище недоступно, UsageAvailable ложно,
+// а в Prefixes есть только попадания
+type CacheStats struct {
+	Local          CacheTierStats     `json:"local"`
+	Remote         CacheTierStats     `json:"remote"`
+	UsageAvailable bool               `json:"usage_available"`
+	Prefixes       []CachePrefixStats `json:"prefixes"`
+}
+
+// Занятость и попадания одного пространства ключей кеша
+type CachePrefixStats struct {
+	Prefix      string `json:"prefix"`
+	Keys        int64  `json:"keys"`
+	MemoryBytes int64  `json:"memory_bytes"`
+	CacheTierStats
+}
+
+// Параметры прогрева кеша
+type CacheWarmRequest struct {
+	// Сколько самых активных пользователей загрузить; 0 — значение по умолчанию
+	HotUsers int `json:"hot_users" binding:"omitempty,min=0,max=1000"`
+}
+
+// Сколько записей загружено в кеш при прогреве
+type CacheWarmReport struct {
+	Categories int `json:"categories"`
+	Services   int `json:"services"`
+	Users      int `json:"users"`
+}
+
+// Результат очистки пространства ключей
+type CacheFlushResult struct {
+	Prefix  string `json:"prefix"`
+	Deleted int64  `json:"deleted"`
+}
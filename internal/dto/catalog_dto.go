@@ -0,0 +1,37 @@
+package dto
+
+// Строка импорта/экспорта каталога: сервис в категории. Строка без name
+// описывает только категорию
+type CatalogRow struct {
+	Category string `json:"category"`
+	Name     string `json:"name,omitempty"`
+	Website  string `json:"website,omitempty"`
+	LogoUrl  string `json:"logo_url,omitempty"`
+}
+
+// Действия импорта над строкой
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportSkip   = "skip"
+)
+
+type CatalogImportRow struct {
+	Row      int      `json:"row"`
+	Category string   `json:"category"`
+	Name     string   `json:"name,omitempty"`
+	Action   string   `json:"action,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+type CatalogImportReport struct {
+	DryRun  bool `json:"dry_run"`
+	Applied bool `json:"applied"`
+
+	CategoriesCreated int `json:"categories_created"`
+	ServicesCreated   int `json:"services_created"`
+	ServicesUpdated   int `json:"services_updated"`
+	Invalid           int `json:"invalid"`
+
+	Rows []CatalogImportRow `json:"rows"`
+}
@@ -1,10 +1,61 @@
 package dto
 
+import (
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+)
+
 // DTO для категории сервиса
 type CategoryCreateRequest struct {
-	Name string `json:"name" binding:"required,min=2,max=100"`
+	Name     string     `json:"name" binding:"required,min=2,max=100"`
+	ParentID *uuid.UUID `json:"parent_id"`
 }
 
+// ParentID: пустая строка переносит категорию в корень, nil оставляет на месте
 type CategoryUpdateRequest struct {
-	Name *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Name     *string `json:"name" binding:"omitempty,min=2,max=100"`
+	ParentID *string `json:"parent_id" binding:"omitempty,uuid"`
+}
+
+// Агрегаты по категории: число сервисов и пользователей с активной подпиской.
+// Total* учитывают всё поддерево категории, пользователь считается один раз
+type CategoryCounts struct {
+	CategoryID        uuid.UUID `json:"-"`
+	ServicesCount     int       `json:"services_count"`
+	ActiveSubscribers int       `json:"active_subscribers"`
+
+	TotalServices    int `json:"total_services"`
+	TotalSubscribers int `json:"total_active_subscribers"`
+}
+
+type CategoryWithCounts struct {
+	models.Category
+	CategoryCounts
+}
+
+// Узел дерева категорий; счётчики заполняются только по запросу
+type CategoryNode struct {
+	ID       uuid.UUID  `json:"id"`
+	Name     string     `json:"name"`
+	ParentID *uuid.UUID `json:"parent_id"`
+
+	*CategoryCounts
+
+	Children []*CategoryNode `json:"children"`
+}
+
+// Элемент пути от корня дерева до категории
+type CategoryRef struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type ServiceWithPath struct {
+	models.Service
+	CategoryPath []CategoryRef `json:"category_path"`
+}
+
+type CategoryTranslationRequest struct {
+	Name string `json:"name" binding:"required,min=2,max=100"`
 }
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"effective-project/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DTO для купонов
+type CouponCreateRequest struct {
+	Code string `json:"code" binding:"required,min=3,max=50"`
+
+	DiscountType models.DiscountType `json:"discount_type" binding:"required,oneof=percent fixed"`
+	PercentOff   int                 `json:"percent_off" binding:"omitempty,min=1,max=100"`
+	AmountOff    int                 `json:"amount_off" binding:"omitempty,gt=0"`
+	Currency     string              `json:"currency" binding:"omitempty,len=3"`
+
+	MaxRedemptions int `json:"max_redemptions" binding:"omitempty,min=0"`
+	PerUserLimit   int `json:"per_user_limit" binding:"omitempty,min=0"`
+
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+
+	ServiceIDs  []uuid.UUID `json:"service_ids"`
+	CategoryIDs []uuid.UUID `json:"category_ids"`
+}
+
+type CouponUpdateRequest struct {
+	PercentOff *int    `json:"percent_off" binding:"omitempty,min=1,max=100"`
+	AmountOff  *int    `json:"amount_off" binding:"omitempty,gt=0"`
+	Currency   *string `json:"currency" binding:"omitempty,len=3"`
+
+	MaxRedemptions *int `json:"max_redemptions" binding:"omitempty,min=0"`
+	PerUserLimit   *int `json:"per_user_limit" binding:"omitempty,min=0"`
+
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+
+	ServiceIDs  *[]uuid.UUID `json:"service_ids"`
+	CategoryIDs *[]uuid.UUID `json:"category_ids"`
+}
+
+// Запрос на применение купона к покупке
+type CouponApplication struct {
+	Code      string
+	UserID    uuid.UUID
+	ServiceID uuid.UUID
+	Amount    int
+	Currency  string
+
+	SubscriptionID *uuid.UUID
+	OrderID        *uuid.UUID
+}
+
+// Снимок цены после применения купона
+type PriceSnapshot struct {
+	ListPrice    int        `json:"list_price"`
+	Discount     int        `json:"discount"`
+	Price        int        `json:"price"`
+	CouponID     *uuid.UUID `json:"coupon_id"`
+	RedemptionID *uuid.UUID `json:"redemption_id"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+// DTO для политики повторных списаний
+type DunningPolicyRequest struct {
+	RetryDays []int `json:"retry_days" binding:"required,min=1,max=10,dive,gt=0,lte=90"`
+}
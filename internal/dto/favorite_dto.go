@@ -0,0 +1,12 @@
+package dto
+
+import (
+	"effective-project/internal/models"
+	"time"
+)
+
+// Недавно просмотренный сервис
+type ViewedService struct {
+	models.Service
+	ViewedAt time.Time `json:"viewed_at"`
+}
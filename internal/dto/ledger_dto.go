@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"effective-project/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DTO для журнала платежей
+type RefundRequest struct {
+	Amount int    `json:"amount" binding:"required,gt=0"`
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// Amount со знаком: положительная корректировка увеличивает выручку, отрицательная уменьшает
+type AdjustmentRequest struct {
+	Amount int    `json:"amount" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// Итоги журнала по одному платежу
+type PaymentLedger struct {
+	Charged  int `json:"charged"`
+	Refunded int `json:"refunded"`
+	Adjusted int `json:"adjusted"`
+}
+
+type AccountBalance struct {
+	Account  models.LedgerAccount `json:"account"`
+	Currency string               `json:"currency"`
+	Debit    int                  `json:"debit"`
+	Credit   int                  `json:"credit"`
+	Balance  int                  `json:"balance"`
+}
+
+type PaymentMismatch struct {
+	PaymentID     uuid.UUID            `json:"payment_id"`
+	PaymentStatus models.PaymentStatus `json:"payment_status"`
+	Currency      string               `json:"currency"`
+	Amount        int                  `json:"amount"`
+	Charged       int                  `json:"charged"`
+}
+
+type ReconciliationReport struct {
+	CheckedAt  time.Time         `json:"checked_at"`
+	Balanced   bool              `json:"balanced"`
+	Accounts   []AccountBalance  `json:"accounts"`
+	Mismatches []PaymentMismatch `json:"mismatches"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"effective-project/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DTO для метрик админ-панели
+type MetricsFilter struct {
+	From     time.Time
+	To       time.Time
+	Currency string
+}
+
+type MetricsSubscriptionRow struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+	Price     int        `json:"price"`
+
+	Interval    models.BillingInterval    `json:"interval"`
+	PausedUntil *time.Time                `json:"paused_until"`
+	Status      models.SubscriptionStatus `json:"status"`
+}
+
+type RevenueRow struct {
+	Month       time.Time `json:"month"`
+	Revenue     int       `json:"revenue"`
+	PayingUsers int       `json:"paying_users"`
+}
+
+type RecurringRevenue struct {
+	At  time.Time `json:"at"`
+	MRR int       `json:"mrr"`
+	ARR int       `json:"arr"`
+}
+
+type RecurringRevenuePoint struct {
+	Month string `json:"month"`
+	MRR   int    `json:"mrr"`
+	ARR   int    `json:"arr"`
+}
+
+type SubscriptionMovementPoint struct {
+	Month     string  `json:"month"`
+	New       int     `json:"new"`
+	Churned   int     `json:"churned"`
+	Active    int     `json:"active"`
+	ChurnRate float64 `json:"churn_rate"`
+}
+
+type CohortRetention struct {
+	Cohort    string    `json:"cohort"`
+	Size      int       `json:"size"`
+	Retention []float64 `json:"retention"`
+}
+
+type ARPUPoint struct {
+	Month       string  `json:"month"`
+	Revenue     int     `json:"revenue"`
+	PayingUsers int     `json:"paying_users"`
+	ARPU        float64 `json:"arpu"`
+}
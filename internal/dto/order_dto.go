@@ -6,6 +6,8 @@ type OrderCreateRequest struct {
 	UserID    uuid.UUID `json:"user_id" binding:"required"`
 	ServiceID uuid.UUID `json:"service_id" binding:"required"`
 	IsPaid    bool      `json:"is_paid"`
+
+	CouponCode string `json:"coupon_code" binding:"omitempty,max=50"`
 }
 
 type OrderUpdateRequest struct {
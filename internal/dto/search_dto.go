@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы найденных объектов
+const (
+	SearchTypeCategory = "category"
+	SearchTypeService  = "service"
+)
+
+// SearchQuery — параметры полнотекстового поиска по каталогу
+type SearchQuery struct {
+	Query string
+	Type  string
+	Limit int
+
+	// CategoryID ограничивает поиск поддеревом категории
+	CategoryID *uuid.UUID
+
+	// курсор: оценка, дата создания и id последнего элемента предыдущей страницы
+	LastScore     *float64
+	LastCreatedAt *time.Time
+	LastID        *uuid.UUID
+}
+
+// SearchHit — найденная категория или сервис
+type SearchHit struct {
+	Type       string     `json:"type"`
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Website    string     `json:"website,omitempty"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Score      float64    `json:"score"`
+	Highlight  string     `json:"highlight"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
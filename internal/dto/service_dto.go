@@ -4,19 +4,37 @@ import "github.com/google/uuid"
 
 // DTO для создания и обновления сервиса
 type ServiceCreateRequest struct {
-	Name string `json:"name" binding:"required,min=2,max=100"`
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	Description string `json:"description" binding:"max=1000"`
 
 	CategoryID uuid.UUID `json:"category_id" binding:"required"`
 
+	Price int `json:"price" binding:"min=0"`
+
 	Website string `json:"website" binding:"omitempty,url"`
 	LogoUrl string `json:"logo_url" binding:"omitempty,url"`
 }
 
 type ServiceUpdateRequest struct {
-	Name *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Name        *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
 
 	CategoryID *uuid.UUID `json:"category_id" binding:"omitempty"`
 
+	Price *int `json:"price" binding:"omitempty,min=0"`
+
 	Website *string `json:"website" binding:"omitempty,url"`
 	LogoUrl *string `json:"logo_url" binding:"omitempty,url"`
 }
+
+// Результат загрузки логотипа: ссылки на миниатюры по размеру стороны
+type LogoResponse struct {
+	LogoUrl    string            `json:"logo_url"`
+	Thumbnails map[string]string `json:"thumbnails"`
+}
+
+// Перевод сервиса; пустое описание при выдаче заменяется описанием сервиса
+type ServiceTranslationRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	Description string `json:"description" binding:"max=1000"`
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"effective-project/internal/models"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +13,8 @@ type TotalFilter struct {
 	To          time.Time
 	UserID      uuid.UUID
 	ServiceName string
+	ServiceID   uuid.UUID
+	CategoryID  uuid.UUID
 }
 
 type SubFilter struct {
@@ -30,14 +33,22 @@ type SubscriptionCreateRequest struct {
 	StartDate time.Time  `json:"start_date" binding:"required"`
 	EndDate   *time.Time `json:"end_date"`
 
-	Price int `json:"price" binding:"required,gt=0" gorm:"not null;index"`
+	Price    int                    `json:"price" binding:"required,gt=0" gorm:"not null;index"`
+	Interval models.BillingInterval `json:"interval" binding:"omitempty,oneof=month year"`
+
+	CouponCode string `json:"coupon_code" binding:"omitempty,max=50"`
 }
 
 type SubscriptionUpdateRequest struct {
 	StartDate *time.Time `json:"start_date"`
 	EndDate   *time.Time `json:"end_date"`
 
-	Price *int `json:"price" binding:"required,gt=0" gorm:"not null;index"`
+	Price    *int                    `json:"price" binding:"required,gt=0" gorm:"not null;index"`
+	Interval *models.BillingInterval `json:"interval" binding:"omitempty,oneof=month year"`
+
+	PausedUntil *time.Time `json:"paused_until"`
+	// Resume снимает паузу: null в paused_until неотличим от отсутствующего поля
+	Resume bool `json:"resume" binding:"excluded_with=PausedUntil"`
 }
 
 type SubscriptionRow struct {
@@ -45,6 +56,9 @@ type SubscriptionRow struct {
 	EndDate   *time.Time `json:"end_date"`
 	Price     int        `json:"price"`
 
+	Interval    models.BillingInterval `json:"interval"`
+	PausedUntil *time.Time             `json:"paused_until"`
+
 	ServiceName string `json:"service_name"`
 }
 
@@ -57,3 +71,29 @@ type SubscriptionResponse struct {
 	ServiceID   uuid.UUID  `json:"service_id"`
 	ServiceName string     `json:"service_name"`
 }
+
+type UpcomingSubscriptionRow struct {
+	ID          uuid.UUID              `json:"id"`
+	ServiceID   uuid.UUID              `json:"service_id"`
+	ServiceName string                 `json:"service_name"`
+	StartDate   time.Time              `json:"start_date"`
+	EndDate     *time.Time             `json:"end_date"`
+	PausedUntil *time.Time             `json:"paused_until"`
+	Price       int                    `json:"price"`
+	Interval    models.BillingInterval `json:"interval"`
+}
+
+type UpcomingCharge struct {
+	Date           time.Time `json:"date"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	ServiceID      uuid.UUID `json:"service_id"`
+	ServiceName    string    `json:"service_name"`
+	Amount         int       `json:"amount"`
+}
+
+type UpcomingChargesResponse struct {
+	From  time.Time        `json:"from"`
+	To    time.Time        `json:"to"`
+	Items []UpcomingCharge `json:"items"`
+	Total int              `json:"total"`
+}
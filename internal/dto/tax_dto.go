@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DTO для налоговых правил
+type TaxRuleCreateRequest struct {
+	Country string `json:"country" binding:"required,len=2"`
+	Region  string `json:"region" binding:"omitempty,max=100"`
+	Name    string `json:"name" binding:"required,max=100"`
+
+	Rate      float64 `json:"rate" binding:"gt=0,lte=100"`
+	Inclusive bool    `json:"inclusive"`
+
+	EffectiveFrom time.Time  `json:"effective_from" binding:"required"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+}
+
+type TaxRuleUpdateRequest struct {
+	Name *string `json:"name" binding:"omitempty,max=100"`
+
+	Rate      *float64 `json:"rate" binding:"omitempty,gt=0,lte=100"`
+	Inclusive *bool    `json:"inclusive"`
+
+	EffectiveFrom *time.Time `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+}
+
+// Расчёт налога для суммы покупки
+type TaxItem struct {
+	TaxRuleID uuid.UUID `json:"tax_rule_id"`
+	Name      string    `json:"name"`
+	Rate      float64   `json:"rate"`
+	Inclusive bool      `json:"inclusive"`
+	Taxable   int       `json:"taxable"`
+	Amount    int       `json:"amount"`
+}
+
+type TaxCalculation struct {
+	Country  string    `json:"country"`
+	Region   string    `json:"region"`
+	Currency string    `json:"currency"`
+	Net      int       `json:"net"`
+	Tax      int       `json:"tax"`
+	Gross    int       `json:"gross"`
+	Lines    []TaxItem `json:"lines"`
+}
+
+// Покупка, по которой нужно начислить налог
+type TaxableItem struct {
+	UserID uuid.UUID
+	Amount int
+
+	OrderID        *uuid.UUID
+	SubscriptionID *uuid.UUID
+}
+
+// Отчёт о собранных налогах
+type TaxReportFilter struct {
+	From    time.Time
+	To      time.Time
+	Period  string
+	Country string
+}
+
+type TaxReportRow struct {
+	Period   time.Time `json:"period"`
+	Country  string    `json:"country"`
+	Region   string    `json:"region"`
+	Currency string    `json:"currency"`
+	Taxable  int       `json:"taxable"`
+	Tax      int       `json:"tax"`
+	Lines    int       `json:"lines"`
+}
@@ -19,4 +19,6 @@ type UserUpdateRequest struct {
 
 	FirstName *string `json:"first_name" binding:"omitempty,min=2,max=100"`
 	LastName  *string `json:"last_name" binding:"omitempty,min=2,max=100"`
+
+	BillingAddress *models.BillingAddress `json:"billing_address"`
 }
@@ -0,0 +1,34 @@
+package gateway
+
+import (
+	"context"
+	"effective-project/internal/models"
+	"log/slog"
+)
+
+// Gateway списывает деньги через платёжного провайдера
+type Gateway interface {
+	Charge(ctx context.Context, payment *models.Payment) (models.PaymentStatus, error)
+}
+
+// DeclineGateway используется, пока нет интеграции с провайдером: каждая попытка
+// записывается как неуспешная, а оплату можно подтвердить вручную через PUT /payments/:id
+type DeclineGateway struct {
+	logger *slog.Logger
+}
+
+func NewDeclineGateway(logger *slog.Logger) *DeclineGateway {
+	return &DeclineGateway{
+		logger: logger,
+	}
+}
+
+func (g *DeclineGateway) Charge(ctx context.Context, payment *models.Payment) (models.PaymentStatus, error) {
+	g.logger.InfoContext(ctx, "payment gateway is not configured, charge declined",
+		slog.Any("payment_id", payment.ID),
+		slog.String("provider", payment.Provider),
+		slog.Int("amount", payment.Amount),
+	)
+
+	return models.PaymentFailed, nil
+}
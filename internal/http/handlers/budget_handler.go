@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type BudgetHandler struct {
+	budgetService service.BudgetService
+	logger        *slog.Logger
+}
+
+func NewBudgetHandler(budgetService service.BudgetService, logger *slog.Logger) *BudgetHandler {
+	return &BudgetHandler{
+		budgetService: budgetService,
+		logger:        logger,
+	}
+}
+
+func (h *BudgetHandler) RegisterRoutes(r *gin.RouterGroup) {
+	budgets := r.Group("/me/budgets")
+	budgets.Use(middleware.RequireRole("user", "admin"))
+
+	budgets.POST("", h.Create)
+	budgets.GET("", h.List)
+	budgets.GET("/:id", h.GetByID)
+	budgets.PUT("/:id", h.Update)
+	budgets.DELETE("/:id", h.Delete)
+}
+
+func (h *BudgetHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req dto.BudgetCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("handler.budget.create: invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	budget, err := h.budgetService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.logger.Error("handler.budget.create: failed to create budget", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create budget"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, budget)
+}
+
+func (h *BudgetHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	budgets, err := h.budgetService.List(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("handler.budget.list: failed to list budgets", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list budgets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": budgets})
+}
+
+func (h *BudgetHandler) GetByID(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	budget, err := h.budgetService.GetByID(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		h.writeError(c, "handler.budget.get_by_id", "failed to get budget", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+func (h *BudgetHandler) Update(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req dto.BudgetUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("handler.budget.update: invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	budget, err := h.budgetService.Update(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, "handler.budget.update", "failed to update budget", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+func (h *BudgetHandler) Delete(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.budgetService.Delete(c.Request.Context(), userID, c.Param("id")); err != nil {
+		h.writeError(c, "handler.budget.delete", "failed to delete budget", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BudgetHandler) writeError(c *gin.Context, op, message string, err error) {
+	if errors.Is(err, service.ErrBudgetNotFound) || errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+		return
+	}
+
+	h.logger.Error(op+": "+message, slog.Any("error", err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/service"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CacheHandler struct {
+	cacheService service.CacheService
+	logger       *slog.Logger
+}
+
+func NewCacheHandler(cacheService service.CacheService, logger *slog.Logger) *CacheHandler {
+	return &CacheHandler{
+		cacheService: cacheService,
+		logger:       logger,
+	}
+}
+
+func (h *CacheHandler) RegisterRoutes(r *gin.RouterGroup) {
+	admin := r.Group("/admin/cache")
+	admin.Use(middleware.RequireRole("admin"))
+
+	admin.GET("/stats", h.Stats)
+	admin.POST("/warm", h.Warm)
+	admin.DELETE("", h.Flush)
+}
+
+func (h *CacheHandler) Stats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.cacheService.Stats(c.Request.Context()))
+}
+
+func (h *CacheHandler) Warm(c *gin.Context) {
+	var req dto.CacheWarmRequest
+	// тело необязательно: без него прогрев идёт с параметрами по умолчанию
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.cacheService.Warm(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("handler.cache.warm: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to warm cache"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *CacheHandler) Flush(c *gin.Context) {
+	result, err := h.cacheService.Flush(c.Request.Context(), c.Query("prefix"))
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownCachePrefix) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("handler.cache.flush: failed", slog.Any("error", err))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to flush cache"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
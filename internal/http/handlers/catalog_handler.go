@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/service"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ограничение тела запроса импорта каталога
+const maxCatalogImportSize = 5 << 20
+
+var catalogColumns = []string{"category", "name", "website", "logo_url"}
+
+var errUnsupportedCatalogFormat = errors.New("unsupported format, expected csv or json")
+
+type CatalogHandler struct {
+	catalogService service.CatalogService
+	logger         *slog.Logger
+}
+
+func NewCatalogHandler(catalogService service.CatalogService, logger *slog.Logger) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+		logger:         logger,
+	}
+}
+
+func (h *CatalogHandler) RegisterRoutes(r *gin.RouterGroup) {
+	catalog := r.Group("/admin/catalog")
+	catalog.Use(middleware.RequireRole("admin"))
+
+	catalog.POST("/import", h.Import)
+	catalog.GET("/export", h.Export)
+}
+
+func (h *CatalogHandler) Import(c *gin.Context) {
+	format, err := catalogFormat(c.Query("format"), c.ContentType())
+	if err != nil {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxCatalogImportSize)
+
+	var rows []dto.CatalogRow
+	if format == "csv" {
+		rows, err = readCatalogCSV(body)
+	} else {
+		err = json.NewDecoder(body).Decode(&rows)
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.catalogService.Import(c.Request.Context(), rows, dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCatalogInvalid):
+			c.JSON(http.StatusUnprocessableEntity, report)
+		case errors.Is(err, service.ErrEmptyCatalog):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrCatalogTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("handler.catalog.import: failed", slog.Any("error", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import catalog"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *CatalogHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errUnsupportedCatalogFormat.Error()})
+		return
+	}
+
+	rows, err := h.catalogService.Export(c.Request.Context())
+	if err != nil {
+		h.logger.Error("handler.catalog.export: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export catalog"})
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, rows)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="catalog.csv"`)
+	c.Status(http.StatusOK)
+	if err := writeCatalogCSV(c.Writer, rows); err != nil {
+		h.logger.Warn("handler.catalog.export: failed to write csv", slog.Any("error", err))
+	}
+}
+
+// catalogFormat выбирает формат по параметру format, иначе по Content-Type
+func catalogFormat(param, contentType string) (string, error) {
+	switch {
+	case param == "csv" || param == "json":
+		return param, nil
+	case param != "":
+		return "", errUnsupportedCatalogFormat
+	case strings.Contains(contentType, "csv"):
+		return "csv", nil
+	case strings.Contains(contentType, "json"):
+		return "json", nil
+	}
+	return "", errUnsupportedCatalogFormat
+}
+
+// readCatalogCSV читает CSV с заголовком; порядок колонок произвольный,
+// обязательна только category
+func readCatalogCSV(r io.Reader) ([]dto.CatalogRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv header is required")
+		}
+		return nil, err
+	}
+
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		index[name] = i
+	}
+	if _, ok := index["category"]; !ok {
+		return nil, errors.New("csv header must contain category column")
+	}
+
+	column := func(record []string, name string) string {
+		i, ok := index[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	var rows []dto.CatalogRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, dto.CatalogRow{
+			Category: column(record, "category"),
+			Name:     column(record, "name"),
+			Website:  column(record, "website"),
+			LogoUrl:  column(record, "logo_url"),
+		})
+	}
+
+	return rows, nil
+}
+
+func writeCatalogCSV(w io.Writer, rows []dto.CatalogRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(catalogColumns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write([]string{row.Category, row.Name, row.Website, row.LogoUrl}); err != nil {
+			return fmt.Errorf("write row: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
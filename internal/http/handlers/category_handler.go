@@ -3,10 +3,14 @@ package handlers
 import (
 	"effective-project/internal/dto"
 	"effective-project/internal/http/middleware"
+	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"effective-project/internal/service"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,22 +18,37 @@ import (
 )
 
 type CategoryHandler struct {
-	categoryService service.CategoryService
-	logger          *slog.Logger
+	categoryService    service.CategoryService
+	catalogService     service.CatalogService
+	translationService service.TranslationService
+	favoriteService    service.FavoriteService
+	logger             *slog.Logger
 }
 
-func NewCategoryHandler(categoryService service.CategoryService, logger *slog.Logger) *CategoryHandler {
+func NewCategoryHandler(
+	categoryService service.CategoryService,
+	catalogService service.CatalogService,
+	translationService service.TranslationService,
+	favoriteService service.FavoriteService,
+	logger *slog.Logger,
+) *CategoryHandler {
 	return &CategoryHandler{
-		categoryService: categoryService,
-		logger:          logger,
+		categoryService:    categoryService,
+		catalogService:     catalogService,
+		translationService: translationService,
+		favoriteService:    favoriteService,
+		logger:             logger,
 	}
 }
 
 func (h *CategoryHandler) RegisterRoutes(r *gin.RouterGroup) {
 	categories := r.Group("/categories")
+	categories.Use(middleware.Locale(h.translationService.Locales()))
 	// Public routes
 	categories.GET("", h.List)
+	categories.GET("/tree", h.Tree)
 	categories.GET("/:id", h.GetByID)
+	categories.GET("/:id/services", h.ListServices)
 
 	admin := categories.Group("")
 	admin.Use(middleware.RequireRole("admin"))
@@ -48,8 +67,12 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 		return
 	}
 
-	category, err := h.categoryService.Create(&req)
+	category, err := h.categoryService.Create(c.Request.Context(), &req)
 	if err != nil {
+		if errors.Is(err, service.ErrParentCategoryNotFound) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("handlers.category.create: failed to create category", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create category"})
 		return
@@ -103,6 +126,12 @@ func (h *CategoryHandler) List(c *gin.Context) {
 		return
 	}
 
+	if err := h.translationService.LocalizeCategories(ctx, currentLocale(c), categories); err != nil {
+		h.logger.Error("handlers.category.list: failed to localize categories", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list categories"})
+		return
+	}
+
 	type cursor struct {
 		CreatedAt time.Time `json:"created_at"`
 		ID        uuid.UUID `json:"id"`
@@ -117,6 +146,21 @@ func (h *CategoryHandler) List(c *gin.Context) {
 		}
 	}
 
+	if withCounts(c) {
+		items, err := h.catalogService.WithCounts(ctx, categories)
+		if err != nil {
+			h.logger.Error("handlers.category.list: failed to count services", slog.Any("error", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list categories"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"items":       items,
+			"next_cursor": nextCursor,
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"items":       categories,
 		"next_cursor": nextCursor,
@@ -126,16 +170,89 @@ func (h *CategoryHandler) List(c *gin.Context) {
 func (h *CategoryHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	category, err := h.categoryService.GetByID(id)
+	category, err := h.categoryService.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error("handlers.category.getByID: failed to get category", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get category"})
 		return
 	}
 
+	localized := []models.Category{*category}
+	if err := h.translationService.LocalizeCategories(c.Request.Context(), currentLocale(c), localized); err != nil {
+		h.logger.Error("handlers.category.getByID: failed to localize category", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get category"})
+		return
+	}
+	category = &localized[0]
+
+	if withCounts(c) {
+		items, err := h.catalogService.WithCounts(c.Request.Context(), localized)
+		if err != nil {
+			h.logger.Error("handlers.category.getByID: failed to count services", slog.Any("error", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get category"})
+			return
+		}
+
+		c.JSON(http.StatusOK, items[0])
+		return
+	}
+
 	c.JSON(http.StatusOK, category)
 }
 
+func (h *CategoryHandler) Tree(c *gin.Context) {
+	tree, err := h.catalogService.Tree(c.Request.Context(), withCounts(c))
+	if err != nil {
+		h.logger.Error("handlers.category.tree: failed to build tree", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get categories"})
+		return
+	}
+
+	if err := h.translationService.LocalizeTree(c.Request.Context(), currentLocale(c), tree); err != nil {
+		h.logger.Error("handlers.category.tree: failed to localize tree", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": tree})
+}
+
+func (h *CategoryHandler) ListServices(c *gin.Context) {
+	q, err := query.Parse(c.Request.URL.Query(), query.Services)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !favoriteFilter(c, h.favoriteService, q, false, h.logger) {
+		return
+	}
+
+	subtree := c.Query("subtree") == "true"
+
+	services, err := h.catalogService.CategoryServices(c.Request.Context(), c.Param("id"), q, subtree)
+	if err != nil {
+		if errors.Is(err, service.ErrCategoryNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+			return
+		}
+		h.logger.Error("handlers.category.list_services: failed to list services", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list services"})
+		return
+	}
+
+	if err := h.translationService.LocalizeServices(c.Request.Context(), currentLocale(c), services); err != nil {
+		h.logger.Error("handlers.category.list_services: failed to localize services", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list services"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       services,
+		"next_cursor": query.NextCursor(q, services),
+	})
+}
+
 func (h *CategoryHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 
@@ -146,8 +263,12 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 		return
 	}
 
-	category, err := h.categoryService.Update(id, &req)
+	category, err := h.categoryService.Update(c.Request.Context(), id, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrParentCategoryNotFound) || errors.Is(err, service.ErrCategoryCycle) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("handlers.category.update: failed to update category", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update category"})
 		return
@@ -159,7 +280,18 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 func (h *CategoryHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.categoryService.Delete(id); err != nil {
+	// подкатегории переносятся к родителю только по явному запросу
+	reparent := c.Query("children") == "reparent"
+
+	if err := h.categoryService.Delete(c.Request.Context(), id, reparent); err != nil {
+		switch {
+		case errors.Is(err, service.ErrCategoryHasChildren):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, service.ErrCategoryNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+			return
+		}
 		h.logger.Error("handlers.category.delete: failed to delete category", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete category"})
 		return
@@ -167,3 +299,13 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// withCounts — запрошены ли агрегаты: ?with=counts
+func withCounts(c *gin.Context) bool {
+	for _, v := range strings.Split(c.Query("with"), ",") {
+		if strings.TrimSpace(v) == "counts" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"effective-project/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// currentUserID достаёт ID пользователя, который AuthMiddleware положил в context
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	idVal, exists := c.Get("userID")
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, ok := idVal.(uuid.UUID)
+	return userID, ok
+}
+
+// isAdmin проверяет роль, которую AuthMiddleware положил в context
+func isAdmin(c *gin.Context) bool {
+	return c.GetString("userRole") == string(models.RoleAdmin)
+}
+
+// actsAs проверяет, что вызывающий — сам userID или админ. Лимиты купона на
+// пользователя считаются по этому ID, поэтому брать его из тела запроса на веру нельзя
+func actsAs(c *gin.Context, userID uuid.UUID) bool {
+	if isAdmin(c) {
+		return true
+	}
+
+	callerID, ok := currentUserID(c)
+	return ok && callerID == userID
+}
+
+// currentLocale достаёт язык ответа, который выбрал middleware.Locale
+func currentLocale(c *gin.Context) string {
+	return c.GetString("locale")
+}
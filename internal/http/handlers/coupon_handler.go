@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CouponHandler struct {
+	couponService service.CouponService
+	logger        *slog.Logger
+}
+
+func NewCouponHandler(couponService service.CouponService, logger *slog.Logger) *CouponHandler {
+	return &CouponHandler{
+		couponService: couponService,
+		logger:        logger,
+	}
+}
+
+func (h *CouponHandler) RegisterRoutes(r *gin.RouterGroup) {
+	coupons := r.Group("/admin/coupons")
+	coupons.Use(middleware.RequireRole("admin"))
+
+	coupons.POST("", h.Create)
+	coupons.GET("", h.List)
+	coupons.GET("/:id", h.GetByID)
+	coupons.PUT("/:id", h.Update)
+	coupons.DELETE("/:id", h.Delete)
+}
+
+func (h *CouponHandler) Create(c *gin.Context) {
+	var req dto.CouponCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("handler.coupon.create: invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	coupon, err := h.couponService.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, "handler.coupon.create", "failed to create coupon", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, coupon)
+}
+
+func (h *CouponHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	var (
+		lastCreatedAt *time.Time
+		lastID        *uuid.UUID
+	)
+
+	if v := c.Query("created_at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid created_at"})
+			return
+		}
+		lastCreatedAt = &t
+	}
+
+	if v := c.Query("id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		lastID = &uid
+	}
+
+	if (lastCreatedAt == nil) != (lastID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "created_at and id must be used together",
+		})
+		return
+	}
+
+	coupons, err := h.couponService.List(ctx, limit, lastCreatedAt, lastID)
+	if err != nil {
+		h.logger.Error("handler.coupon.list: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list coupons"})
+		return
+	}
+
+	type cursor struct {
+		CreatedAt time.Time `json:"created_at"`
+		ID        uuid.UUID `json:"id"`
+	}
+
+	var nextCursor *cursor
+	if len(coupons) == limit {
+		last := coupons[len(coupons)-1]
+		nextCursor = &cursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       coupons,
+		"next_cursor": nextCursor,
+	})
+}
+
+func (h *CouponHandler) GetByID(c *gin.Context) {
+	coupon, err := h.couponService.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.writeError(c, "handler.coupon.get_by_id", "failed to get coupon", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, coupon)
+}
+
+func (h *CouponHandler) Update(c *gin.Context) {
+	var req dto.CouponUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("handler.coupon.update: invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	coupon, err := h.couponService.Update(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, "handler.coupon.update", "failed to update coupon", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, coupon)
+}
+
+func (h *CouponHandler) Delete(c *gin.Context) {
+	if err := h.couponService.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.writeError(c, "handler.coupon.delete", "failed to delete coupon", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CouponHandler) writeError(c *gin.Context, op, message string, err error) {
+	if errors.Is(err, service.ErrCouponNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "coupon not found"})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidCoupon) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Error(op+": "+message, slog.Any("error", err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// couponError сообщает клиенту, почему промокод не удалось применить к покупке
+func couponError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrCouponNotFound):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid coupon code"})
+	case errors.Is(err, service.ErrCouponExpired),
+		errors.Is(err, service.ErrCouponNotApplicable),
+		errors.Is(err, service.ErrCouponCurrency),
+		errors.Is(err, service.ErrCouponExhausted),
+		errors.Is(err, service.ErrCouponUserLimit):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
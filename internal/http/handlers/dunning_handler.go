@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/models"
+	"effective-project/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DunningHandler struct {
+	dunningService service.DunningService
+	logger         *slog.Logger
+}
+
+func NewDunningHandler(dunningService service.DunningService, logger *slog.Logger) *DunningHandler {
+	return &DunningHandler{
+		dunningService: dunningService,
+		logger:         logger,
+	}
+}
+
+func (h *DunningHandler) RegisterRoutes(r *gin.RouterGroup) {
+	dunning := r.Group("/admin/dunning")
+	dunning.Use(middleware.RequireRole("admin"))
+
+	dunning.GET("/policy", h.GetPolicy)
+	dunning.PUT("/policy", h.UpdatePolicy)
+	dunning.GET("/cases", h.ListCases)
+}
+
+func (h *DunningHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.dunningService.GetPolicy(c.Request.Context())
+	if err != nil {
+		h.logger.Error("handler.dunning.get_policy: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get dunning policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *DunningHandler) UpdatePolicy(c *gin.Context) {
+	var req dto.DunningPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("handler.dunning.update_policy: invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	policy, err := h.dunningService.UpdatePolicy(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDunningPolicy) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("handler.dunning.update_policy: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update dunning policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *DunningHandler) ListCases(c *gin.Context) {
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	status := models.DunningStatus(c.Query("status"))
+	switch status {
+	case "", models.DunningOpen, models.DunningRecovered, models.DunningCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	cases, err := h.dunningService.ListCases(c.Request.Context(), status, limit)
+	if err != nil {
+		h.logger.Error("handler.dunning.list_cases: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list dunning cases"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": cases})
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/models"
+	"effective-project/internal/query"
+	"effective-project/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FavoriteHandler struct {
+	favoriteService    service.FavoriteService
+	recentService      service.RecentService
+	serviceService     service.ServiceService
+	translationService service.TranslationService
+	logger             *slog.Logger
+}
+
+func NewFavoriteHandler(
+	favoriteService service.FavoriteService,
+	recentService service.RecentService,
+	serviceService service.ServiceService,
+	translationService service.TranslationService,
+	logger *slog.Logger,
+) *FavoriteHandler {
+	return &FavoriteHandler{
+		favoriteService:    favoriteService,
+		recentService:      recentService,
+		serviceService:     serviceService,
+		translationService: translationService,
+		logger:             logger,
+	}
+}
+
+func (h *FavoriteHandler) RegisterRoutes(r *gin.RouterGroup) {
+	me := r.Group("/me")
+	me.Use(middleware.RequireRole("user", "admin"))
+	me.Use(middleware.Locale(h.translationService.Locales()))
+
+	me.GET("/favorites", h.ListFavorites)
+	me.PUT("/favorites/:id", h.AddFavorite)
+	me.DELETE("/favorites/:id", h.RemoveFavorite)
+
+	me.GET("/recent", h.ListRecent)
+	me.DELETE("/recent", h.ClearRecent)
+}
+
+func (h *FavoriteHandler) ListFavorites(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	q, err := query.Parse(c.Request.URL.Query(), query.Services)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !favoriteFilter(c, h.favoriteService, q, true, h.logger) {
+		return
+	}
+
+	services, err := h.serviceService.List(ctx, q)
+	if err == nil {
+		err = h.translationService.LocalizeServices(ctx, currentLocale(c), services)
+	}
+	if err != nil {
+		h.logger.Error("handler.favorite.list: failed to list favorites", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list favorites"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       services,
+		"next_cursor": query.NextCursor(q, services),
+	})
+}
+
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.favoriteService.Add(c.Request.Context(), userID, c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, service.ErrServiceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+		case errors.Is(err, service.ErrTooManyFavorites):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("handler.favorite.add: failed", slog.Any("error", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add favorite"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.favoriteService.Remove(c.Request.Context(), userID, c.Param("id")); err != nil {
+		if errors.Is(err, service.ErrServiceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+			return
+		}
+		h.logger.Error("handler.favorite.remove: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove favorite"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *FavoriteHandler) ListRecent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit := service.RecentlyViewedLimit
+	if v := c.Query("limit"); v != "" {
+		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= service.RecentlyViewedLimit {
+			limit = l
+		}
+	}
+
+	ctx := c.Request.Context()
+
+	items, err := h.recentService.List(ctx, userID, limit)
+	if err == nil {
+		services := make([]models.Service, len(items))
+		for i := range items {
+			services[i] = items[i].Service
+		}
+		err = h.translationService.LocalizeServices(ctx, currentLocale(c), services)
+		for i := range items {
+			items[i].Service = services[i]
+		}
+	}
+	if err != nil {
+		h.logger.Error("handler.favorite.list_recent: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list recently viewed services"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+func (h *FavoriteHandler) ClearRecent(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.recentService.Clear(c.Request.Context(), userID); err != nil {
+		h.logger.Error("handler.favorite.clear_recent: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to clear recently viewed services"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// favoriteFilter ограничивает выборку сервисов избранным текущего пользователя,
+// если force или передан ?favorite=true. Возвращает false, если ответ уже отправлен
+func favoriteFilter(
+	c *gin.Context,
+	favorites service.FavoriteService,
+	q *query.List,
+	force bool,
+	logger *slog.Logger,
+) bool {
+	if !force && c.Query("favorite") != "true" {
+		return true
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "favorite=true requires authorization"})
+		return false
+	}
+
+	ids, err := favorites.ServiceIDs(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error("handler.favorite_filter: failed to get favorites", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get favorites"})
+		return false
+	}
+
+	values := make([]any, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, id)
+	}
+	q.Filters = append(q.Filters, query.Filter{Field: "id", Op: query.In, Value: values})
+
+	return true
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LedgerHandler struct {
+	ledgerService service.LedgerService
+	logger        *slog.Logger
+}
+
+func NewLedgerHandler(ledgerService service.LedgerService, logger *slog.Logger) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+		logger:        logger,
+	}
+}
+
+func (h *LedgerHandler) RegisterRoutes(r *gin.RouterGroup) {
+	admin := r.Group("")
+	admin.Use(middleware.RequireRole("admin"))
+
+	admin.POST("/payments/:id/refunds", h.Refund)
+	admin.POST("/payments/:id/adjustments", h.Adjust)
+	admin.GET("/payments/:id/ledger", h.Entries)
+	admin.GET("/admin/ledger/reconciliation", h.Reconcile)
+}
+
+func (h *LedgerHandler) Refund(c *gin.Context) {
+	var req dto.RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("handler.ledger.refund: invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	entries, err := h.ledgerService.Refund(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, "handler.ledger.refund", "failed to refund payment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"entries": entries})
+}
+
+func (h *LedgerHandler) Adjust(c *gin.Context) {
+	var req dto.AdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("handler.ledger.adjust: invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	entries, err := h.ledgerService.Adjust(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, "handler.ledger.adjust", "failed to adjust payment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"entries": entries})
+}
+
+func (h *LedgerHandler) Entries(c *gin.Context) {
+	entries, err := h.ledgerService.Entries(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.writeError(c, "handler.ledger.entries", "failed to get ledger entries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": entries})
+}
+
+func (h *LedgerHandler) Reconcile(c *gin.Context) {
+	report, err := h.ledgerService.Reconcile(c.Request.Context())
+	if err != nil {
+		h.writeError(c, "handler.ledger.reconcile", "failed to reconcile ledger", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *LedgerHandler) writeError(c *gin.Context, op, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrPaymentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "payment not found"})
+		return
+	case errors.Is(err, service.ErrPaymentNotSucceeded),
+		errors.Is(err, service.ErrRefundExceedsPayment):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Error(op+": "+message, slog.Any("error", err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
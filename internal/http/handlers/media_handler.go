@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/media"
+	"effective-project/internal/service"
+	"effective-project/internal/storage"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Запас на заголовки multipart сверх размера самого файла
+const multipartOverhead = 64 << 10
+
+type MediaHandler struct {
+	logoService service.LogoService
+	logger      *slog.Logger
+}
+
+func NewMediaHandler(logoService service.LogoService, logger *slog.Logger) *MediaHandler {
+	return &MediaHandler{
+		logoService: logoService,
+		logger:      logger,
+	}
+}
+
+func (h *MediaHandler) RegisterRoutes(r *gin.RouterGroup) {
+	// Public routes
+	r.GET("/media/*key", h.Serve)
+
+	admin := r.Group("/services")
+	admin.Use(middleware.RequireRole("admin"))
+
+	// Admin routes
+	admin.POST("/:id/logo", h.UploadLogo)
+}
+
+func (h *MediaHandler) UploadLogo(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxLogoSize+multipartOverhead)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrLogoTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if file.Size > service.MaxLogoSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrLogoTooLarge.Error()})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		h.logger.Error("handler.media.upload_logo: failed to open file", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid file"})
+		return
+	}
+	defer f.Close()
+
+	logo, err := h.logoService.Upload(c.Request.Context(), c.Param("id"), f)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrServiceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+		case errors.Is(err, service.ErrLogoTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, media.ErrUnsupportedImage):
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		case errors.Is(err, media.ErrImageDimensions):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("handler.media.upload_logo: failed", slog.Any("error", err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to upload logo"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, logo)
+}
+
+func (h *MediaHandler) Serve(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	body, info, err := h.logoService.Open(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		h.logger.Error("handler.media.serve: failed to open file", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get file"})
+		return
+	}
+	defer body.Close()
+
+	// ключи медиа содержат хеш содержимого и никогда не перезаписываются
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if info.ETag != "" {
+		c.Header("ETag", info.ETag)
+		if match := c.GetHeader("If-None-Match"); match != "" && match == info.ETag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	if !info.ModTime.IsZero() {
+		c.Header("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	if info.Size >= 0 {
+		c.Header("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+
+	c.Header("Content-Type", info.ContentType)
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, body); err != nil {
+		h.logger.Warn("handler.media.serve: failed to write file", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetricsHandler struct {
+	metricsService service.MetricsService
+	logger         *slog.Logger
+}
+
+func NewMetricsHandler(metricsService service.MetricsService, logger *slog.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		metricsService: metricsService,
+		logger:         logger,
+	}
+}
+
+func (h *MetricsHandler) RegisterRoutes(r *gin.RouterGroup) {
+	metrics := r.Group("/admin/metrics")
+	metrics.Use(middleware.RequireRole("admin"))
+
+	// Admin routes
+	metrics.GET("/mrr", h.RecurringRevenue)
+	metrics.GET("/mrr/series", h.RecurringRevenueSeries)
+	metrics.GET("/subscriptions", h.SubscriptionMovement)
+	metrics.GET("/retention", h.CohortRetention)
+	metrics.GET("/arpu", h.ARPU)
+}
+
+func (h *MetricsHandler) RecurringRevenue(c *gin.Context) {
+	at := time.Now().UTC()
+	if v := c.Query("at"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid at"})
+			return
+		}
+		at = t
+	}
+
+	revenue, err := h.metricsService.RecurringRevenue(c.Request.Context(), at)
+	if err != nil {
+		h.logger.Error("handler.metrics.mrr: failed to calculate mrr", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to calculate mrr"})
+		return
+	}
+
+	c.JSON(http.StatusOK, revenue)
+}
+
+func (h *MetricsHandler) RecurringRevenueSeries(c *gin.Context) {
+	f, ok := h.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	points, err := h.metricsService.RecurringRevenueSeries(c.Request.Context(), f)
+	if err != nil {
+		h.writeError(c, "handler.metrics.mrr_series", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"series": points})
+}
+
+func (h *MetricsHandler) SubscriptionMovement(c *gin.Context) {
+	f, ok := h.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	points, err := h.metricsService.SubscriptionMovement(c.Request.Context(), f)
+	if err != nil {
+		h.writeError(c, "handler.metrics.subscriptions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"series": points})
+}
+
+func (h *MetricsHandler) CohortRetention(c *gin.Context) {
+	f, ok := h.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	cohorts, err := h.metricsService.CohortRetention(c.Request.Context(), f)
+	if err != nil {
+		h.writeError(c, "handler.metrics.retention", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cohorts": cohorts})
+}
+
+func (h *MetricsHandler) ARPU(c *gin.Context) {
+	f, ok := h.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	points, err := h.metricsService.ARPU(c.Request.Context(), f)
+	if err != nil {
+		h.writeError(c, "handler.metrics.arpu", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"currency": f.Currency, "series": points})
+}
+
+func (h *MetricsHandler) parseFilter(c *gin.Context) (dto.MetricsFilter, bool) {
+	from, err := parseMonth(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+		return dto.MetricsFilter{}, false
+	}
+
+	to, err := parseMonth(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+		return dto.MetricsFilter{}, false
+	}
+
+	currency := strings.ToUpper(c.DefaultQuery("currency", "RUB"))
+	if len(currency) != 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency"})
+		return dto.MetricsFilter{}, false
+	}
+
+	return dto.MetricsFilter{From: from, To: to, Currency: currency}, true
+}
+
+func (h *MetricsHandler) writeError(c *gin.Context, op string, err error) {
+	if errors.Is(err, service.ErrInvalidPeriod) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Error(op+": failed to calculate metrics", slog.Any("error", err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to calculate metrics"})
+}
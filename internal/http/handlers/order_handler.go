@@ -2,12 +2,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
 	"effective-project/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"log/slog"
 )
 
@@ -28,9 +31,12 @@ func NewOrderHandler(
 
 // RegisterRoutes регистрирует роуты в gin.Engine или gin.RouterGroup
 func (h *OrderHandler) RegisterRoutes(r *gin.RouterGroup) {
-	r.POST("/orders", h.Create)
-	r.GET("/orders/:id", h.GetByID)
-	r.PUT("/orders/:id", h.Update)
+	orders := r.Group("/orders")
+	orders.Use(middleware.RequireRole("user", "admin"))
+
+	orders.POST("", h.Create)
+	orders.GET("/:id", h.GetByID)
+	orders.PUT("/:id", h.Update)
 }
 
 func (h *OrderHandler) Create(c *gin.Context) {
@@ -41,8 +47,21 @@ func (h *OrderHandler) Create(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.Create(req)
+	// заказ оформляется только на себя; отметить его оплаченным может только админ
+	if !actsAs(c, req.UserID) || (req.IsPaid && !isAdmin(c)) {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	order, err := h.orderService.Create(c.Request.Context(), req)
 	if err != nil {
+		if couponError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrServiceNotFound) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
 		h.logger.Error("handlers.order.create: failed to create order", slog.Any("error", err))
 		c.AbortWithStatus(http.StatusInternalServerError)
 		return
@@ -54,13 +73,23 @@ func (h *OrderHandler) Create(c *gin.Context) {
 func (h *OrderHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	order, err := h.orderService.GetByID(id)
+	order, err := h.orderService.GetByID(c.Request.Context(), id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
 		h.logger.Error("handlers.order.get_by_id: failed to get order", slog.Any("error", err))
 		c.AbortWithStatus(http.StatusInternalServerError)
 		return
 	}
 
+	// чужой заказ не отличается от несуществующего
+	if !actsAs(c, order.UserID) {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
 	c.JSON(http.StatusOK, order)
 }
 
@@ -74,7 +103,27 @@ func (h *OrderHandler) Update(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.Update(id, req)
+	if req.IsPaid != nil && !isAdmin(c) {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	existing, err := h.orderService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		h.logger.Error("handlers.order.update: failed to get order", slog.Any("error", err))
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	if !actsAs(c, existing.UserID) {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	order, err := h.orderService.Update(c.Request.Context(), id, req)
 	if err != nil {
 		h.logger.Error("handlers.order.update: failed to update order", slog.Any("error", err))
 		c.AbortWithStatus(http.StatusInternalServerError)
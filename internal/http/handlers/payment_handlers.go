@@ -2,14 +2,13 @@ package handlers
 
 import (
 	"effective-project/internal/dto"
+	"effective-project/internal/query"
 	"effective-project/internal/service"
+	"errors"
 	"log/slog"
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type PaymentHandlers struct {
@@ -42,7 +41,7 @@ func (h *PaymentHandlers) Create(c *gin.Context) {
 		return
 	}
 
-	payment, err := h.paymentService.Create(&req)
+	payment, err := h.paymentService.Create(c.Request.Context(), &req)
 	if err != nil {
 		h.logger.Error("payment.create: failed to create payment", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payment"})
@@ -55,74 +54,29 @@ func (h *PaymentHandlers) Create(c *gin.Context) {
 func (h *PaymentHandlers) List(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	// limit
-	limit := 20
-	if v := c.Query("limit"); v != "" {
-		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
-	}
-
-	// cursor
-	var (
-		lastCreatedAt *time.Time
-		lastID        *uuid.UUID
-	)
-
-	if v := c.Query("created_at"); v != "" {
-		t, err := time.Parse(time.RFC3339, v)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid created_at"})
-			return
-		}
-		lastCreatedAt = &t
-	}
-
-	if v := c.Query("id"); v != "" {
-		uid, err := uuid.Parse(v)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
-			return
-		}
-		lastID = &uid
-	}
-
-	if (lastCreatedAt == nil) != (lastID == nil) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "created_at and id must be used together"})
+	q, err := query.Parse(c.Request.URL.Query(), query.Payments)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	payments, err := h.paymentService.List(ctx, limit, lastCreatedAt, lastID)
+	payments, err := h.paymentService.List(ctx, q)
 	if err != nil {
 		h.logger.Error("payment.list: failed to list payments", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list payments"})
 		return
 	}
 
-	type cursor struct {
-		CreatedAt time.Time `json:"created_at"`
-		ID        uuid.UUID `json:"id"`
-	}
-
-	var nextCursor *cursor
-	if len(payments) == limit {
-		last := payments[len(payments)-1]
-		nextCursor = &cursor{
-			CreatedAt: last.CreatedAt,
-			ID:        last.ID,
-		}
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"items":       payments,
-		"next_cursor": nextCursor,
+		"next_cursor": query.NextCursor(q, payments),
 	})
 }
 
 func (h *PaymentHandlers) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	payment, err := h.paymentService.GetByID(id)
+	payment, err := h.paymentService.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error("payment.getByID: failed to get payment", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payment"})
@@ -142,8 +96,12 @@ func (h *PaymentHandlers) Update(c *gin.Context) {
 		return
 	}
 
-	payment, err := h.paymentService.Update(id, &req)
+	payment, err := h.paymentService.Update(c.Request.Context(), id, &req)
 	if err != nil {
+		if errors.Is(err, service.ErrPaymentImmutable) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("payment.update: failed to update payment", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payment"})
 		return
@@ -155,7 +113,11 @@ func (h *PaymentHandlers) Update(c *gin.Context) {
 func (h *PaymentHandlers) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.paymentService.Delete(id); err != nil {
+	if err := h.paymentService.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrPaymentImmutable) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("payment.delete: failed to delete payment", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payment"})
 		return
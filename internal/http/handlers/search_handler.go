@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SearchHandler struct {
+	searchService service.SearchService
+	logger        *slog.Logger
+}
+
+func NewSearchHandler(searchService service.SearchService, logger *slog.Logger) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+		logger:        logger,
+	}
+}
+
+func (h *SearchHandler) RegisterRoutes(r *gin.RouterGroup) {
+	// Public routes
+	r.GET("/search", h.Search)
+}
+
+func (h *SearchHandler) Search(c *gin.Context) {
+	q := dto.SearchQuery{
+		Query: c.Query("q"),
+		Type:  c.Query("type"),
+		Limit: 20,
+	}
+
+	if v := c.Query("limit"); v != "" {
+		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 100 {
+			q.Limit = l
+		}
+	}
+
+	if v := c.Query("category_id"); v != "" {
+		categoryID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+			return
+		}
+		q.CategoryID = &categoryID
+	}
+
+	if v := c.Query("score"); v != "" {
+		score, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid score"})
+			return
+		}
+		q.LastScore = &score
+	}
+
+	if v := c.Query("created_at"); v != "" {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid created_at"})
+			return
+		}
+		q.LastCreatedAt = &t
+	}
+
+	if v := c.Query("id"); v != "" {
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		q.LastID = &uid
+	}
+
+	if (q.LastScore == nil) != (q.LastCreatedAt == nil) || (q.LastCreatedAt == nil) != (q.LastID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "score, created_at and id must be used together",
+		})
+		return
+	}
+
+	hits, err := h.searchService.Search(c.Request.Context(), q)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearchQuery) || errors.Is(err, service.ErrInvalidSearchType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("handler.search.search: failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search"})
+		return
+	}
+
+	type cursor struct {
+		Score     float64   `json:"score"`
+		CreatedAt time.Time `json:"created_at"`
+		ID        uuid.UUID `json:"id"`
+	}
+
+	var nextCursor *cursor
+	if len(hits) == q.Limit {
+		last := hits[len(hits)-1]
+		nextCursor = &cursor{
+			Score:     last.Score,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       hits,
+		"next_cursor": nextCursor,
+	})
+}
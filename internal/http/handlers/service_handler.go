@@ -3,31 +3,46 @@ package handlers
 import (
 	"effective-project/internal/dto"
 	"effective-project/internal/http/middleware"
+	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"effective-project/internal/service"
 	"log/slog"
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type ServiceHandler struct {
-	serviceService service.ServiceService
-	logger         *slog.Logger
+	serviceService     service.ServiceService
+	catalogService     service.CatalogService
+	translationService service.TranslationService
+	favoriteService    service.FavoriteService
+	recentService      service.RecentService
+	logger             *slog.Logger
 }
 
-func NewServiceHandler(serviceService service.ServiceService, logger *slog.Logger) *ServiceHandler {
+func NewServiceHandler(
+	serviceService service.ServiceService,
+	catalogService service.CatalogService,
+	translationService service.TranslationService,
+	favoriteService service.FavoriteService,
+	recentService service.RecentService,
+	logger *slog.Logger,
+) *ServiceHandler {
 	return &ServiceHandler{
-		serviceService: serviceService,
-		logger:         logger,
+		serviceService:     serviceService,
+		catalogService:     catalogService,
+		translationService: translationService,
+		favoriteService:    favoriteService,
+		recentService:      recentService,
+		logger:             logger,
 	}
 }
 
 // RegisterRoutes registers service routes in Gin router
 func (h *ServiceHandler) RegisterRoutes(r *gin.RouterGroup) {
 	services := r.Group("/services")
+	services.Use(middleware.Locale(h.translationService.Locales()))
 	// Public routes
 	services.GET("", h.List)
 	services.GET("/:id", h.GetByID)
@@ -50,7 +65,7 @@ func (h *ServiceHandler) Create(c *gin.Context) {
 		return
 	}
 
-	service, err := h.serviceService.Create(&req)
+	service, err := h.serviceService.Create(c.Request.Context(), &req)
 	if err != nil {
 		h.logger.Error("handler.service.create: failed to create service", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create service"})
@@ -63,81 +78,70 @@ func (h *ServiceHandler) Create(c *gin.Context) {
 func (h *ServiceHandler) List(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	// limit
-	limit := 20
-	if v := c.Query("limit"); v != "" {
-		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
-	}
-
-	// cursor
-	var (
-		lastCreatedAt *time.Time
-		lastID        *uuid.UUID
-	)
-
-	if v := c.Query("created_at"); v != "" {
-		t, err := time.Parse(time.RFC3339, v)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid created_at"})
-			return
-		}
-		lastCreatedAt = &t
-	}
-
-	if v := c.Query("id"); v != "" {
-		uid, err := uuid.Parse(v)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
-			return
-		}
-		lastID = &uid
+	q, err := query.Parse(c.Request.URL.Query(), query.Services)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	if (lastCreatedAt == nil) != (lastID == nil) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "created_at and id must be used together"})
+	if !favoriteFilter(c, h.favoriteService, q, false, h.logger) {
 		return
 	}
 
-	services, err := h.serviceService.List(ctx, limit, lastCreatedAt, lastID)
+	services, err := h.serviceService.List(ctx, q)
 	if err != nil {
 		h.logger.Error("handler.service.list: failed to list services", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list services"})
 		return
 	}
 
-	type cursor struct {
-		CreatedAt time.Time `json:"created_at"`
-		ID        uuid.UUID `json:"id"`
-	}
-
-	var nextCursor *cursor
-	if len(services) == limit {
-		last := services[len(services)-1]
-		nextCursor = &cursor{
-			CreatedAt: last.CreatedAt,
-			ID:        last.ID,
-		}
+	if err := h.translationService.LocalizeServices(ctx, currentLocale(c), services); err != nil {
+		h.logger.Error("handler.service.list: failed to localize services", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list services"})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"items":       services,
-		"next_cursor": nextCursor,
+		"next_cursor": query.NextCursor(q, services),
 	})
 }
 
 func (h *ServiceHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	service, err := h.serviceService.GetByID(id)
+	service, err := h.serviceService.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error("handler.service.get_by_id: failed to get service", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get service"})
 		return
 	}
 
-	c.JSON(http.StatusOK, service)
+	ctx := c.Request.Context()
+	locale := currentLocale(c)
+
+	if userID, ok := currentUserID(c); ok {
+		h.recentService.Track(ctx, userID, service.ID)
+	}
+
+	localized := []models.Service{*service}
+	if err := h.translationService.LocalizeServices(ctx, locale, localized); err != nil {
+		h.logger.Error("handler.service.get_by_id: failed to localize service", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get service"})
+		return
+	}
+
+	path, err := h.catalogService.Breadcrumbs(ctx, service.CategoryID)
+	if err == nil {
+		err = h.translationService.LocalizePath(ctx, locale, path)
+	}
+	if err != nil {
+		h.logger.Error("handler.service.get_by_id: failed to get category path", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get service"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ServiceWithPath{Service: localized[0], CategoryPath: path})
 }
 
 func (h *ServiceHandler) Update(c *gin.Context) {
@@ -150,7 +154,7 @@ func (h *ServiceHandler) Update(c *gin.Context) {
 		return
 	}
 
-	service, err := h.serviceService.Update(id, &req)
+	service, err := h.serviceService.Update(c.Request.Context(), id, &req)
 	if err != nil {
 		h.logger.Error("handler.service.update: failed to update service", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update service"})
@@ -163,7 +167,7 @@ func (h *ServiceHandler) Update(c *gin.Context) {
 func (h *ServiceHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.serviceService.Delete(id); err != nil {
+	if err := h.serviceService.Delete(c.Request.Context(), id); err != nil {
 		h.logger.Error("handler.service.delete: failed to delete service", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete service"})
 		return
@@ -3,9 +3,9 @@ package handlers
 import (
 	"effective-project/internal/dto"
 	"effective-project/internal/http/middleware"
+	"effective-project/internal/query"
 	"effective-project/internal/service"
 	"net/http"
-	"strconv"
 	"time"
 
 	"log/slog"
@@ -52,8 +52,16 @@ func (h *SubscriptionHandler) Create(c *gin.Context) {
 		return
 	}
 
-	subscription, err := h.subscriptionService.Create(&req)
+	if req.CouponCode != "" && !actsAs(c, req.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "coupon can only be applied for the current user"})
+		return
+	}
+
+	subscription, err := h.subscriptionService.Create(c.Request.Context(), &req)
 	if err != nil {
+		if couponError(c, err) {
+			return
+		}
 		h.logger.Error("handler.subscription.create: failed to create subscription", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create subscription"})
 		return
@@ -65,75 +73,29 @@ func (h *SubscriptionHandler) Create(c *gin.Context) {
 func (h *SubscriptionHandler) List(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	// limit
-	limit := 20
-	if v := c.Query("limit"); v != "" {
-		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
-	}
-
-	var (
-		lastCreatedAt *time.Time
-		lastID        *uuid.UUID
-	)
-
-	if v := c.Query("created_at"); v != "" {
-		t, err := time.Parse(time.RFC3339, v)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid created_at"})
-			return
-		}
-		lastCreatedAt = &t
-	}
-
-	if v := c.Query("id"); v != "" {
-		uid, err := uuid.Parse(v)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
-			return
-		}
-		lastID = &uid
-	}
-
-	if (lastCreatedAt == nil) != (lastID == nil) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "created_at and id must be used together",
-		})
+	q, err := query.Parse(c.Request.URL.Query(), query.Subscriptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	subscriptions, err := h.subscriptionService.List(ctx, limit, lastCreatedAt, lastID)
+	subscriptions, err := h.subscriptionService.List(ctx, q)
 	if err != nil {
 		h.logger.Error("handler.subscription.list: failed", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list subscriptions"})
 		return
 	}
 
-	type cursor struct {
-		CreatedAt time.Time `json:"created_at"`
-		ID        uuid.UUID `json:"id"`
-	}
-
-	var nextCursor *cursor
-	if len(subscriptions) == limit {
-		last := subscriptions[len(subscriptions)-1]
-		nextCursor = &cursor{
-			CreatedAt: last.CreatedAt,
-			ID:        last.ID,
-		}
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"items":       subscriptions,
-		"next_cursor": nextCursor,
+		"next_cursor": query.NextCursor(q, subscriptions),
 	})
 }
 
 func (h *SubscriptionHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	subscription, err := h.subscriptionService.GetByID(id)
+	subscription, err := h.subscriptionService.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error("handler.subscription.get_by_id: failed", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get subscription"})
@@ -153,7 +115,7 @@ func (h *SubscriptionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	subscription, err := h.subscriptionService.Update(id, &req)
+	subscription, err := h.subscriptionService.Update(c.Request.Context(), id, &req)
 	if err != nil {
 		h.logger.Error("handler.subscription.update: failed", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update subscription"})
@@ -166,7 +128,7 @@ func (h *SubscriptionHandler) Update(c *gin.Context) {
 func (h *SubscriptionHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.subscriptionService.Delete(id); err != nil {
+	if err := h.subscriptionService.Delete(c.Request.Context(), id); err != nil {
 		h.logger.Error("handler.subscription.delete: failed", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete subscription"})
 		return
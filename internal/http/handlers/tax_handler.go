@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxHandler struct {
+	taxService service.TaxService
+	logger     *slog.Logger
+}
+
+func NewTaxHandler(taxService service.TaxService, logger *slog.Logger) *TaxHandler {
+	return &TaxHandler{
+		taxService: taxService,
+		logger:     logger,
+	}
+}
+
+func (h *TaxHandler) RegisterRoutes(r *gin.RouterGroup) {
+	rules := r.Group("/admin/tax-rules")
+	rules.Use(middleware.RequireRole("admin"))
+
+	rules.POST("", h.CreateRule)
+	rules.GET("", h.ListRules)
+	rules.GET("/:id", h.GetRule)
+	rules.PUT("/:id", h.UpdateRule)
+	rules.DELETE("/:id", h.DeleteRule)
+
+	r.GET("/admin/taxes/report", middleware.RequireRole("admin"), h.Report)
+	r.GET("/me/taxes/quote", middleware.RequireRole("user", "admin"), h.Quote)
+}
+
+func (h *TaxHandler) CreateRule(c *gin.Context) {
+	var req dto.TaxRuleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("handler.tax.create_rule: invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	rule, err := h.taxService.CreateRule(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, "handler.tax.create_rule", "failed to create tax rule", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func (h *TaxHandler) ListRules(c *gin.Context) {
+	rules, err := h.taxService.ListRules(c.Request.Context(), c.Query("country"))
+	if err != nil {
+		h.writeError(c, "handler.tax.list_rules", "failed to list tax rules", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": rules})
+}
+
+func (h *TaxHandler) GetRule(c *gin.Context) {
+	rule, err := h.taxService.GetRule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.writeError(c, "handler.tax.get_rule", "failed to get tax rule", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *TaxHandler) UpdateRule(c *gin.Context) {
+	var req dto.TaxRuleUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("handler.tax.update_rule: invalid request", slog.Any("error", err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	rule, err := h.taxService.UpdateRule(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, "handler.tax.update_rule", "failed to update tax rule", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *TaxHandler) DeleteRule(c *gin.Context) {
+	if err := h.taxService.DeleteRule(c.Request.Context(), c.Param("id")); err != nil {
+		h.writeError(c, "handler.tax.delete_rule", "failed to delete tax rule", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TaxHandler) Report(c *gin.Context) {
+	from, err := parseMonth(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+		return
+	}
+
+	to, err := parseMonth(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+		return
+	}
+
+	rows, err := h.taxService.Report(c.Request.Context(), dto.TaxReportFilter{
+		From:    from,
+		To:      to,
+		Period:  c.DefaultQuery("period", "month"),
+		Country: c.Query("country"),
+	})
+	if err != nil {
+		h.writeError(c, "handler.tax.report", "failed to build tax report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": rows})
+}
+
+func (h *TaxHandler) Quote(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	amount, err := strconv.Atoi(c.Query("amount"))
+	if err != nil || amount < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amount"})
+		return
+	}
+
+	calc, err := h.taxService.Quote(c.Request.Context(), userID, amount)
+	if err != nil {
+		h.writeError(c, "handler.tax.quote", "failed to calculate tax", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, calc)
+}
+
+func (h *TaxHandler) writeError(c *gin.Context, op, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTaxRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "tax rule not found"})
+		return
+	case errors.Is(err, service.ErrInvalidTaxRule),
+		errors.Is(err, service.ErrInvalidTaxPeriod),
+		errors.Is(err, service.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrNoBillingAddress):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Error(op+": "+message, slog.Any("error", err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/service"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationHandler struct {
+	translationService service.TranslationService
+	logger             *slog.Logger
+}
+
+func NewTranslationHandler(translationService service.TranslationService, logger *slog.Logger) *TranslationHandler {
+	return &TranslationHandler{
+		translationService: translationService,
+		logger:             logger,
+	}
+}
+
+func (h *TranslationHandler) RegisterRoutes(r *gin.RouterGroup) {
+	categories := r.Group("/categories/:id/translations")
+	categories.Use(middleware.RequireRole("admin"))
+
+	// Admin routes
+	categories.GET("", h.ListCategory)
+	categories.PUT("/:locale", h.SetCategory)
+	categories.DELETE("/:locale", h.DeleteCategory)
+
+	services := r.Group("/services/:id/translations")
+	services.Use(middleware.RequireRole("admin"))
+
+	// Admin routes
+	services.GET("", h.ListService)
+	services.PUT("/:locale", h.SetService)
+	services.DELETE("/:locale", h.DeleteService)
+}
+
+func (h *TranslationHandler) ListCategory(c *gin.Context) {
+	translations, err := h.translationService.CategoryTranslations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, "handler.translation.list_category", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"default_locale": h.translationService.Locales().Default,
+		"items":          translations,
+	})
+}
+
+func (h *TranslationHandler) SetCategory(c *gin.Context) {
+	var req dto.CategoryTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	translation, err := h.translationService.SetCategoryTranslation(c.Request.Context(), c.Param("id"), c.Param("locale"), &req)
+	if err != nil {
+		h.fail(c, "handler.translation.set_category", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *TranslationHandler) DeleteCategory(c *gin.Context) {
+	if err := h.translationService.DeleteCategoryTranslation(c.Request.Context(), c.Param("id"), c.Param("locale")); err != nil {
+		h.fail(c, "handler.translation.delete_category", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TranslationHandler) ListService(c *gin.Context) {
+	translations, err := h.translationService.ServiceTranslations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.fail(c, "handler.translation.list_service", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"default_locale": h.translationService.Locales().Default,
+		"items":          translations,
+	})
+}
+
+func (h *TranslationHandler) SetService(c *gin.Context) {
+	var req dto.ServiceTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	translation, err := h.translationService.SetServiceTranslation(c.Request.Context(), c.Param("id"), c.Param("locale"), &req)
+	if err != nil {
+		h.fail(c, "handler.translation.set_service", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *TranslationHandler) DeleteService(c *gin.Context) {
+	if err := h.translationService.DeleteServiceTranslation(c.Request.Context(), c.Param("id"), c.Param("locale")); err != nil {
+		h.fail(c, "handler.translation.delete_service", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TranslationHandler) fail(c *gin.Context, op string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCategoryNotFound),
+		errors.Is(err, service.ErrServiceNotFound),
+		errors.Is(err, service.ErrTranslationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUnsupportedLocale), errors.Is(err, service.ErrDefaultLocale):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(op+": failed", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process translation"})
+	}
+}
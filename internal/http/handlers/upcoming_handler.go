@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"effective-project/internal/http/middleware"
+	"effective-project/internal/service"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UpcomingHandler struct {
+	upcomingService service.UpcomingService
+	logger          *slog.Logger
+}
+
+func NewUpcomingHandler(upcomingService service.UpcomingService, logger *slog.Logger) *UpcomingHandler {
+	return &UpcomingHandler{
+		upcomingService: upcomingService,
+		logger:          logger,
+	}
+}
+
+func (h *UpcomingHandler) RegisterRoutes(r *gin.RouterGroup) {
+	// Public routes: календарные приложения не умеют передавать токен в заголовке
+	r.GET("/calendar/:userID/:token", h.PublicCalendar)
+
+	me := r.Group("/me")
+	me.Use(middleware.RequireRole("user", "admin"))
+
+	me.GET("/upcoming", h.Upcoming)
+	me.GET("/upcoming.ics", h.Calendar)
+	me.GET("/calendar", h.CalendarURL)
+}
+
+func (h *UpcomingHandler) Upcoming(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	days, ok := parseDays(c)
+	if !ok {
+		return
+	}
+
+	upcoming, err := h.upcomingService.Upcoming(c.Request.Context(), userID, days)
+	if err != nil {
+		h.logger.Error("handler.upcoming.upcoming: failed to get upcoming charges", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get upcoming charges"})
+		return
+	}
+
+	c.JSON(http.StatusOK, upcoming)
+}
+
+func (h *UpcomingHandler) Calendar(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	days, ok := parseDays(c)
+	if !ok {
+		return
+	}
+
+	h.writeCalendar(c, userID, days)
+}
+
+func (h *UpcomingHandler) CalendarURL(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"url": fmt.Sprintf("/calendar/%s/%s.ics", userID, h.upcomingService.CalendarToken(userID)),
+	})
+}
+
+func (h *UpcomingHandler) PublicCalendar(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+		return
+	}
+
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	if !h.upcomingService.VerifyCalendarToken(userID, token) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+		return
+	}
+
+	h.writeCalendar(c, userID, 0)
+}
+
+func (h *UpcomingHandler) writeCalendar(c *gin.Context, userID uuid.UUID, days int) {
+	calendar, err := h.upcomingService.Calendar(c.Request.Context(), userID, days)
+	if err != nil {
+		h.logger.Error("handler.upcoming.calendar: failed to build calendar", slog.Any("error", err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build calendar"})
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="upcoming.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", calendar)
+}
+
+func parseDays(c *gin.Context) (int, bool) {
+	v := c.Query("days")
+	if v == "" {
+		return 0, true
+	}
+
+	days, err := strconv.Atoi(v)
+	if err != nil || days <= 0 || days > 366 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days"})
+		return 0, false
+	}
+
+	return days, true
+}
@@ -2,17 +2,15 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
-	"time"
 
 	"effective-project/internal/dto"
 	"effective-project/internal/http/middleware"
+	"effective-project/internal/query"
 	"effective-project/internal/service"
 
 	"log/slog"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type UserHandler struct {
@@ -50,7 +48,7 @@ func (h *UserHandler) Create(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Create(&req)
+	user, err := h.userService.Create(c.Request.Context(), &req)
 	if err != nil {
 		h.logger.Error("handler.user.create: failed to create user", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create user"})
@@ -63,72 +61,29 @@ func (h *UserHandler) Create(c *gin.Context) {
 func (h *UserHandler) List(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	limit := 20
-	if v := c.Query("limit"); v != "" {
-		if l, err := strconv.Atoi(v); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
-	}
-
-	var (
-		lastCreatedAt *time.Time
-		lastID        *uuid.UUID
-	)
-
-	if v := c.Query("created_at"); v != "" {
-		t, err := time.Parse(time.RFC3339, v)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid created_at"})
-			return
-		}
-		lastCreatedAt = &t
-	}
-
-	if v := c.Query("id"); v != "" {
-		uid, err := uuid.Parse(v)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
-			return
-		}
-		lastID = &uid
-	}
-
-	if (lastCreatedAt == nil) != (lastID == nil) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "created_at and id must be used together"})
+	q, err := query.Parse(c.Request.URL.Query(), query.Users)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	users, err := h.userService.List(ctx, limit, lastCreatedAt, lastID)
+	users, err := h.userService.List(ctx, q)
 	if err != nil {
 		h.logger.Error("handler.user.list: failed to list users", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list users"})
 		return
 	}
 
-	type cursor struct {
-		CreatedAt time.Time `json:"created_at"`
-		ID        uuid.UUID `json:"id"`
-	}
-
-	var nextCursor *cursor
-	if len(users) == limit {
-		last := users[len(users)-1]
-		nextCursor = &cursor{
-			CreatedAt: last.CreatedAt,
-			ID:        last.ID,
-		}
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"items":       users,
-		"next_cursor": nextCursor,
+		"next_cursor": query.NextCursor(q, users),
 	})
 }
 
 func (h *UserHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	user, err := h.userService.GetByID(id)
+	user, err := h.userService.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error("handler.user.get_by_id: failed to get user", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
@@ -148,7 +103,7 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Update(id, &req)
+	user, err := h.userService.Update(c.Request.Context(), id, &req)
 	if err != nil {
 		h.logger.Error("handler.user.update: failed to update user", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update user"})
@@ -161,7 +116,7 @@ func (h *UserHandler) Update(c *gin.Context) {
 func (h *UserHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.userService.Delete(id); err != nil {
+	if err := h.userService.Delete(c.Request.Context(), id); err != nil {
 		h.logger.Error("handler.user.delete: failed to delete user", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete user"})
 		return
@@ -173,7 +128,7 @@ func (h *UserHandler) Delete(c *gin.Context) {
 func (h *UserHandler) GetByEmail(c *gin.Context) {
 	email := c.Query("email")
 
-	user, err := h.userService.GetByEmail(email)
+	user, err := h.userService.GetByEmail(c.Request.Context(), email)
 	if err != nil {
 		h.logger.Error("handler.user.get_by_email: failed to get user", slog.Any("error", err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
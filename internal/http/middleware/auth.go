@@ -28,37 +28,57 @@ func AuthMiddleware(jwtCfg service.JWTConfig) gin.HandlerFunc {
 			return
 		}
 
-		tokenStr := parts[1]
-
-		token, err := jwt.ParseWithClaims(tokenStr, &service.UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrTokenSignatureInvalid
-			}
-			return []byte(jwtCfg.SecretKey), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := parseClaims(parts[1], jwtCfg)
+		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "токен недействителен либо просрочен",
 			})
 			return
 		}
+		ctx.Set("userID", claims.UserID)
+		ctx.Set("userRole", claims.Role)
 
-		claims, ok := token.Claims.(*service.UserClaims)
+		ctx.Next()
+	}
+}
 
-		if !ok {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid token claims",
-			})
-			return
+// OptionalAuthMiddleware кладёт данные пользователя в context, если передан валидный токен,
+// но не отклоняет анонимные запросы
+func OptionalAuthMiddleware(jwtCfg service.JWTConfig) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		parts := strings.SplitN(ctx.GetHeader("Authorization"), " ", 2)
+
+		if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+			if claims, err := parseClaims(parts[1], jwtCfg); err == nil {
+				ctx.Set("userID", claims.UserID)
+				ctx.Set("userRole", claims.Role)
+			}
 		}
-		ctx.Set("userID", claims.UserID)
-		ctx.Set("userRole", claims.Role)
 
 		ctx.Next()
 	}
 }
 
+func parseClaims(tokenStr string, jwtCfg service.JWTConfig) (*service.UserClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &service.UserClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return []byte(jwtCfg.SecretKey), nil
+	})
+
+	if err != nil || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	claims, ok := token.Claims.(*service.UserClaims)
+	if !ok {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	return claims, nil
+}
+
 func RequireRole(roles ...string) gin.HandlerFunc {
 	roleSet := make(map[string]struct{}, len(roles))
 
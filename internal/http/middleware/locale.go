@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"effective-project/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale выбирает язык ответа по ?lang= и Accept-Language и кладёт его в context
+func Locale(locales i18n.Locales) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		locale := locales.Negotiate(ctx.Query("lang"), ctx.GetHeader("Accept-Language"))
+
+		ctx.Set("locale", locale)
+		ctx.Header("Content-Language", locale)
+		ctx.Writer.Header().Add("Vary", "Accept-Language")
+
+		ctx.Next()
+	}
+}
@@ -53,7 +53,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	req.Role = models.RoleUser
 
-	user, err := h.users.Create(&req)
+	user, err := h.users.Create(c.Request.Context(), &req)
 
 	if err != nil {
 		h.logger.Error("Ошибка создания пользователя через /register", "error", err.Error(), "email", req.Email)
@@ -74,7 +74,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.auth.Login(req.Email, req.Password)
+	token, err := h.auth.Login(c.Request.Context(), req.Email, req.Password)
 
 	if err != nil {
 		if err == service.ErrInvalidCredentials {
@@ -106,7 +106,7 @@ func (h *AuthHandler) Me(c *gin.Context) {
 		})
 		return
 	}
-	user, err := h.users.GetByID(userID)
+	user, err := h.users.GetByID(c.Request.Context(), userID)
 	if err != nil {
 		if errors.Is(err, errors.New("user not found")) {
 			h.logger.Warn("Пользователь не найден в Auth.Me", "user_id", userID)
@@ -143,7 +143,7 @@ func (h *AuthHandler) UpdateMe(c *gin.Context) {
 		return
 	}
 
-	user, err := h.users.Update(userID, &req)
+	user, err := h.users.Update(c.Request.Context(), userID, &req)
 
 	if err != nil {
 		if errors.Is(err, errors.New("user not found")) {
@@ -217,7 +217,7 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.users.ChangePassword(userID, req.OldPassword, req.NewPassword); err != nil {
+	if err := h.users.ChangePassword(c.Request.Context(), userID, req.OldPassword, req.NewPassword); err != nil {
 		h.logger.Error("Ошибка смены пароля", "error", err.Error(), "user_id", userID)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
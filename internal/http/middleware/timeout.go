@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout ограничивает время обработки запроса. Контекст запроса отменяется
+// по истечении d или при отключении клиента, а вместе с ним — запросы
+// к базе и Redis, запущенные обработчиком
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"effective-project/internal/http/handlers"
+	"effective-project/internal/http/middleware"
 	"effective-project/internal/service"
 	"log/slog"
 
@@ -11,21 +12,92 @@ import (
 func RegisterRoutes(
 	router *gin.RouterGroup,
 	logger *slog.Logger,
+	jwtCfg service.JWTConfig,
+	authService service.AuthService,
 	userService service.UserService,
 	paymentService service.PaymentService,
 	subscriptionService service.SubscriptionService,
 	serviceService service.ServiceService,
 	categoryService service.CategoryService,
+	metricsService service.MetricsService,
+	upcomingService service.UpcomingService,
+	budgetService service.BudgetService,
+	orderService service.OrderService,
+	couponService service.CouponService,
+	taxService service.TaxService,
+	ledgerService service.LedgerService,
+	dunningService service.DunningService,
+	searchService service.SearchService,
+	catalogService service.CatalogService,
+	logoService service.LogoService,
+	translationService service.TranslationService,
+	favoriteService service.FavoriteService,
+	recentService service.RecentService,
+	cacheService service.CacheService,
 ) {
+	// Токен разбирается на всех маршрутах, но анонимные запросы не отклоняются:
+	// RequireRole читает роль из context, и без этого слоя маршруты с RequireRole
+	// отвечали 403 любому запросу. Публичные маршруты остаются публичными
+	router.Use(middleware.OptionalAuthMiddleware(jwtCfg))
+
+	authHandler := middleware.NewAuthHandler(authService, userService, logger)
 	userHandler := handlers.NewUserHandler(userService, logger)
 	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService, logger)
-	serviceHandler := handlers.NewServiceHandler(serviceService, logger)
+	serviceHandler := handlers.NewServiceHandler(
+		serviceService,
+		catalogService,
+		translationService,
+		favoriteService,
+		recentService,
+		logger,
+	)
 	paymentHandler := handlers.NewPaymentHandlers(paymentService, logger)
-	categoryHandler := handlers.NewCategoryHandler(categoryService, logger)
+	categoryHandler := handlers.NewCategoryHandler(
+		categoryService,
+		catalogService,
+		translationService,
+		favoriteService,
+		logger,
+	)
+	metricsHandler := handlers.NewMetricsHandler(metricsService, logger)
+	upcomingHandler := handlers.NewUpcomingHandler(upcomingService, logger)
+	budgetHandler := handlers.NewBudgetHandler(budgetService, logger)
+	orderHandler := handlers.NewOrderHandler(orderService, logger)
+	couponHandler := handlers.NewCouponHandler(couponService, logger)
+	taxHandler := handlers.NewTaxHandler(taxService, logger)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService, logger)
+	dunningHandler := handlers.NewDunningHandler(dunningService, logger)
+	searchHandler := handlers.NewSearchHandler(searchService, logger)
+	mediaHandler := handlers.NewMediaHandler(logoService, logger)
+	catalogHandler := handlers.NewCatalogHandler(catalogService, logger)
+	translationHandler := handlers.NewTranslationHandler(translationService, logger)
+	favoriteHandler := handlers.NewFavoriteHandler(
+		favoriteService,
+		recentService,
+		serviceService,
+		translationService,
+		logger,
+	)
+	cacheHandler := handlers.NewCacheHandler(cacheService, logger)
 
+	authHandler.RegisterRoutes(router, jwtCfg)
 	userHandler.RegisterRoutes(router)
 	subscriptionHandler.RegisterRoutes(router)
 	serviceHandler.RegisterRoutes(router)
 	paymentHandler.RegisterRoutes(router)
 	categoryHandler.RegisterRoutes(router)
+	metricsHandler.RegisterRoutes(router)
+	upcomingHandler.RegisterRoutes(router)
+	budgetHandler.RegisterRoutes(router)
+	orderHandler.RegisterRoutes(router)
+	couponHandler.RegisterRoutes(router)
+	taxHandler.RegisterRoutes(router)
+	ledgerHandler.RegisterRoutes(router)
+	dunningHandler.RegisterRoutes(router)
+	searchHandler.RegisterRoutes(router)
+	mediaHandler.RegisterRoutes(router)
+	catalogHandler.RegisterRoutes(router)
+	translationHandler.RegisterRoutes(router)
+	favoriteHandler.RegisterRoutes(router)
+	cacheHandler.RegisterRoutes(router)
 }
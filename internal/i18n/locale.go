@@ -0,0 +1,124 @@
+package i18n
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidLocales = errors.New("язык по умолчанию должен входить в список поддерживаемых")
+
+// Locales — поддерживаемые языки каталога. Текст на языке Default хранится
+// в самих записях, остальные языки — в таблицах переводов
+type Locales struct {
+	Default   string
+	Supported []string
+}
+
+func NewLocales(def string, supported []string) (Locales, error) {
+	l := Locales{Default: Normalize(def)}
+	for _, s := range supported {
+		if s = Normalize(s); s != "" && !l.Supports(s) {
+			l.Supported = append(l.Supported, s)
+		}
+	}
+
+	if l.Default == "" || !l.Supports(l.Default) {
+		return Locales{}, ErrInvalidLocales
+	}
+
+	return l, nil
+}
+
+func (l Locales) Supports(locale string) bool {
+	for _, s := range l.Supported {
+		if s == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate выбирает язык ответа: сначала явный параметр lang, затем
+// Accept-Language по убыванию q, иначе язык по умолчанию. Региональные
+// варианты сводятся к основному языку: en-US → en
+func (l Locales) Negotiate(lang, acceptLanguage string) string {
+	if locale := l.match(lang); locale != "" {
+		return locale
+	}
+
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if tag == "*" {
+			break
+		}
+		if locale := l.match(tag); locale != "" {
+			return locale
+		}
+	}
+
+	return l.Default
+}
+
+func (l Locales) match(tag string) string {
+	tag = Normalize(tag)
+	if tag == "" {
+		return ""
+	}
+	if l.Supports(tag) {
+		return tag
+	}
+	if base, _, ok := strings.Cut(tag, "-"); ok && l.Supports(base) {
+		return base
+	}
+	return ""
+}
+
+// Normalize приводит тег языка к виду en или en-us
+func Normalize(tag string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-")
+}
+
+// parseAcceptLanguage возвращает теги заголовка по убыванию веса;
+// теги с q=0 отбрасываются, при равном весе сохраняется исходный порядок
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
@@ -0,0 +1,38 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	locales, err := NewLocales("ru", []string{"ru", "en"})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name, lang, accept, want string
+	}{
+		{"default", "", "", "ru"},
+		{"query wins", "en", "ru", "en"},
+		{"unsupported query falls back to header", "de", "en-GB,en;q=0.9", "en"},
+		{"region reduced to language", "", "en-US", "en"},
+		{"weights", "", "ru;q=0.5, en;q=0.8", "en"},
+		{"zero weight ignored", "", "en;q=0, de", "ru"},
+		{"wildcard stops matching", "", "de, *, en", "ru"},
+		{"case and underscore", "EN_us", "", "en"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, locales.Negotiate(tc.lang, tc.accept))
+		})
+	}
+}
+
+func TestNewLocales_DefaultMustBeSupported(t *testing.T) {
+	_, err := NewLocales("de", []string{"ru", "en"})
+
+	assert.ErrorIs(t, err, ErrInvalidLocales)
+}
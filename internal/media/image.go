@@ -0,0 +1,118 @@
+// Package media проверяет загружаемые изображения и готовит из них миниатюры.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+
+	// регистрация декодеров для image.Decode
+	_ "image/gif"
+	_ "image/jpeg"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("поддерживаются только изображения PNG, JPEG и GIF")
+	ErrImageDimensions  = errors.New("размер изображения превышает допустимый")
+)
+
+// MaxDimension ограничивает ширину и высоту исходника: сжатый файл небольшого
+// размера может раскрыться в огромный растр
+const MaxDimension = 4096
+
+var supportedTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+}
+
+// Decode определяет тип по содержимому, а не по имени файла или заголовку
+// клиента, и декодирует изображение
+func Decode(data []byte) (image.Image, string, error) {
+	contentType := http.DetectContentType(data)
+	if !supportedTypes[contentType] {
+		return nil, "", ErrUnsupportedImage
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > MaxDimension || cfg.Height > MaxDimension {
+		return nil, "", ErrImageDimensions
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedImage
+	}
+
+	return img, contentType, nil
+}
+
+// Thumbnail вписывает изображение в квадрат size×size с сохранением пропорций.
+// Уменьшение усредняет пиксели исходника, попавшие в пиксель результата;
+// изображения меньше size не увеличиваются
+func Thumbnail(src image.Image, size int) *image.NRGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	dw, dh := w, h
+	if w > size || h > size {
+		if w >= h {
+			dw, dh = size, max(1, h*size/w)
+		} else {
+			dw, dh = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		y0 := b.Min.Y + y*h/dh
+		y1 := max(y0+1, b.Min.Y+(y+1)*h/dh)
+
+		for x := 0; x < dw; x++ {
+			x0 := b.Min.X + x*w/dw
+			x1 := max(x0+1, b.Min.X+(x+1)*w/dw)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(src.At(sx, sy)).(color.NRGBA64)
+					// цвет взвешивается по альфе, чтобы прозрачные края не темнели
+					r += uint64(c.R) * uint64(c.A)
+					g += uint64(c.G) * uint64(c.A)
+					bl += uint64(c.B) * uint64(c.A)
+					a += uint64(c.A)
+					n++
+				}
+			}
+
+			var px color.NRGBA
+			if a > 0 {
+				px = color.NRGBA{
+					R: uint8(r / a >> 8),
+					G: uint8(g / a >> 8),
+					B: uint8(bl / a >> 8),
+					A: uint8(a / n >> 8),
+				}
+			}
+			dst.SetNRGBA(x, y, px)
+		}
+	}
+
+	return dst
+}
+
+// EncodePNG кодирует миниатюру; PNG сохраняет прозрачность логотипов
+func EncodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,35 @@
+package media
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbnail_KeepsAspectAndDoesNotUpscale(t *testing.T) {
+	tall := image.NewNRGBA(image.Rect(0, 0, 100, 400))
+
+	assert.Equal(t, image.Rect(0, 0, 16, 64), Thumbnail(tall, 64).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 100, 400), Thumbnail(tall, 512).Bounds())
+}
+
+func TestThumbnail_TransparentEdgesDoNotDarken(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+	src.SetNRGBA(1, 0, color.NRGBA{})
+
+	px := Thumbnail(src, 1).NRGBAAt(0, 0)
+
+	assert.Equal(t, uint8(255), px.R)
+	assert.InDelta(t, 127, int(px.A), 1)
+}
+
+func TestDecode_SniffsContent(t *testing.T) {
+	_, _, err := Decode([]byte("GIF89a"))
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+
+	_, _, err = Decode([]byte("%PDF-1.4"))
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+}
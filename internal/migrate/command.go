@@ -0,0 +1,115 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+)
+
+var ErrUsage = errors.New(`usage: app migrate <command>
+  up              применить все новые миграции
+  down [N]        откатить N последних миграций (по умолчанию одну)
+  status          показать применённые и ожидающие миграции
+  create [-dir D] NAME  создать пустую пару файлов миграции`)
+
+// DefaultDir — каталог исходников миграций относительно корня репозитория
+const DefaultDir = "internal/migrate/migrations"
+
+// Command выполняет подкоманду migrate. Подключение к базе открывается через db,
+// только если команде оно нужно: create работает с файлами
+func Command(ctx context.Context, args []string, db func() *gorm.DB, out io.Writer, logger *slog.Logger) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	if args[0] == "create" {
+		flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		dir := flags.String("dir", DefaultDir, "")
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() != 1 {
+			return ErrUsage
+		}
+
+		up, down, err := Create(*dir, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "created %s\ncreated %s\n", up, down)
+		return nil
+	}
+
+	migrations, err := Embedded()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		if len(args) != 1 {
+			return ErrUsage
+		}
+
+		applied, err := New(db(), migrations, logger).Up(ctx)
+		for _, mig := range applied {
+			fmt.Fprintf(out, "applied %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		switch len(args) {
+		case 1:
+		case 2:
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return ErrUsage
+			}
+		default:
+			return ErrUsage
+		}
+
+		reverted, err := New(db(), migrations, logger).Down(ctx, steps)
+		for _, mig := range reverted {
+			fmt.Fprintf(out, "rolled back %04d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+
+	case "status":
+		if len(args) != 1 {
+			return ErrUsage
+		}
+
+		states, err := New(db(), migrations, logger).Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range states {
+			applied := "pending"
+			switch {
+			case s.Missing:
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05") + " (not in this build)"
+			case s.AppliedAt != nil:
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return w.Flush()
+
+	default:
+		return ErrUsage
+	}
+}
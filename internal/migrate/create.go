@@ -0,0 +1,60 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidName = errors.New("название миграции должно содержать буквы или цифры")
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create добавляет в dir пустую пару файлов со следующим по порядку номером
+// и возвращает их пути. Новые файлы попадают в бинарь при следующей сборке
+func Create(dir, name string) (up, down string, err error) {
+	name = strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", ErrInvalidName
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+
+	var version int64 = 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", version, name))
+	up, down = base+".up.sql", base+".down.sql"
+
+	if err := writeNew(up, fmt.Sprintf("-- %04d %s\n", version, name)); err != nil {
+		return "", "", err
+	}
+	if err := writeNew(down, fmt.Sprintf("-- откат %04d %s\n", version, name)); err != nil {
+		os.Remove(up)
+		return "", "", err
+	}
+
+	return up, down, nil
+}
+
+func writeNew(path, content string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
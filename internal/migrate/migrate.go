@@ -0,0 +1,308 @@
+package migrate
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidFileName  = errors.New("имя файла миграции должно иметь вид 0001_name.up.sql или 0001_name.down.sql")
+	ErrDuplicateVersion = errors.New("две миграции с одним номером")
+	ErrIncomplete       = errors.New("у миграции нет файла up или down")
+	ErrUnknownVersion   = errors.New("применённой миграции нет в сборке")
+)
+
+//go:embed migrations/*.sql
+var embedded embed.FS
+
+// Ключ advisory lock: пока одна реплика применяет миграции, остальные ждут
+const lockKey int64 = 0x6d696772617465
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now()
+)`
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration — пара SQL-файлов с одним номером. Каждая миграция
+// применяется и откатывается в своей транзакции
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// State — состояние миграции в базе
+type State struct {
+	Version int64
+	Name    string
+	// AppliedAt пуст у ещё не применённых миграций
+	AppliedAt *time.Time
+	// Missing — миграция применена, но её нет в сборке,
+	// например база уже обновлена более новой версией приложения
+	Missing bool
+}
+
+// Embedded возвращает миграции, встроенные в бинарь
+func Embedded() ([]Migration, error) {
+	dir, err := fs.Sub(embedded, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(dir)
+}
+
+// Load читает миграции из корня fsys и сортирует их по номеру
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	files := map[int64]map[string]bool{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+			files[version] = map[string]bool{}
+		}
+		if m.Name != match[2] || files[version][match[3]] {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, version)
+		}
+		files[version][match[3]] = true
+
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, m := range byVersion {
+		if !files[version]["up"] || !files[version]["down"] {
+			return nil, fmt.Errorf("%w: %d_%s", ErrIncomplete, version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator применяет миграции и ведёт их учёт в таблице schema_migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+func New(db *gorm.DB, migrations []Migration, logger *slog.Logger) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+// Up применяет все ещё не применённые миграции по возрастанию номера,
+// в том числе пропущенные миграции с номером меньше последнего применённого.
+// Первая ошибка останавливает применение, уже применённые миграции остаются
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	op := "migrate.up"
+
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(mig.Up).Error; err != nil {
+					return err
+				}
+				return tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", mig.Version, mig.Name).Error
+			})
+			if err != nil {
+				m.logger.Error("migration failed", slog.String("op", op), slog.Int64("version", mig.Version),
+					slog.String("name", mig.Name), slog.Any("error", err))
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			m.logger.Info("migration applied", slog.String("op", op), slog.Int64("version", mig.Version), slog.String("name", mig.Name))
+			done = append(done, mig)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down откатывает steps последних применённых миграций
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	op := "migrate.down"
+
+	byVersion := make(map[int64]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+
+	var done []Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		var versions []int64
+		err := conn.Raw("SELECT version FROM schema_migrations ORDER BY version DESC LIMIT ?", steps).
+			Scan(&versions).Error
+		if err != nil {
+			return err
+		}
+
+		for _, version := range versions {
+			mig, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(mig.Down).Error; err != nil {
+					return err
+				}
+				return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", mig.Version).Error
+			})
+			if err != nil {
+				m.logger.Error("migration rollback failed", slog.String("op", op), slog.Int64("version", mig.Version),
+					slog.String("name", mig.Name), slog.Any("error", err))
+				return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+
+			m.logger.Info("migration rolled back", slog.String("op", op), slog.Int64("version", mig.Version), slog.String("name", mig.Name))
+			done = append(done, mig)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status возвращает все известные миграции и применённые, которых нет в сборке
+func (m *Migrator) Status(ctx context.Context) ([]State, error) {
+	var states []State
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			state := State{Version: mig.Version, Name: mig.Name}
+			if a, ok := applied[mig.Version]; ok {
+				state.AppliedAt = &a.AppliedAt
+				delete(applied, mig.Version)
+			}
+			states = append(states, state)
+		}
+
+		for _, a := range applied {
+			states = append(states, State{Version: a.Version, Name: a.Name, AppliedAt: &a.AppliedAt, Missing: true})
+		}
+
+		return nil
+	})
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Version < states[j].Version
+	})
+
+	return states, err
+}
+
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]appliedMigration, error) {
+	var rows []appliedMigration
+	if err := conn.Raw("SELECT version, name, applied_at FROM schema_migrations").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+// locked выполняет fn на одном соединении под advisory lock, чтобы реплики,
+// запущенные одновременно, не применяли миграции параллельно
+func (m *Migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	op := "migrate.lock"
+
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		m.logger.Debug("waiting for migration lock", slog.String("op", op))
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+			return err
+		}
+		defer func() {
+			// блокировка сессионная: без явного снятия она уйдёт в пул вместе с соединением
+			err := conn.WithContext(context.WithoutCancel(ctx)).Exec("SELECT pg_advisory_unlock(?)", lockKey).Error
+			if err != nil {
+				m.logger.Error("failed to release migration lock", slog.String("op", op), slog.Any("error", err))
+			}
+		}()
+
+		if err := conn.Exec(createTable).Error; err != nil {
+			return err
+		}
+
+		return fn(conn)
+	})
+}
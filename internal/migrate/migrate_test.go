@@ -0,0 +1,142 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"effective-project/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+func TestLoad_PairsAndSortsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_add_index.up.sql":   {Data: []byte("CREATE INDEX")},
+		"0010_add_index.down.sql": {Data: []byte("DROP INDEX")},
+		"0002_init.up.sql":        {Data: []byte("CREATE TABLE")},
+		"0002_init.down.sql":      {Data: []byte("DROP TABLE")},
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Migration{
+		{Version: 2, Name: "init", Up: "CREATE TABLE", Down: "DROP TABLE"},
+		{Version: 10, Name: "add_index", Up: "CREATE INDEX", Down: "DROP INDEX"},
+	}, migrations)
+}
+
+func TestLoad_RejectsBrokenSets(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		err  error
+	}{
+		{
+			name: "invalid file name",
+			fsys: fstest.MapFS{"init.sql": {}},
+			err:  ErrInvalidFileName,
+		},
+		{
+			name: "same version, different names",
+			fsys: fstest.MapFS{
+				"0001_a.up.sql": {}, "0001_a.down.sql": {},
+				"0001_b.up.sql": {}, "0001_b.down.sql": {},
+			},
+			err: ErrDuplicateVersion,
+		},
+		{
+			name: "same version written with and without padding",
+			fsys: fstest.MapFS{"0001_a.up.sql": {}, "1_a.up.sql": {}, "0001_a.down.sql": {}},
+			err:  ErrDuplicateVersion,
+		},
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{"0001_a.up.sql": {}},
+			err:  ErrIncomplete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestCreate_TakesNextVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	up, down, err := Create(dir, "Init")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0001_init.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "0001_init.down.sql"), down)
+
+	up, _, err = Create(dir, "Add user phone!")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0002_add_user_phone.up.sql"), up)
+
+	migrations, err := Load(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Len(t, migrations, 2)
+
+	_, _, err = Create(dir, "!!!")
+	assert.ErrorIs(t, err, ErrInvalidName)
+}
+
+// 0001 заменила AutoMigrate: каждая таблица и колонка моделей должна создаваться миграциями;
+// колонки, появившиеся позже, добавляются через ALTER TABLE ... ADD COLUMN
+func TestEmbedded_CoversModels(t *testing.T) {
+	migrations, err := Embedded()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, int64(1), migrations[0].Version)
+
+	var up string
+	for _, mig := range migrations {
+		up += mig.Up
+	}
+
+	cache := &sync.Map{}
+	for _, model := range []any{
+		&models.User{},
+		&models.Subscription{},
+		&models.Service{},
+		&models.Payment{},
+		&models.Category{},
+		&models.Order{},
+		&models.Budget{},
+		&models.Coupon{},
+		&models.Redemption{},
+		&models.TaxRule{},
+		&models.TaxLine{},
+		&models.LedgerEntry{},
+		&models.DunningPolicy{},
+		&models.DunningCase{},
+		&models.CategoryTranslation{},
+		&models.ServiceTranslation{},
+		&models.Favorite{},
+	} {
+		s, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		require.NoError(t, err)
+
+		table := regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS ` + s.Table + ` \((.*?)\n\);`).FindStringSubmatch(up)
+		if !assert.NotNil(t, table, "table %s", s.Table) {
+			continue
+		}
+
+		for _, column := range s.DBNames {
+			if regexp.MustCompile(`(?m)^\s+` + column + ` `).MatchString(table[1]) {
+				continue
+			}
+			assert.Regexp(t, `ALTER TABLE `+s.Table+` ADD COLUMN IF NOT EXISTS `+column+` `, up, "column %s.%s", s.Table, column)
+		}
+	}
+}
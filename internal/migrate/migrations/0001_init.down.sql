@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS favorites;
+DROP TABLE IF EXISTS service_translations;
+DROP TABLE IF EXISTS category_translations;
+DROP TABLE IF EXISTS dunning_cases;
+DROP TABLE IF EXISTS dunning_policies;
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS tax_lines;
+DROP TABLE IF EXISTS tax_rules;
+DROP TABLE IF EXISTS redemptions;
+DROP TABLE IF EXISTS coupons;
+DROP TABLE IF EXISTS budgets;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS services;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,326 @@
+-- Схема на момент перехода с AutoMigrate. Все объекты создаются через IF NOT EXISTS:
+-- на базе, которую уже создал AutoMigrate, миграция ничего не меняет и только
+-- записывается в schema_migrations. Имена индексов и ограничений совпадают с теми,
+-- что давал gorm, чтобы следующие миграции одинаково работали на обеих базах
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS users (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	email varchar(255) NOT NULL,
+	password text NOT NULL,
+	first_name varchar(100) NOT NULL,
+	last_name varchar(100) NOT NULL,
+	billing_country varchar(2),
+	billing_region varchar(100),
+	billing_city varchar(100),
+	billing_postal_code varchar(20),
+	billing_line1 varchar(255),
+	roles text NOT NULL DEFAULT 'user',
+	CONSTRAINT chk_users_roles CHECK (roles IN ('user','admin'))
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_first_name ON users (first_name);
+CREATE INDEX IF NOT EXISTS idx_users_last_name ON users (last_name);
+CREATE INDEX IF NOT EXISTS idx_users_country ON users (billing_country);
+CREATE INDEX IF NOT EXISTS idx_users_roles ON users (roles);
+
+CREATE TABLE IF NOT EXISTS categories (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name varchar(100) NOT NULL,
+	parent_id uuid,
+	CONSTRAINT fk_categories_children FOREIGN KEY (parent_id) REFERENCES categories (id)
+);
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+CREATE TABLE IF NOT EXISTS services (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	name varchar(100) NOT NULL,
+	description text,
+	category_id uuid NOT NULL,
+	website varchar(255),
+	logo_url varchar(255),
+	CONSTRAINT fk_categories_services FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+CREATE INDEX IF NOT EXISTS idx_services_deleted_at ON services (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_services_name ON services (name);
+CREATE INDEX IF NOT EXISTS idx_services_category_id ON services (category_id);
+CREATE INDEX IF NOT EXISTS idx_services_website ON services (website);
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	user_id uuid NOT NULL,
+	service_id uuid NOT NULL,
+	start_date timestamptz NOT NULL,
+	end_date timestamptz,
+	price bigint NOT NULL,
+	billing_interval varchar(10) NOT NULL DEFAULT 'month',
+	list_price bigint NOT NULL DEFAULT 0,
+	discount bigint NOT NULL DEFAULT 0,
+	coupon_id uuid,
+	status varchar(20) NOT NULL DEFAULT 'active',
+	paused_until timestamptz,
+	CONSTRAINT fk_users_subscriptions FOREIGN KEY (user_id) REFERENCES users (id),
+	CONSTRAINT fk_services_subscriptions FOREIGN KEY (service_id) REFERENCES services (id)
+);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_deleted_at ON subscriptions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_id ON subscriptions (user_id);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_service_id ON subscriptions (service_id);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_start_date ON subscriptions (start_date);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_end_date ON subscriptions (end_date);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_price ON subscriptions (price);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_coupon_id ON subscriptions (coupon_id);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_status ON subscriptions (status);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_paused_until ON subscriptions (paused_until);
+
+CREATE TABLE IF NOT EXISTS orders (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	user_id uuid NOT NULL,
+	service_id uuid NOT NULL,
+	is_paid boolean NOT NULL DEFAULT false,
+	list_price bigint NOT NULL DEFAULT 0,
+	discount bigint NOT NULL DEFAULT 0,
+	price bigint NOT NULL DEFAULT 0,
+	coupon_id uuid
+);
+CREATE INDEX IF NOT EXISTS idx_orders_deleted_at ON orders (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders (user_id);
+CREATE INDEX IF NOT EXISTS idx_orders_service_id ON orders (service_id);
+CREATE INDEX IF NOT EXISTS idx_orders_is_paid ON orders (is_paid);
+CREATE INDEX IF NOT EXISTS idx_orders_coupon_id ON orders (coupon_id);
+
+CREATE TABLE IF NOT EXISTS payments (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	subscription_id uuid NOT NULL,
+	order_id uuid NOT NULL,
+	amount bigint NOT NULL,
+	currency varchar(3) NOT NULL,
+	paid_at timestamptz,
+	payment_status varchar(20) NOT NULL,
+	provider varchar(50) NOT NULL,
+	attempt bigint NOT NULL DEFAULT 1,
+	retry_of_id uuid,
+	CONSTRAINT fk_payments_subscription FOREIGN KEY (subscription_id) REFERENCES subscriptions (id),
+	CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+CREATE INDEX IF NOT EXISTS idx_payments_deleted_at ON payments (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_payments_subscription_id ON payments (subscription_id);
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id);
+CREATE INDEX IF NOT EXISTS idx_payments_amount ON payments (amount);
+CREATE INDEX IF NOT EXISTS idx_payments_currency ON payments (currency);
+CREATE INDEX IF NOT EXISTS idx_payments_paid_at ON payments (paid_at);
+CREATE INDEX IF NOT EXISTS idx_payments_payment_status ON payments (payment_status);
+CREATE INDEX IF NOT EXISTS idx_payments_provider ON payments (provider);
+CREATE INDEX IF NOT EXISTS idx_payments_retry_of_id ON payments (retry_of_id);
+
+CREATE TABLE IF NOT EXISTS budgets (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	user_id uuid NOT NULL,
+	monthly_limit bigint NOT NULL,
+	currency varchar(3) NOT NULL,
+	category_id uuid,
+	service_id uuid,
+	alerted_threshold bigint NOT NULL DEFAULT 0,
+	alerted_period varchar(7),
+	CONSTRAINT fk_budgets_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_budgets_deleted_at ON budgets (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_budgets_user_id ON budgets (user_id);
+CREATE INDEX IF NOT EXISTS idx_budgets_category_id ON budgets (category_id);
+CREATE INDEX IF NOT EXISTS idx_budgets_service_id ON budgets (service_id);
+
+CREATE TABLE IF NOT EXISTS coupons (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	code varchar(50) NOT NULL,
+	discount_type varchar(10) NOT NULL,
+	percent_off bigint NOT NULL DEFAULT 0,
+	amount_off bigint NOT NULL DEFAULT 0,
+	currency varchar(3),
+	max_redemptions bigint NOT NULL DEFAULT 0,
+	per_user_limit bigint NOT NULL DEFAULT 0,
+	valid_from timestamptz,
+	valid_until timestamptz,
+	service_ids uuid[],
+	category_ids uuid[]
+);
+CREATE INDEX IF NOT EXISTS idx_coupons_deleted_at ON coupons (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code ON coupons (code);
+CREATE INDEX IF NOT EXISTS idx_coupons_valid_from ON coupons (valid_from);
+CREATE INDEX IF NOT EXISTS idx_coupons_valid_until ON coupons (valid_until);
+
+CREATE TABLE IF NOT EXISTS redemptions (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	coupon_id uuid NOT NULL,
+	user_id uuid NOT NULL,
+	subscription_id uuid,
+	order_id uuid,
+	discount bigint NOT NULL,
+	currency varchar(3) NOT NULL,
+	CONSTRAINT fk_redemptions_coupon FOREIGN KEY (coupon_id) REFERENCES coupons (id)
+);
+CREATE INDEX IF NOT EXISTS idx_redemptions_deleted_at ON redemptions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_redemptions_coupon_id ON redemptions (coupon_id);
+CREATE INDEX IF NOT EXISTS idx_redemptions_user_id ON redemptions (user_id);
+CREATE INDEX IF NOT EXISTS idx_redemptions_subscription_id ON redemptions (subscription_id);
+CREATE INDEX IF NOT EXISTS idx_redemptions_order_id ON redemptions (order_id);
+
+CREATE TABLE IF NOT EXISTS tax_rules (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	country varchar(2) NOT NULL,
+	region varchar(100) NOT NULL DEFAULT '',
+	name varchar(100) NOT NULL,
+	rate numeric(6,3) NOT NULL,
+	inclusive boolean NOT NULL DEFAULT false,
+	effective_from timestamptz NOT NULL,
+	effective_to timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_tax_rules_deleted_at ON tax_rules (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tax_rules_region ON tax_rules (country, region);
+CREATE INDEX IF NOT EXISTS idx_tax_rules_effective_from ON tax_rules (effective_from);
+CREATE INDEX IF NOT EXISTS idx_tax_rules_effective_to ON tax_rules (effective_to);
+
+CREATE TABLE IF NOT EXISTS tax_lines (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	user_id uuid NOT NULL,
+	order_id uuid,
+	subscription_id uuid,
+	tax_rule_id uuid NOT NULL,
+	country varchar(2) NOT NULL,
+	region varchar(100) NOT NULL DEFAULT '',
+	name varchar(100) NOT NULL,
+	rate numeric(6,3) NOT NULL,
+	inclusive boolean NOT NULL,
+	taxable bigint NOT NULL,
+	amount bigint NOT NULL,
+	currency varchar(3) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_tax_lines_deleted_at ON tax_lines (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tax_lines_user_id ON tax_lines (user_id);
+CREATE INDEX IF NOT EXISTS idx_tax_lines_order_id ON tax_lines (order_id);
+CREATE INDEX IF NOT EXISTS idx_tax_lines_subscription_id ON tax_lines (subscription_id);
+CREATE INDEX IF NOT EXISTS idx_tax_lines_tax_rule_id ON tax_lines (tax_rule_id);
+CREATE INDEX IF NOT EXISTS idx_tax_lines_country ON tax_lines (country);
+
+-- Журнал только дополняется: проводки не обновляются и не удаляются, поэтому без updated_at и deleted_at
+CREATE TABLE IF NOT EXISTS ledger_entries (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	transaction_id uuid NOT NULL,
+	payment_id uuid NOT NULL,
+	kind varchar(20) NOT NULL,
+	account varchar(30) NOT NULL,
+	debit bigint NOT NULL DEFAULT 0,
+	credit bigint NOT NULL DEFAULT 0,
+	currency varchar(3) NOT NULL,
+	description varchar(255),
+	CONSTRAINT chk_ledger_entries_debit CHECK (debit >= 0),
+	CONSTRAINT chk_ledger_entries_credit CHECK (credit >= 0)
+);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_created_at ON ledger_entries (created_at);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction_id ON ledger_entries (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_payment_id ON ledger_entries (payment_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_kind ON ledger_entries (kind);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries (account);
+
+CREATE TABLE IF NOT EXISTS dunning_policies (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	retry_days integer[] NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_dunning_policies_deleted_at ON dunning_policies (deleted_at);
+
+CREATE TABLE IF NOT EXISTS dunning_cases (
+	id uuid PRIMARY KEY,
+	created_at timestamptz,
+	updated_at timestamptz,
+	deleted_at timestamptz,
+	subscription_id uuid NOT NULL,
+	order_id uuid NOT NULL,
+	payment_id uuid NOT NULL,
+	last_payment_id uuid NOT NULL,
+	status varchar(20) NOT NULL DEFAULT 'open',
+	retries bigint NOT NULL DEFAULT 0,
+	failed_at timestamptz NOT NULL,
+	next_retry_at timestamptz,
+	closed_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_dunning_cases_deleted_at ON dunning_cases (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_dunning_cases_subscription_id ON dunning_cases (subscription_id);
+CREATE INDEX IF NOT EXISTS idx_dunning_cases_order_id ON dunning_cases (order_id);
+CREATE INDEX IF NOT EXISTS idx_dunning_cases_payment_id ON dunning_cases (payment_id);
+CREATE INDEX IF NOT EXISTS idx_dunning_cases_status ON dunning_cases (status);
+CREATE INDEX IF NOT EXISTS idx_dunning_cases_next_retry_at ON dunning_cases (next_retry_at);
+
+CREATE TABLE IF NOT EXISTS category_translations (
+	category_id uuid NOT NULL,
+	locale varchar(16) NOT NULL,
+	name varchar(100) NOT NULL,
+	updated_at timestamptz,
+	PRIMARY KEY (category_id, locale),
+	CONSTRAINT fk_categories_translations FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS service_translations (
+	service_id uuid NOT NULL,
+	locale varchar(16) NOT NULL,
+	name varchar(100) NOT NULL,
+	description text,
+	updated_at timestamptz,
+	PRIMARY KEY (service_id, locale),
+	CONSTRAINT fk_services_translations FOREIGN KEY (service_id) REFERENCES services (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS favorites (
+	user_id uuid NOT NULL,
+	service_id uuid NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (user_id, service_id),
+	CONSTRAINT fk_favorites_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+	CONSTRAINT fk_favorites_service FOREIGN KEY (service_id) REFERENCES services (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_favorites_service_id ON favorites (service_id);
+
+-- Поиск: триграммы устойчивы к опечаткам, полнотекстовый индекс ранжирует совпадения слов
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_services_name_trgm ON services USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_services_website_trgm ON services USING gin (website gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_name_fts ON categories USING gin (to_tsvector('simple', name));
+CREATE INDEX IF NOT EXISTS idx_services_name_fts ON services
+	USING gin (to_tsvector('simple', name || ' ' || coalesce(website, '')));
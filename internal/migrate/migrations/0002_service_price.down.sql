@@ -0,0 +1,2 @@
+-- откат 0002 service_price
+ALTER TABLE services DROP COLUMN IF EXISTS price;
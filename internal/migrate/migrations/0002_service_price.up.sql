@@ -0,0 +1,3 @@
+-- 0002 service_price
+-- цена сервиса в минимальных единицах валюты; по ней сервер считает стоимость заказа
+ALTER TABLE services ADD COLUMN IF NOT EXISTS price bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- откат 0003 dunning_open_case_unique
+DROP INDEX IF EXISTS idx_dunning_cases_open_subscription_id;
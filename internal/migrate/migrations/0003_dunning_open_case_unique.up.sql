@@ -0,0 +1,14 @@
+-- 0003 dunning_open_case_unique
+-- у подписки не больше одного открытого процесса взыскания. Дубликаты, которые
+-- успели появиться, закрываются: остаётся самый поздний процесс
+UPDATE dunning_cases SET status = 'cancelled', next_retry_at = NULL, closed_at = now()
+WHERE id IN (
+	SELECT id FROM (
+		SELECT id, row_number() OVER (PARTITION BY subscription_id ORDER BY failed_at DESC, id) AS n
+		FROM dunning_cases
+		WHERE status = 'open'
+	) ranked
+	WHERE n > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dunning_cases_open_subscription_id ON dunning_cases (subscription_id) WHERE status = 'open';
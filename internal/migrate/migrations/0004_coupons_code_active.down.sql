@@ -0,0 +1,4 @@
+-- откат 0004 coupons_code_active
+-- не применится, если код переиспользован после удаления купона
+DROP INDEX IF EXISTS idx_coupons_code;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code ON coupons (code);
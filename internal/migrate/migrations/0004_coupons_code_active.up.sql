@@ -0,0 +1,4 @@
+-- 0004 coupons_code_active
+-- код уникален только среди неудалённых купонов: удалённый купон не занимает код
+DROP INDEX IF EXISTS idx_coupons_code;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code ON coupons (code) WHERE deleted_at IS NULL;
@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"context"
+
+	"effective-project/internal/models"
+	"effective-project/internal/notification"
+
+	"github.com/google/uuid"
+)
+
+// MockBudgetRepository is a test mock for repository.BudgetRepository
+type MockBudgetRepository struct {
+	CreateFn      func(ctx context.Context, budget *models.Budget) error
+	ListByUserFn  func(ctx context.Context, userID uuid.UUID) ([]models.Budget, error)
+	ListUserIDsFn func(ctx context.Context) ([]uuid.UUID, error)
+	GetByIDFn     func(ctx context.Context, id string) (*models.Budget, error)
+	UpdateFn      func(ctx context.Context, budget *models.Budget) error
+	DeleteFn      func(ctx context.Context, id string) error
+}
+
+func (m *MockBudgetRepository) Create(ctx context.Context, budget *models.Budget) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, budget)
+	}
+	return nil
+}
+
+func (m *MockBudgetRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Budget, error) {
+	if m.ListByUserFn != nil {
+		return m.ListByUserFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) ListUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	if m.ListUserIDsFn != nil {
+		return m.ListUserIDsFn(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) GetByID(ctx context.Context, id string) (*models.Budget, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) Update(ctx context.Context, budget *models.Budget) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, budget)
+	}
+	return nil
+}
+
+func (m *MockBudgetRepository) Delete(ctx context.Context, id string) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+// MockNotifier records sent notifications
+type MockNotifier struct {
+	Sent []notification.Notification
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, n notification.Notification) error {
+	m.Sent = append(m.Sent, n)
+	return nil
+}
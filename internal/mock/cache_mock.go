@@ -0,0 +1,120 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"effective-project/internal/cache"
+)
+
+// MockCache is a test mock for cache.Cache. Without GetOrLoadFn it reads
+// through GetFn, calls the loader on any error and stores the result via SetFn
+type MockCache[T any] struct {
+	GetFn        func(ctx context.Context, key string) (T, error)
+	SetFn        func(ctx context.Context, key string, value T, ttl time.Duration) error
+	DeleteFn     func(ctx context.Context, key string) error
+	InvalidateFn func(ctx context.Context, tags ...string) error
+	GetOrLoadFn  func(ctx context.Context, key string, load cache.Loader[T], ttl time.Duration) (T, error)
+}
+
+func (m *MockCache[T]) Get(ctx context.Context, key string) (T, error) {
+	if m.GetFn != nil {
+		return m.GetFn(ctx, key)
+	}
+	var zero T
+	return zero, cache.ErrCacheMiss
+}
+
+func (m *MockCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if m.SetFn != nil {
+		return m.SetFn(ctx, key, value, ttl)
+	}
+	return nil
+}
+
+func (m *MockCache[T]) Delete(ctx context.Context, key string) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, key)
+	}
+	return nil
+}
+
+func (m *MockCache[T]) Invalidate(ctx context.Context, tags ...string) error {
+	if m.InvalidateFn != nil {
+		return m.InvalidateFn(ctx, tags...)
+	}
+	return nil
+}
+
+func (m *MockCache[T]) GetOrLoad(ctx context.Context, key string, load cache.Loader[T], ttl time.Duration) (T, error) {
+	if m.GetOrLoadFn != nil {
+		return m.GetOrLoadFn(ctx, key, load, ttl)
+	}
+	if value, err := m.Get(ctx, key); err == nil {
+		return value, nil
+	}
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+	_ = m.Set(ctx, key, value, ttl)
+	return value, nil
+}
+
+// MockStore is an in-memory cache.Store. Tags maps a tag to its keys
+type MockStore struct {
+	Values map[string][]byte
+	Tags   map[string][]string
+	Err    error
+}
+
+func (m *MockStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	data, ok := m.Values[key]
+	if !ok {
+		return nil, cache.ErrCacheMiss
+	}
+	return data, nil
+}
+
+func (m *MockStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Values == nil {
+		m.Values = map[string][]byte{}
+	}
+	m.Values[key] = value
+	if len(tags) > 0 && m.Tags == nil {
+		m.Tags = map[string][]string{}
+	}
+	for _, tag := range tags {
+		m.Tags[tag] = append(m.Tags[tag], key)
+	}
+	return nil
+}
+
+func (m *MockStore) Delete(ctx context.Context, key string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	delete(m.Values, key)
+	return nil
+}
+
+func (m *MockStore) Invalidate(ctx context.Context, tags ...string) ([]string, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var deleted []string
+	for _, tag := range tags {
+		for _, key := range m.Tags[tag] {
+			delete(m.Values, key)
+			deleted = append(deleted, key)
+		}
+		delete(m.Tags, tag)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+)
+
+// MockCatalogRepository is a test mock for repository.CatalogRepository
+type MockCatalogRepository struct {
+	CategoryCountsFn func(ctx context.Context, at time.Time) ([]dto.CategoryCounts, error)
+	SnapshotFn       func(ctx context.Context) ([]models.Category, []models.Service, error)
+	ImportFn         func(ctx context.Context, categories []models.Category, created []models.Service, updated []models.Service) error
+}
+
+func (m *MockCatalogRepository) CategoryCounts(ctx context.Context, at time.Time) ([]dto.CategoryCounts, error) {
+	if m.CategoryCountsFn != nil {
+		return m.CategoryCountsFn(ctx, at)
+	}
+	return nil, nil
+}
+
+func (m *MockCatalogRepository) Snapshot(ctx context.Context) ([]models.Category, []models.Service, error) {
+	if m.SnapshotFn != nil {
+		return m.SnapshotFn(ctx)
+	}
+	return nil, nil, nil
+}
+
+func (m *MockCatalogRepository) Import(ctx context.Context, categories []models.Category, created []models.Service, updated []models.Service) error {
+	if m.ImportFn != nil {
+		return m.ImportFn(ctx, categories, created, updated)
+	}
+	return nil
+}
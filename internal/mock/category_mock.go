@@ -11,16 +11,23 @@ import (
 
 // MockCategoryRepository is a test mock for repository.CategoryRepository
 type MockCategoryRepository struct {
-	CreateFn  func(c *models.Category) error
+	CreateFn  func(ctx context.Context, c *models.Category) error
 	ListFn    func(ctx context.Context, limit int, lastCreatedAt *time.Time, lastID *uuid.UUID) ([]models.Category, error)
-	GetByIDFn func(id string) (*models.Category, error)
-	UpdateFn  func(c *models.Category) error
-	DeleteFn  func(id string) error
+	GetByIDFn func(ctx context.Context, id string) (*models.Category, error)
+	UpdateFn  func(ctx context.Context, c *models.Category) error
+	DeleteFn  func(ctx context.Context, id string) error
+
+	AllFn               func(ctx context.Context) ([]models.Category, error)
+	AncestorsFn         func(ctx context.Context, id uuid.UUID) ([]models.Category, error)
+	SubtreeIDsFn        func(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	ChildrenFn          func(ctx context.Context, id uuid.UUID) ([]models.Category, error)
+	DeleteAndReparentFn func(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error
+	LockTreeFn          func(ctx context.Context) error
 }
 
-func (m *MockCategoryRepository) Create(c *models.Category) error {
+func (m *MockCategoryRepository) Create(ctx context.Context, c *models.Category) error {
 	if m.CreateFn != nil {
-		return m.CreateFn(c)
+		return m.CreateFn(ctx, c)
 	}
 	return nil
 }
@@ -32,51 +39,65 @@ func (m *MockCategoryRepository) List(ctx context.Context, limit int, lastCreate
 	return nil, nil
 }
 
-func (m *MockCategoryRepository) GetByID(id string) (*models.Category, error) {
+func (m *MockCategoryRepository) GetByID(ctx context.Context, id string) (*models.Category, error) {
 	if m.GetByIDFn != nil {
-		return m.GetByIDFn(id)
+		return m.GetByIDFn(ctx, id)
 	}
 	return nil, nil
 }
 
-func (m *MockCategoryRepository) Update(c *models.Category) error {
+func (m *MockCategoryRepository) Update(ctx context.Context, c *models.Category) error {
 	if m.UpdateFn != nil {
-		return m.UpdateFn(c)
+		return m.UpdateFn(ctx, c)
 	}
 	return nil
 }
 
-func (m *MockCategoryRepository) Delete(id string) error {
+func (m *MockCategoryRepository) Delete(ctx context.Context, id string) error {
 	if m.DeleteFn != nil {
-		return m.DeleteFn(id)
+		return m.DeleteFn(ctx, id)
 	}
 	return nil
 }
 
-// MockCategoryCache is a mock for cache.CategoryCache
-type MockCategoryCache struct {
-	GetByIDFn func(ctx context.Context, id string) (*models.Category, error)
-	SetFn     func(ctx context.Context, category *models.Category, ttl time.Duration) error
-	DeleteFn  func(ctx context.Context, id string) error
+func (m *MockCategoryRepository) All(ctx context.Context) ([]models.Category, error) {
+	if m.AllFn != nil {
+		return m.AllFn(ctx)
+	}
+	return nil, nil
 }
 
-func (m *MockCategoryCache) GetByID(ctx context.Context, id string) (*models.Category, error) {
-	if m.GetByIDFn != nil {
-		return m.GetByIDFn(ctx, id)
+func (m *MockCategoryRepository) Ancestors(ctx context.Context, id uuid.UUID) ([]models.Category, error) {
+	if m.AncestorsFn != nil {
+		return m.AncestorsFn(ctx, id)
 	}
 	return nil, nil
 }
 
-func (m *MockCategoryCache) Set(ctx context.Context, category *models.Category, ttl time.Duration) error {
-	if m.SetFn != nil {
-		return m.SetFn(ctx, category, ttl)
+func (m *MockCategoryRepository) SubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	if m.SubtreeIDsFn != nil {
+		return m.SubtreeIDsFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) Children(ctx context.Context, id uuid.UUID) ([]models.Category, error) {
+	if m.ChildrenFn != nil {
+		return m.ChildrenFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) DeleteAndReparent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	if m.DeleteAndReparentFn != nil {
+		return m.DeleteAndReparentFn(ctx, id, parentID)
 	}
 	return nil
 }
 
-func (m *MockCategoryCache) Delete(ctx context.Context, id string) error {
-	if m.DeleteFn != nil {
-		return m.DeleteFn(ctx, id)
+func (m *MockCategoryRepository) LockTree(ctx context.Context) error {
+	if m.LockTreeFn != nil {
+		return m.LockTreeFn(ctx)
 	}
 	return nil
 }
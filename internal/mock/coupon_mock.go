@@ -0,0 +1,74 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// MockCouponRepository is a test mock for repository.CouponRepository
+type MockCouponRepository struct {
+	CreateFn    func(ctx context.Context, coupon *models.Coupon) error
+	ListFn      func(ctx context.Context, limit int, lastCreatedAt *time.Time, lastID *uuid.UUID) ([]models.Coupon, error)
+	GetByIDFn   func(ctx context.Context, id string) (*models.Coupon, error)
+	GetByCodeFn func(ctx context.Context, code string) (*models.Coupon, error)
+	UpdateFn    func(ctx context.Context, coupon *models.Coupon) error
+	DeleteFn    func(ctx context.Context, id string) error
+	RedeemFn    func(ctx context.Context, redemption *models.Redemption, check func(total, perUser int64) error) error
+}
+
+func (m *MockCouponRepository) Create(ctx context.Context, coupon *models.Coupon) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, coupon)
+	}
+	return nil
+}
+
+func (m *MockCouponRepository) List(ctx context.Context, limit int, lastCreatedAt *time.Time, lastID *uuid.UUID) ([]models.Coupon, error) {
+	if m.ListFn != nil {
+		return m.ListFn(ctx, limit, lastCreatedAt, lastID)
+	}
+	return nil, nil
+}
+
+func (m *MockCouponRepository) GetByID(ctx context.Context, id string) (*models.Coupon, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockCouponRepository) GetByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	if m.GetByCodeFn != nil {
+		return m.GetByCodeFn(ctx, code)
+	}
+	return nil, nil
+}
+
+func (m *MockCouponRepository) Update(ctx context.Context, coupon *models.Coupon) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, coupon)
+	}
+	return nil
+}
+
+func (m *MockCouponRepository) Delete(ctx context.Context, id string) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockCouponRepository) Redeem(ctx context.Context, redemption *models.Redemption, check func(total, perUser int64) error) error {
+	if m.RedeemFn != nil {
+		return m.RedeemFn(ctx, redemption, check)
+	}
+	if err := check(0, 0); err != nil {
+		return err
+	}
+	redemption.ID = uuid.New()
+	return nil
+}
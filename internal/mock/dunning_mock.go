@@ -0,0 +1,91 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MockDunningRepository is a test mock for repository.DunningRepository
+type MockDunningRepository struct {
+	GetPolicyFn   func(ctx context.Context) (*models.DunningPolicy, error)
+	SavePolicyFn  func(ctx context.Context, policy *models.DunningPolicy) error
+	CreateCaseFn  func(ctx context.Context, dunningCase *models.DunningCase) error
+	GetOpenCaseFn func(ctx context.Context, subscriptionID uuid.UUID) (*models.DunningCase, error)
+	ClaimDueFn    func(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]models.DunningCase, error)
+	ListCasesFn   func(ctx context.Context, status models.DunningStatus, limit int) ([]models.DunningCase, error)
+	LockCaseFn    func(ctx context.Context, id uuid.UUID) (*models.DunningCase, error)
+	UpdateCaseFn  func(ctx context.Context, dunningCase *models.DunningCase) error
+}
+
+func (m *MockDunningRepository) GetPolicy(ctx context.Context) (*models.DunningPolicy, error) {
+	if m.GetPolicyFn != nil {
+		return m.GetPolicyFn(ctx)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockDunningRepository) SavePolicy(ctx context.Context, policy *models.DunningPolicy) error {
+	if m.SavePolicyFn != nil {
+		return m.SavePolicyFn(ctx, policy)
+	}
+	return nil
+}
+
+func (m *MockDunningRepository) CreateCase(ctx context.Context, dunningCase *models.DunningCase) error {
+	if m.CreateCaseFn != nil {
+		return m.CreateCaseFn(ctx, dunningCase)
+	}
+	return nil
+}
+
+func (m *MockDunningRepository) GetOpenCase(ctx context.Context, subscriptionID uuid.UUID) (*models.DunningCase, error) {
+	if m.GetOpenCaseFn != nil {
+		return m.GetOpenCaseFn(ctx, subscriptionID)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockDunningRepository) ClaimDue(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]models.DunningCase, error) {
+	if m.ClaimDueFn != nil {
+		return m.ClaimDueFn(ctx, at, lease, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockDunningRepository) ListCases(ctx context.Context, status models.DunningStatus, limit int) ([]models.DunningCase, error) {
+	if m.ListCasesFn != nil {
+		return m.ListCasesFn(ctx, status, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockDunningRepository) LockCase(ctx context.Context, id uuid.UUID) (*models.DunningCase, error) {
+	if m.LockCaseFn != nil {
+		return m.LockCaseFn(ctx, id)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockDunningRepository) UpdateCase(ctx context.Context, dunningCase *models.DunningCase) error {
+	if m.UpdateCaseFn != nil {
+		return m.UpdateCaseFn(ctx, dunningCase)
+	}
+	return nil
+}
+
+// MockGateway is a test mock for gateway.Gateway
+type MockGateway struct {
+	ChargeFn func(ctx context.Context, payment *models.Payment) (models.PaymentStatus, error)
+}
+
+func (m *MockGateway) Charge(ctx context.Context, payment *models.Payment) (models.PaymentStatus, error) {
+	if m.ChargeFn != nil {
+		return m.ChargeFn(ctx, payment)
+	}
+	return models.PaymentFailed, nil
+}
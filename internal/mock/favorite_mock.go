@@ -0,0 +1,87 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"effective-project/internal/cache"
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// MockFavoriteRepository is a test mock for repository.FavoriteRepository
+type MockFavoriteRepository struct {
+	AddFn        func(ctx context.Context, favorite *models.Favorite) error
+	RemoveFn     func(ctx context.Context, userID, serviceID uuid.UUID) error
+	ServiceIDsFn func(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	CountFn      func(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+func (m *MockFavoriteRepository) Add(ctx context.Context, favorite *models.Favorite) error {
+	if m.AddFn != nil {
+		return m.AddFn(ctx, favorite)
+	}
+	return nil
+}
+
+func (m *MockFavoriteRepository) Remove(ctx context.Context, userID, serviceID uuid.UUID) error {
+	if m.RemoveFn != nil {
+		return m.RemoveFn(ctx, userID, serviceID)
+	}
+	return nil
+}
+
+func (m *MockFavoriteRepository) ServiceIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	if m.ServiceIDsFn != nil {
+		return m.ServiceIDsFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockFavoriteRepository) Count(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if m.CountFn != nil {
+		return m.CountFn(ctx, userID)
+	}
+	return 0, nil
+}
+
+// MockRecentlyViewed is an in-memory cache.RecentlyViewed
+type MockRecentlyViewed struct {
+	Views map[uuid.UUID][]cache.View
+	Err   error
+}
+
+func (m *MockRecentlyViewed) Add(ctx context.Context, userID, serviceID uuid.UUID, at time.Time) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Views == nil {
+		m.Views = map[uuid.UUID][]cache.View{}
+	}
+
+	views := []cache.View{{ServiceID: serviceID, ViewedAt: at}}
+	for _, v := range m.Views[userID] {
+		if v.ServiceID != serviceID {
+			views = append(views, v)
+		}
+	}
+	m.Views[userID] = views
+	return nil
+}
+
+func (m *MockRecentlyViewed) List(ctx context.Context, userID uuid.UUID, limit int) ([]cache.View, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	views := m.Views[userID]
+	if limit > 0 && len(views) > limit {
+		views = views[:limit]
+	}
+	return views, nil
+}
+
+func (m *MockRecentlyViewed) Clear(ctx context.Context, userID uuid.UUID) error {
+	delete(m.Views, userID)
+	return m.Err
+}
@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"effective-project/internal/dto"
+)
+
+// MockMetricsRepository is a test mock for repository.MetricsRepository
+type MockMetricsRepository struct {
+	SubscriptionsStartedBeforeFn func(ctx context.Context, to time.Time) ([]dto.MetricsSubscriptionRow, error)
+	RevenueByMonthFn             func(ctx context.Context, f dto.MetricsFilter) ([]dto.RevenueRow, error)
+}
+
+func (m *MockMetricsRepository) SubscriptionsStartedBefore(ctx context.Context, to time.Time) ([]dto.MetricsSubscriptionRow, error) {
+	if m.SubscriptionsStartedBeforeFn != nil {
+		return m.SubscriptionsStartedBeforeFn(ctx, to)
+	}
+	return nil, nil
+}
+
+func (m *MockMetricsRepository) RevenueByMonth(ctx context.Context, f dto.MetricsFilter) ([]dto.RevenueRow, error) {
+	if m.RevenueByMonthFn != nil {
+		return m.RevenueByMonthFn(ctx, f)
+	}
+	return nil, nil
+}
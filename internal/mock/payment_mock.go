@@ -0,0 +1,115 @@
+package mock
+
+import (
+	"context"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/query"
+
+	"github.com/google/uuid"
+)
+
+// MockPaymentRepository is a test mock for repository.PaymentRepository
+type MockPaymentRepository struct {
+	CreateFn           func(ctx context.Context, payment *models.Payment) error
+	ListFn             func(ctx context.Context, q *query.List) ([]models.Payment, error)
+	GetByIDFn          func(ctx context.Context, id string) (*models.Payment, error)
+	GetForUpdateFn     func(ctx context.Context, id string) (*models.Payment, error)
+	UpdateFn           func(ctx context.Context, payment *models.Payment) error
+	DeleteFn           func(ctx context.Context, id string) error
+	CreateWithLedgerFn func(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error
+	UpdateWithLedgerFn func(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error
+}
+
+func (m *MockPaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, payment)
+	}
+	return nil
+}
+
+func (m *MockPaymentRepository) List(ctx context.Context, q *query.List) ([]models.Payment, error) {
+	if m.ListFn != nil {
+		return m.ListFn(ctx, q)
+	}
+	return nil, nil
+}
+
+func (m *MockPaymentRepository) GetByID(ctx context.Context, id string) (*models.Payment, error) {
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockPaymentRepository) GetForUpdate(ctx context.Context, id string) (*models.Payment, error) {
+	if m.GetForUpdateFn != nil {
+		return m.GetForUpdateFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockPaymentRepository) Update(ctx context.Context, payment *models.Payment) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, payment)
+	}
+	return nil
+}
+
+func (m *MockPaymentRepository) Delete(ctx context.Context, id string) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockPaymentRepository) CreateWithLedger(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error {
+	if m.CreateWithLedgerFn != nil {
+		return m.CreateWithLedgerFn(ctx, payment, entries)
+	}
+	return nil
+}
+
+func (m *MockPaymentRepository) UpdateWithLedger(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error {
+	if m.UpdateWithLedgerFn != nil {
+		return m.UpdateWithLedgerFn(ctx, payment, entries)
+	}
+	return nil
+}
+
+// MockLedgerRepository is a test mock for repository.LedgerRepository
+type MockLedgerRepository struct {
+	PostFn              func(ctx context.Context, paymentID uuid.UUID, entries []models.LedgerEntry, check func(summary dto.PaymentLedger) error) error
+	ListByPaymentFn     func(ctx context.Context, paymentID uuid.UUID) ([]models.LedgerEntry, error)
+	AccountBalancesFn   func(ctx context.Context) ([]dto.AccountBalance, error)
+	PaymentMismatchesFn func(ctx context.Context) ([]dto.PaymentMismatch, error)
+}
+
+func (m *MockLedgerRepository) Post(ctx context.Context, paymentID uuid.UUID, entries []models.LedgerEntry, check func(summary dto.PaymentLedger) error) error {
+	if m.PostFn != nil {
+		return m.PostFn(ctx, paymentID, entries, check)
+	}
+	return check(dto.PaymentLedger{})
+}
+
+func (m *MockLedgerRepository) ListByPayment(ctx context.Context, paymentID uuid.UUID) ([]models.LedgerEntry, error) {
+	if m.ListByPaymentFn != nil {
+		return m.ListByPaymentFn(ctx, paymentID)
+	}
+	return nil, nil
+}
+
+func (m *MockLedgerRepository) AccountBalances(ctx context.Context) ([]dto.AccountBalance, error) {
+	if m.AccountBalancesFn != nil {
+		return m.AccountBalancesFn(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockLedgerRepository) PaymentMismatches(ctx context.Context) ([]dto.PaymentMismatch, error) {
+	if m.PaymentMismatchesFn != nil {
+		return m.PaymentMismatchesFn(ctx)
+	}
+	return nil, nil
+}
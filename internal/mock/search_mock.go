@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"context"
+
+	"effective-project/internal/dto"
+)
+
+// MockSearchRepository is a test mock for repository.SearchRepository
+type MockSearchRepository struct {
+	SearchFn func(ctx context.Context, q dto.SearchQuery, tsQuery string) ([]dto.SearchHit, error)
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, q dto.SearchQuery, tsQuery string) ([]dto.SearchHit, error) {
+	if m.SearchFn != nil {
+		return m.SearchFn(ctx, q, tsQuery)
+	}
+	return nil, nil
+}
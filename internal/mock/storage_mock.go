@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"effective-project/internal/storage"
+)
+
+// MockBlobStore is an in-memory storage.BlobStore
+type MockBlobStore struct {
+	mu      sync.Mutex
+	Objects map[string][]byte
+}
+
+func (m *MockBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Objects == nil {
+		m.Objects = map[string][]byte{}
+	}
+	m.Objects[key] = data
+	return nil
+}
+
+func (m *MockBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, *storage.BlobInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.Objects[key]
+	if !ok {
+		return nil, nil, storage.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), &storage.BlobInfo{Size: int64(len(data))}, nil
+}
+
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.Objects, key)
+	return nil
+}
@@ -6,52 +6,63 @@ import (
 
 	"effective-project/internal/dto"
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 
 	"github.com/google/uuid"
 )
 
 // MockSubscriptionRepository is a test mock for repository.SubscriptionRepository
 type MockSubscriptionRepository struct {
-	CreateFn       func(s *models.Subscription) error
-	ListFn         func(ctx context.Context, limit int, lastCreatedAt *time.Time, lastID *uuid.UUID) ([]dto.SubscriptionResponse, error)
-	GetByIDFn      func(id string) (*dto.SubscriptionResponse, error)
-	UpdateFn       func(s *models.Subscription) error
-	DeleteFn       func(id string) error
+	CreateFn       func(ctx context.Context, s *models.Subscription) error
+	ListFn         func(ctx context.Context, q *query.List) ([]dto.SubscriptionResponse, error)
+	GetByIDFn      func(ctx context.Context, id string) (*dto.SubscriptionResponse, error)
+	UpdateFn       func(ctx context.Context, s *models.Subscription) error
+	UpdateStatusFn func(ctx context.Context, id uuid.UUID, status models.SubscriptionStatus, endDate *time.Time) error
+	DeleteFn       func(ctx context.Context, id string) error
 	FindForTotalFn func(ctx context.Context, f dto.TotalFilter) ([]dto.SubscriptionRow, error)
-	GetModelByIDFn func(id string) (*models.Subscription, error)
+	GetModelByIDFn func(ctx context.Context, id string) (*models.Subscription, error)
+
+	ListActiveByUserFn func(ctx context.Context, userID uuid.UUID, at time.Time) ([]dto.UpcomingSubscriptionRow, error)
 }
 
-func (m *MockSubscriptionRepository) Create(s *models.Subscription) error {
+func (m *MockSubscriptionRepository) Create(ctx context.Context, s *models.Subscription) error {
 	if m.CreateFn != nil {
-		return m.CreateFn(s)
+		return m.CreateFn(ctx, s)
 	}
 	return nil
 }
 
-func (m *MockSubscriptionRepository) List(ctx context.Context, limit int, lastCreatedAt *time.Time, lastID *uuid.UUID) ([]dto.SubscriptionResponse, error) {
+func (m *MockSubscriptionRepository) List(ctx context.Context, q *query.List) ([]dto.SubscriptionResponse, error) {
 	if m.ListFn != nil {
-		return m.ListFn(ctx, limit, lastCreatedAt, lastID)
+		return m.ListFn(ctx, q)
 	}
 	return nil, nil
 }
 
-func (m *MockSubscriptionRepository) GetByID(id string) (*dto.SubscriptionResponse, error) {
+func (m *MockSubscriptionRepository) GetByID(ctx context.Context, id string) (*dto.SubscriptionResponse, error) {
 	if m.GetByIDFn != nil {
-		return m.GetByIDFn(id)
+		return m.GetByIDFn(ctx, id)
 	}
 	return nil, nil
 }
 
-func (m *MockSubscriptionRepository) Update(s *models.Subscription) error {
+func (m *MockSubscriptionRepository) Update(ctx context.Context, s *models.Subscription) error {
 	if m.UpdateFn != nil {
-		return m.UpdateFn(s)
+		return m.UpdateFn(ctx, s)
 	}
 	return nil
 }
 
-func (m *MockSubscriptionRepository) Delete(id string) error {
+func (m *MockSubscriptionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.SubscriptionStatus, endDate *time.Time) error {
+	if m.UpdateStatusFn != nil {
+		return m.UpdateStatusFn(ctx, id, status, endDate)
+	}
+	return nil
+}
+
+func (m *MockSubscriptionRepository) Delete(ctx context.Context, id string) error {
 	if m.DeleteFn != nil {
-		return m.DeleteFn(id)
+		return m.DeleteFn(ctx, id)
 	}
 	return nil
 }
@@ -63,37 +74,16 @@ func (m *MockSubscriptionRepository) FindForTotal(ctx context.Context, f dto.Tot
 	return nil, nil
 }
 
-func (m *MockSubscriptionRepository) GetModelByID(id string) (*models.Subscription, error) {
+func (m *MockSubscriptionRepository) GetModelByID(ctx context.Context, id string) (*models.Subscription, error) {
 	if m.GetModelByIDFn != nil {
-		return m.GetModelByIDFn(id)
+		return m.GetModelByIDFn(ctx, id)
 	}
 	return nil, nil
 }
 
-// MockSubscriptionCache is a mock for cache.SubscriptionCache
-type MockSubscriptionCache struct {
-	GetByIDFn    func(ctx context.Context, id string) (*models.Subscription, error)
-	SetFn        func(ctx context.Context, sub *models.Subscription, ttl time.Duration) error
-	DeleteByIDFn func(ctx context.Context, id string) error
-}
-
-func (m *MockSubscriptionCache) GetByID(ctx context.Context, id string) (*models.Subscription, error) {
-	if m.GetByIDFn != nil {
-		return m.GetByIDFn(ctx, id)
+func (m *MockSubscriptionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID, at time.Time) ([]dto.UpcomingSubscriptionRow, error) {
+	if m.ListActiveByUserFn != nil {
+		return m.ListActiveByUserFn(ctx, userID, at)
 	}
 	return nil, nil
 }
-
-func (m *MockSubscriptionCache) Set(ctx context.Context, sub *models.Subscription, ttl time.Duration) error {
-	if m.SetFn != nil {
-		return m.SetFn(ctx, sub, ttl)
-	}
-	return nil
-}
-
-func (m *MockSubscriptionCache) DeleteByID(ctx context.Context, id string) error {
-	if m.DeleteByIDFn != nil {
-		return m.DeleteByIDFn(ctx, id)
-	}
-	return nil
-}
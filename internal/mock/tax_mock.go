@@ -0,0 +1,89 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+)
+
+// MockTaxRepository is a test mock for repository.TaxRepository
+type MockTaxRepository struct {
+	CreateRuleFn     func(ctx context.Context, rule *models.TaxRule) error
+	ListRulesFn      func(ctx context.Context, country string) ([]models.TaxRule, error)
+	GetRuleByIDFn    func(ctx context.Context, id string) (*models.TaxRule, error)
+	UpdateRuleFn     func(ctx context.Context, rule *models.TaxRule) error
+	DeleteRuleFn     func(ctx context.Context, id string) error
+	EffectiveRulesFn func(ctx context.Context, country, region string, at time.Time) ([]models.TaxRule, error)
+	CreateLinesFn    func(ctx context.Context, lines []models.TaxLine) error
+	ReportFn         func(ctx context.Context, f dto.TaxReportFilter) ([]dto.TaxReportRow, error)
+}
+
+func (m *MockTaxRepository) CreateRule(ctx context.Context, rule *models.TaxRule) error {
+	if m.CreateRuleFn != nil {
+		return m.CreateRuleFn(ctx, rule)
+	}
+	return nil
+}
+
+func (m *MockTaxRepository) ListRules(ctx context.Context, country string) ([]models.TaxRule, error) {
+	if m.ListRulesFn != nil {
+		return m.ListRulesFn(ctx, country)
+	}
+	return nil, nil
+}
+
+func (m *MockTaxRepository) GetRuleByID(ctx context.Context, id string) (*models.TaxRule, error) {
+	if m.GetRuleByIDFn != nil {
+		return m.GetRuleByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockTaxRepository) UpdateRule(ctx context.Context, rule *models.TaxRule) error {
+	if m.UpdateRuleFn != nil {
+		return m.UpdateRuleFn(ctx, rule)
+	}
+	return nil
+}
+
+func (m *MockTaxRepository) DeleteRule(ctx context.Context, id string) error {
+	if m.DeleteRuleFn != nil {
+		return m.DeleteRuleFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockTaxRepository) EffectiveRules(ctx context.Context, country, region string, at time.Time) ([]models.TaxRule, error) {
+	if m.EffectiveRulesFn != nil {
+		return m.EffectiveRulesFn(ctx, country, region, at)
+	}
+	return nil, nil
+}
+
+func (m *MockTaxRepository) CreateLines(ctx context.Context, lines []models.TaxLine) error {
+	if m.CreateLinesFn != nil {
+		return m.CreateLinesFn(ctx, lines)
+	}
+	return nil
+}
+
+func (m *MockTaxRepository) Report(ctx context.Context, f dto.TaxReportFilter) ([]dto.TaxReportRow, error) {
+	if m.ReportFn != nil {
+		return m.ReportFn(ctx, f)
+	}
+	return nil, nil
+}
+
+// MockTaxRecorder is a test mock for the Record part of service.TaxService
+type MockTaxRecorder struct {
+	RecordFn func(ctx context.Context, item dto.TaxableItem) error
+}
+
+func (m *MockTaxRecorder) Record(ctx context.Context, item dto.TaxableItem) error {
+	if m.RecordFn != nil {
+		return m.RecordFn(ctx, item)
+	}
+	return nil
+}
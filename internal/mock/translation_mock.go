@@ -0,0 +1,77 @@
+package mock
+
+import (
+	"context"
+
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// MockTranslationRepository is a test mock for repository.TranslationRepository
+type MockTranslationRepository struct {
+	CategoryTranslationsFn func(ctx context.Context, locale string, ids []uuid.UUID) ([]models.CategoryTranslation, error)
+	ServiceTranslationsFn  func(ctx context.Context, locale string, ids []uuid.UUID) ([]models.ServiceTranslation, error)
+	ListCategoryFn         func(ctx context.Context, categoryID uuid.UUID) ([]models.CategoryTranslation, error)
+	ListServiceFn          func(ctx context.Context, serviceID uuid.UUID) ([]models.ServiceTranslation, error)
+	UpsertCategoryFn       func(ctx context.Context, t *models.CategoryTranslation) error
+	UpsertServiceFn        func(ctx context.Context, t *models.ServiceTranslation) error
+	DeleteCategoryFn       func(ctx context.Context, categoryID uuid.UUID, locale string) (bool, error)
+	DeleteServiceFn        func(ctx context.Context, serviceID uuid.UUID, locale string) (bool, error)
+}
+
+func (m *MockTranslationRepository) CategoryTranslations(ctx context.Context, locale string, ids []uuid.UUID) ([]models.CategoryTranslation, error) {
+	if m.CategoryTranslationsFn != nil {
+		return m.CategoryTranslationsFn(ctx, locale, ids)
+	}
+	return nil, nil
+}
+
+func (m *MockTranslationRepository) ServiceTranslations(ctx context.Context, locale string, ids []uuid.UUID) ([]models.ServiceTranslation, error) {
+	if m.ServiceTranslationsFn != nil {
+		return m.ServiceTranslationsFn(ctx, locale, ids)
+	}
+	return nil, nil
+}
+
+func (m *MockTranslationRepository) ListCategory(ctx context.Context, categoryID uuid.UUID) ([]models.CategoryTranslation, error) {
+	if m.ListCategoryFn != nil {
+		return m.ListCategoryFn(ctx, categoryID)
+	}
+	return nil, nil
+}
+
+func (m *MockTranslationRepository) ListService(ctx context.Context, serviceID uuid.UUID) ([]models.ServiceTranslation, error) {
+	if m.ListServiceFn != nil {
+		return m.ListServiceFn(ctx, serviceID)
+	}
+	return nil, nil
+}
+
+func (m *MockTranslationRepository) UpsertCategory(ctx context.Context, t *models.CategoryTranslation) error {
+	if m.UpsertCategoryFn != nil {
+		return m.UpsertCategoryFn(ctx, t)
+	}
+	return nil
+}
+
+func (m *MockTranslationRepository) UpsertService(ctx context.Context, t *models.ServiceTranslation) error {
+	if m.UpsertServiceFn != nil {
+		return m.UpsertServiceFn(ctx, t)
+	}
+	return nil
+}
+
+func (m *MockTranslationRepository) DeleteCategory(ctx context.Context, categoryID uuid.UUID, locale string) (bool, error) {
+	if m.DeleteCategoryFn != nil {
+		return m.DeleteCategoryFn(ctx, categoryID, locale)
+	}
+	return true, nil
+}
+
+func (m *MockTranslationRepository) DeleteService(ctx context.Context, serviceID uuid.UUID, locale string) (bool, error) {
+	if m.DeleteServiceFn != nil {
+		return m.DeleteServiceFn(ctx, serviceID, locale)
+	}
+	return true, nil
+}
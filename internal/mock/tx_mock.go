@@ -0,0 +1,19 @@
+package mock
+
+import "context"
+
+// MockTxManager is a test mock for repository.TxManager
+// By default it runs fn in place and counts the calls
+type MockTxManager struct {
+	WithinTxFn func(ctx context.Context, fn func(ctx context.Context) error) error
+
+	Calls int
+}
+
+func (m *MockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Calls++
+	if m.WithinTxFn != nil {
+		return m.WithinTxFn(ctx, fn)
+	}
+	return fn(ctx)
+}
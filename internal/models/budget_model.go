@@ -0,0 +1,22 @@
+package models
+
+import "github.com/google/uuid"
+
+// Месячный бюджет пользователя на подписки
+type Budget struct {
+	Base
+
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	User   User      `json:"-"`
+
+	MonthlyLimit int    `json:"monthly_limit" binding:"required,gt=0" gorm:"not null"`
+	Currency     string `json:"currency" binding:"required,len=3" gorm:"size:3;not null"`
+
+	// Необязательные фильтры: бюджет только на категорию или на один сервис
+	CategoryID *uuid.UUID `json:"category_id" gorm:"type:uuid;index"`
+	ServiceID  *uuid.UUID `json:"service_id" gorm:"type:uuid;index"`
+
+	// Последний порог (80/100), о котором уже отправлено уведомление в периоде AlertedPeriod
+	AlertedThreshold int    `json:"-" gorm:"not null;default:0"`
+	AlertedPeriod    string `json:"-" gorm:"size:7"`
+}
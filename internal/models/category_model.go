@@ -1,10 +1,15 @@
 package models
 
-// Категория сервиса
+import "github.com/google/uuid"
+
+// Категория сервиса. Категории образуют дерево: ParentID == nil у корневых
 type Category struct {
 	Base
 
-	Name string `json:"name" binding:"required,min=2,max=100" gorm:"size:100;not null"`
+	Name     string     `json:"name" binding:"required,min=2,max=100" gorm:"size:100;not null"`
+	ParentID *uuid.UUID `json:"parent_id" gorm:"type:uuid;index"`
 
-	Services []Service `json:"-" gorm:"foreignKey:CategoryID"`
+	Children     []Category            `json:"-" gorm:"foreignKey:ParentID"`
+	Services     []Service             `json:"-" gorm:"foreignKey:CategoryID"`
+	Translations []CategoryTranslation `json:"-" gorm:"foreignKey:CategoryID;constraint:OnDelete:CASCADE"`
 }
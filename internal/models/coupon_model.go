@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent"
+	DiscountFixed   DiscountType = "fixed"
+)
+
+// Валюта, в которой выставляются подписки и заказы
+const DefaultCurrency = "RUB"
+
+// Купон / промокод на скидку
+type Coupon struct {
+	Base
+
+	Code string `json:"code" gorm:"size:50;not null;uniqueIndex:idx_coupons_code,where:deleted_at IS NULL"`
+
+	DiscountType DiscountType `json:"discount_type" gorm:"size:10;not null"`
+	PercentOff   int          `json:"percent_off" gorm:"not null;default:0"`
+	AmountOff    int          `json:"amount_off" gorm:"not null;default:0"`
+	Currency     string       `json:"currency" gorm:"size:3"`
+
+	// 0 — без ограничений
+	MaxRedemptions int `json:"max_redemptions" gorm:"not null;default:0"`
+	PerUserLimit   int `json:"per_user_limit" gorm:"not null;default:0"`
+
+	ValidFrom  *time.Time `json:"valid_from" gorm:"index"`
+	ValidUntil *time.Time `json:"valid_until" gorm:"index"`
+
+	// Пустой список — купон действует на все сервисы/категории
+	ServiceIDs  UUIDArray `json:"service_ids" gorm:"type:uuid[]"`
+	CategoryIDs UUIDArray `json:"category_ids" gorm:"type:uuid[]"`
+}
+
+// Факт применения купона
+type Redemption struct {
+	Base
+
+	CouponID uuid.UUID `json:"coupon_id" gorm:"type:uuid;not null;index"`
+	Coupon   Coupon    `json:"-"`
+
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+
+	SubscriptionID *uuid.UUID `json:"subscription_id" gorm:"type:uuid;index"`
+	OrderID        *uuid.UUID `json:"order_id" gorm:"type:uuid;index"`
+
+	Discount int    `json:"discount" gorm:"not null"`
+	Currency string `json:"currency" gorm:"size:3;not null"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Расписание повторных списаний по умолчанию: через 1, 3 и 7 дней после неудачи
+var DefaultRetryDays = IntArray{1, 3, 7}
+
+// Политика повторных попыток списания. В таблице одна запись
+type DunningPolicy struct {
+	Base
+
+	// Дни от первой неудачной попытки; после последней неудачи подписка отменяется
+	RetryDays IntArray `json:"retry_days" gorm:"type:integer[];not null"`
+}
+
+type DunningStatus string
+
+const (
+	DunningOpen      DunningStatus = "open"
+	DunningRecovered DunningStatus = "recovered"
+	DunningCancelled DunningStatus = "cancelled"
+)
+
+// Процесс взыскания по неудачному платежу подписки
+type DunningCase struct {
+	Base
+
+	SubscriptionID uuid.UUID `json:"subscription_id" gorm:"type:uuid;not null;index"`
+
+	// Счёт (заказ), по которому создаются повторные попытки
+	OrderID uuid.UUID `json:"order_id" gorm:"type:uuid;not null;index"`
+
+	// Первая неудачная попытка и последняя созданная попытка
+	PaymentID     uuid.UUID `json:"payment_id" gorm:"type:uuid;not null;index"`
+	LastPaymentID uuid.UUID `json:"last_payment_id" gorm:"type:uuid;not null"`
+
+	Status      DunningStatus `json:"status" gorm:"size:20;not null;default:'open';index"`
+	Retries     int           `json:"retries" gorm:"not null;default:0"`
+	FailedAt    time.Time     `json:"failed_at" gorm:"not null"`
+	NextRetryAt *time.Time    `json:"next_retry_at" gorm:"index"`
+	ClosedAt    *time.Time    `json:"closed_at"`
+}
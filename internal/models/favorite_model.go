@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Сервис в избранном пользователя
+type Favorite struct {
+	UserID    uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	ServiceID uuid.UUID `json:"service_id" gorm:"type:uuid;primaryKey;index"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	User    User    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Service Service `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
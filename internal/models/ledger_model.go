@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrLedgerAppendOnly = errors.New("проводки журнала нельзя изменять или удалять")
+
+type LedgerAccount string
+
+const (
+	AccountUserReceivable   LedgerAccount = "user_receivable"
+	AccountRevenue          LedgerAccount = "revenue"
+	AccountRefunds          LedgerAccount = "refunds"
+	AccountProviderClearing LedgerAccount = "provider_clearing"
+)
+
+type LedgerEntryKind string
+
+const (
+	LedgerCharge     LedgerEntryKind = "charge"
+	LedgerRefund     LedgerEntryKind = "refund"
+	LedgerAdjustment LedgerEntryKind = "adjustment"
+)
+
+// Проводка журнала платежей. Журнал только дополняется: исправления
+// оформляются новыми проводками, а сумма дебетов в проводке равна сумме кредитов
+type LedgerEntry struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+
+	// Проводки одной операции объединены TransactionID
+	TransactionID uuid.UUID       `json:"transaction_id" gorm:"type:uuid;not null;index"`
+	PaymentID     uuid.UUID       `json:"payment_id" gorm:"type:uuid;not null;index"`
+	Kind          LedgerEntryKind `json:"kind" gorm:"size:20;not null;index"`
+
+	Account  LedgerAccount `json:"account" gorm:"size:30;not null;index"`
+	Debit    int           `json:"debit" gorm:"not null;default:0;check:debit >= 0"`
+	Credit   int           `json:"credit" gorm:"not null;default:0;check:credit >= 0"`
+	Currency string        `json:"currency" gorm:"size:3;not null"`
+
+	Description string `json:"description" gorm:"size:255"`
+}
+
+func (e *LedgerEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+func (e *LedgerEntry) BeforeUpdate(tx *gorm.DB) error {
+	return ErrLedgerAppendOnly
+}
+
+func (e *LedgerEntry) BeforeDelete(tx *gorm.DB) error {
+	return ErrLedgerAppendOnly
+}
@@ -10,4 +10,10 @@ type Order struct {
 	ServiceID uuid.UUID `json:"service_id" binding:"required" gorm:"type:uuid;not null;index"`
 
 	IsPaid bool `json:"is_paid" gorm:"not null;default:false;index"`
+
+	// Снимок цены на момент оформления: ListPrice - Discount = Price
+	ListPrice int        `json:"list_price" gorm:"not null;default:0"`
+	Discount  int        `json:"discount" gorm:"not null;default:0"`
+	Price     int        `json:"price" gorm:"not null;default:0"`
+	CouponID  *uuid.UUID `json:"coupon_id" gorm:"type:uuid;index"`
 }
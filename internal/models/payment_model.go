@@ -30,4 +30,8 @@ type Payment struct {
 
 	PaymentStatus PaymentStatus `json:"payment_status" binding:"required" gorm:"size:20;not null;index"`
 	Provider      string        `json:"provider" binding:"required,min=2,max=50" gorm:"size:50;not null;index"`
+
+	// Номер попытки оплаты счёта; повторные попытки ссылаются на первую
+	Attempt   int        `json:"attempt" gorm:"not null;default:1"`
+	RetryOfID *uuid.UUID `json:"retry_of_id" gorm:"type:uuid;index"`
 }
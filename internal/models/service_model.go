@@ -6,13 +6,18 @@ import "github.com/google/uuid"
 type Service struct {
 	Base
 
-	Name string `json:"name" binding:"required,min=2,max=100" gorm:"size:100;not null;index"`
+	Name        string `json:"name" binding:"required,min=2,max=100" gorm:"size:100;not null;index"`
+	Description string `json:"description" binding:"max=1000" gorm:"type:text"`
 
 	CategoryID uuid.UUID `json:"category_id" binding:"required" gorm:"type:uuid;not null;index"`
 	Category   Category  `json:"-"`
 
+	// Цена в минимальных единицах валюты; из неё считается стоимость заказа
+	Price int `json:"price" binding:"min=0" gorm:"not null;default:0"`
+
 	Website string `json:"website" binding:"omitempty,url" gorm:"size:255;index"`
 	LogoUrl string `json:"logo_url" binding:"omitempty,url" gorm:"size:255"`
 
-	Subscriptions []Subscription `json:"-" gorm:"foreignKey:ServiceID"`
+	Subscriptions []Subscription       `json:"-" gorm:"foreignKey:ServiceID"`
+	Translations  []ServiceTranslation `json:"-" gorm:"foreignKey:ServiceID;constraint:OnDelete:CASCADE"`
 }
@@ -6,6 +6,21 @@ import (
 	"github.com/google/uuid"
 )
 
+type BillingInterval string
+
+const (
+	IntervalMonth BillingInterval = "month"
+	IntervalYear  BillingInterval = "year"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive    SubscriptionStatus = "active"
+	SubscriptionPastDue   SubscriptionStatus = "past_due"
+	SubscriptionCancelled SubscriptionStatus = "cancelled"
+)
+
 // Подписка пользователя на сервис
 type Subscription struct {
 	Base
@@ -16,8 +31,20 @@ type Subscription struct {
 	ServiceID uuid.UUID `json:"service_id" binding:"required" gorm:"type:uuid;not null;index"`
 	Service   Service   `json:"-"`
 
-	StartDate time.Time `json:"start_date" binding:"required" gorm:"not null;index"`
+	StartDate time.Time  `json:"start_date" binding:"required" gorm:"not null;index"`
 	EndDate   *time.Time `json:"end_date" gorm:"index"`
 
-	Price    int    `json:"price" binding:"required,gt=0" gorm:"not null;index"`
+	Price    int             `json:"price" binding:"required,gt=0" gorm:"not null;index"`
+	Interval BillingInterval `json:"interval" gorm:"column:billing_interval;size:10;not null;default:'month'"`
+
+	// Снимок цены на момент оформления: ListPrice - Discount = Price
+	ListPrice int        `json:"list_price" gorm:"not null;default:0"`
+	Discount  int        `json:"discount" gorm:"not null;default:0"`
+	CouponID  *uuid.UUID `json:"coupon_id" gorm:"type:uuid;index"`
+
+	// past_due — платёж не прошёл и идут повторные попытки, cancelled — попытки исчерпаны
+	Status SubscriptionStatus `json:"status" gorm:"size:20;not null;default:'active';index"`
+
+	// Списания до этой даты не производятся
+	PausedUntil *time.Time `json:"paused_until" gorm:"index"`
 }
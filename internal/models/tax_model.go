@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Налоговая ставка для страны или региона
+type TaxRule struct {
+	Base
+
+	// ISO 3166-1 alpha-2; пустой Region — ставка действует на всю страну
+	Country string `json:"country" gorm:"size:2;not null;index:idx_tax_rules_region"`
+	Region  string `json:"region" gorm:"size:100;not null;default:'';index:idx_tax_rules_region"`
+
+	Name string `json:"name" gorm:"size:100;not null"`
+
+	// Ставка в процентах, например 20 или 7.25
+	Rate float64 `json:"rate" gorm:"type:numeric(6,3);not null"`
+
+	// Inclusive — налог уже входит в цену (НДС), иначе начисляется сверху (sales tax)
+	Inclusive bool `json:"inclusive" gorm:"not null;default:false"`
+
+	EffectiveFrom time.Time  `json:"effective_from" gorm:"not null;index"`
+	EffectiveTo   *time.Time `json:"effective_to" gorm:"index"`
+}
+
+// Строка налога, начисленного по заказу или подписке
+type TaxLine struct {
+	Base
+
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+
+	OrderID        *uuid.UUID `json:"order_id" gorm:"type:uuid;index"`
+	SubscriptionID *uuid.UUID `json:"subscription_id" gorm:"type:uuid;index"`
+
+	TaxRuleID uuid.UUID `json:"tax_rule_id" gorm:"type:uuid;not null;index"`
+	Country   string    `json:"country" gorm:"size:2;not null;index"`
+	Region    string    `json:"region" gorm:"size:100;not null;default:''"`
+	Name      string    `json:"name" gorm:"size:100;not null"`
+	Rate      float64   `json:"rate" gorm:"type:numeric(6,3);not null"`
+	Inclusive bool      `json:"inclusive" gorm:"not null"`
+
+	// Налогооблагаемая база и сумма налога
+	Taxable  int    `json:"taxable" gorm:"not null"`
+	Amount   int    `json:"amount" gorm:"not null"`
+	Currency string `json:"currency" gorm:"size:3;not null"`
+}
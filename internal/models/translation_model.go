@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Перевод названия категории. Текст на языке по умолчанию хранится в самой категории
+type CategoryTranslation struct {
+	CategoryID uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	Locale     string    `json:"locale" gorm:"size:16;primaryKey"`
+
+	Name string `json:"name" gorm:"size:100;not null"`
+
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Перевод названия и описания сервиса; пустое описание берётся из сервиса
+type ServiceTranslation struct {
+	ServiceID uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	Locale    string    `json:"locale" gorm:"size:16;primaryKey"`
+
+	Name        string `json:"name" gorm:"size:100;not null"`
+	Description string `json:"description" gorm:"type:text"`
+
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// UUIDArray хранится в Postgres как uuid[]
+type UUIDArray []uuid.UUID
+
+func (a UUIDArray) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+
+	parts := make([]string, len(a))
+	for i, id := range a {
+		parts[i] = id.String()
+	}
+
+	return "{" + strings.Join(parts, ",") + "}", nil
+}
+
+func (a *UUIDArray) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("models.UUIDArray: unsupported type %T", src)
+	}
+
+	raw = strings.Trim(raw, "{}")
+	if raw == "" {
+		*a = UUIDArray{}
+		return nil
+	}
+
+	parts := strings.Split(raw, ",")
+	result := make(UUIDArray, 0, len(parts))
+	for _, part := range parts {
+		id, err := uuid.Parse(strings.Trim(part, `"`))
+		if err != nil {
+			return err
+		}
+		result = append(result, id)
+	}
+
+	*a = result
+	return nil
+}
+
+func (a UUIDArray) Contains(id uuid.UUID) bool {
+	for _, v := range a {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IntArray хранится в Postgres как integer[]
+type IntArray []int
+
+func (a IntArray) Value() (driver.Value, error) {
+	parts := make([]string, len(a))
+	for i, v := range a {
+		parts[i] = strconv.Itoa(v)
+	}
+
+	return "{" + strings.Join(parts, ",") + "}", nil
+}
+
+func (a *IntArray) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("models.IntArray: unsupported type %T", src)
+	}
+
+	raw = strings.Trim(raw, "{}")
+	if raw == "" {
+		*a = IntArray{}
+		return nil
+	}
+
+	parts := strings.Split(raw, ",")
+	result := make(IntArray, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return err
+		}
+		result = append(result, v)
+	}
+
+	*a = result
+	return nil
+}
@@ -18,6 +18,17 @@ type User struct {
 	FirstName string `json:"first_name" binding:"required,min=2,max=100" gorm:"size:100;not null;index"`
 	LastName  string `json:"last_name" binding:"required,min=2,max=100" gorm:"size:100;not null;index"`
 
+	BillingAddress BillingAddress `json:"billing_address" gorm:"embedded;embeddedPrefix:billing_"`
+
 	Roles         Role           `json:"role" binding:"required,oneof=user admin" gorm:"type:text;not null;default:'user';index;check:roles IN ('user','admin')"`
 	Subscriptions []Subscription `json:"-" gorm:"foreignKey:UserID"`
 }
+
+// Платёжный адрес, по которому определяются налоговые ставки
+type BillingAddress struct {
+	Country    string `json:"country" binding:"omitempty,len=2" gorm:"size:2;index"`
+	Region     string `json:"region" binding:"omitempty,max=100" gorm:"size:100"`
+	City       string `json:"city" binding:"omitempty,max=100" gorm:"size:100"`
+	PostalCode string `json:"postal_code" binding:"omitempty,max=20" gorm:"size:20"`
+	Line1      string `json:"line1" binding:"omitempty,max=255" gorm:"size:255"`
+}
@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+type Type string
+
+const (
+	TypeBudgetThreshold       Type = "budget_threshold"
+	TypePaymentFailed         Type = "payment_failed"
+	TypePaymentRecovered      Type = "payment_recovered"
+	TypeSubscriptionCancelled Type = "subscription_cancelled"
+)
+
+// Уведомление пользователю
+type Notification struct {
+	UserID  uuid.UUID      `json:"user_id"`
+	Type    Type           `json:"type"`
+	Title   string         `json:"title"`
+	Message string         `json:"message"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// LogNotifier пишет уведомления в лог — используется, пока нет внешнего канала доставки
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{
+		logger: logger,
+	}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	n.logger.InfoContext(ctx, "notification",
+		slog.String("type", string(notification.Type)),
+		slog.Any("user_id", notification.UserID),
+		slog.String("title", notification.Title),
+		slog.String("message", notification.Message),
+		slog.Any("data", notification.Data),
+	)
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor — значения ключей сортировки и id последнего элемента страницы
+type Cursor struct {
+	Values []any
+	ID     uuid.UUID
+}
+
+type cursorPayload struct {
+	Sort   string    `json:"s"`
+	Values []string  `json:"v"`
+	ID     uuid.UUID `json:"id"`
+}
+
+// NextCursor возвращает курсор следующей страницы или nil, если страница последняя.
+// Значения ключей берутся из полей элемента по json-тегам
+func NextCursor[T any](l *List, items []T) *string {
+	if len(items) == 0 || len(items) < l.Limit {
+		return nil
+	}
+
+	last := reflect.ValueOf(items[len(items)-1])
+
+	id, ok := jsonField(last, "id").(uuid.UUID)
+	if !ok {
+		return nil
+	}
+
+	p := cursorPayload{Sort: l.sortKey(), ID: id}
+	for _, s := range l.Sort {
+		p.Values = append(p.Values, formatValue(jsonField(last, s.Field)))
+	}
+
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return nil
+	}
+
+	cursor := base64.RawURLEncoding.EncodeToString(raw)
+	return &cursor
+}
+
+func decodeCursor(raw string, l *List) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var p cursorPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	// курсор, выданный для другой сортировки, указывает в другое место выборки
+	if p.Sort != l.sortKey() || len(p.Values) != len(l.Sort) {
+		return nil, ErrInvalidCursor
+	}
+
+	c := &Cursor{ID: p.ID}
+	for i, s := range l.Sort {
+		v, err := parseValue(l.schema.Fields[s.Field].Kind, p.Values[i])
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		c.Values = append(c.Values, v)
+	}
+
+	return c, nil
+}
+
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	case int:
+		return strconv.Itoa(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case uuid.UUID:
+		return x.String()
+	case string:
+		return x
+	default:
+		if s, ok := v.(interface{ String() string }); ok {
+			return s.String()
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.String {
+			return rv.String()
+		}
+		return ""
+	}
+}
+
+// jsonField ищет поле структуры по json-тегу, включая встроенные структуры
+func jsonField(v reflect.Value, name string) any {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == name {
+			return v.Field(i).Interface()
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Anonymous {
+			if found := jsonField(v.Field(i), name); found != nil {
+				return found
+			}
+		}
+	}
+
+	return nil
+}
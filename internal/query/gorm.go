@@ -0,0 +1,80 @@
+package query
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Apply добавляет к запросу фильтры, сортировку, условие курсора и лимит
+func (l *List) Apply(db *gorm.DB) *gorm.DB {
+	for _, f := range l.Filters {
+		column := l.schema.Fields[f.Field].Column
+
+		switch f.Op {
+		case In:
+			db = db.Where(column+" IN ?", f.Value)
+		case Like:
+			db = db.Where(column+" ILIKE ?", "%"+escapeLike(f.Value.(string))+"%")
+		default:
+			db = db.Where(column+" "+opSQL[f.Op]+" ?", f.Value)
+		}
+	}
+
+	if l.After != nil {
+		where, args := l.keyset()
+		db = db.Where(where, args...)
+	}
+
+	for _, s := range l.Sort {
+		column := l.schema.Fields[s.Field].Column
+		if s.Desc {
+			db = db.Order(column + " DESC")
+		} else {
+			db = db.Order(column + " ASC")
+		}
+	}
+
+	return db.Order(l.schema.IDColumn + " ASC").Limit(l.Limit)
+}
+
+// keyset строит условие «строго после курсора» для произвольного набора
+// ключей сортировки: (a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?)
+func (l *List) keyset() (string, []any) {
+	var (
+		clauses []string
+		args    []any
+	)
+
+	for i := 0; i <= len(l.Sort); i++ {
+		var parts []string
+		var partArgs []any
+
+		for j := 0; j < i; j++ {
+			parts = append(parts, l.schema.Fields[l.Sort[j].Field].Column+" = ?")
+			partArgs = append(partArgs, l.After.Values[j])
+		}
+
+		if i < len(l.Sort) {
+			s := l.Sort[i]
+			op := " > ?"
+			if s.Desc {
+				op = " < ?"
+			}
+			parts = append(parts, l.schema.Fields[s.Field].Column+op)
+			partArgs = append(partArgs, l.After.Values[i])
+		} else {
+			parts = append(parts, l.schema.IDColumn+" > ?")
+			partArgs = append(partArgs, l.After.ID)
+		}
+
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+		args = append(args, partArgs...)
+	}
+
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
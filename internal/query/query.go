@@ -0,0 +1,291 @@
+// Package query разбирает параметры фильтрации, сортировки и пагинации
+// list-эндпоинтов: ?filter[price][gte]=100&sort=-price,name&cursor=...
+// Поля проверяются по белому списку ресурса, поэтому в SQL попадают только
+// заранее известные колонки.
+package query
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownField  = errors.New("поле недоступно для фильтрации или сортировки")
+	ErrUnknownOp     = errors.New("оператор фильтра не поддерживается для поля")
+	ErrInvalidValue  = errors.New("некорректное значение фильтра")
+	ErrInvalidCursor = errors.New("некорректный курсор")
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	UUID
+	Time
+)
+
+type Op string
+
+const (
+	Eq   Op = "eq"
+	Ne   Op = "ne"
+	Gt   Op = "gt"
+	Gte  Op = "gte"
+	Lt   Op = "lt"
+	Lte  Op = "lte"
+	In   Op = "in"
+	Like Op = "like"
+)
+
+var opSQL = map[Op]string{
+	Eq:  "=",
+	Ne:  "<>",
+	Gt:  ">",
+	Gte: ">=",
+	Lt:  "<",
+	Lte: "<=",
+}
+
+// Операторы, допустимые для каждого типа поля
+var kindOps = map[Kind][]Op{
+	String: {Eq, Ne, In, Like},
+	Int:    {Eq, Ne, Gt, Gte, Lt, Lte, In},
+	UUID:   {Eq, Ne, In},
+	Time:   {Eq, Ne, Gt, Gte, Lt, Lte},
+}
+
+// Field описывает поле ресурса: имя в API совпадает с json-тегом ответа,
+// Column — выражение в SQL
+type Field struct {
+	Column   string
+	Kind     Kind
+	Filter   bool
+	Sortable bool
+}
+
+// Schema — белый список полей ресурса. IDColumn — уникальная колонка,
+// которая замыкает сортировку, чтобы курсор был однозначным
+type Schema struct {
+	Fields   map[string]Field
+	IDColumn string
+	Default  []Sort
+}
+
+type Filter struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// List — разобранные параметры запроса списка
+type List struct {
+	Filters []Filter
+	Sort    []Sort
+	Limit   int
+	After   *Cursor
+
+	schema Schema
+}
+
+// New создаёт запрос с сортировкой по умолчанию для выборок, которые
+// собирает сам сервис, а не клиент
+func New(schema Schema, limit int, filters ...Filter) *List {
+	return &List{
+		Filters: filters,
+		Sort:    schema.Default,
+		Limit:   limit,
+		schema:  schema,
+	}
+}
+
+var filterParam = regexp.MustCompile(`^filter\[([a-z_]+)\](?:\[([a-z]+)\])?$`)
+
+// Parse разбирает параметры запроса по схеме ресурса
+func Parse(values url.Values, schema Schema) (*List, error) {
+	l := &List{
+		Limit:  DefaultLimit,
+		schema: schema,
+	}
+
+	if v := values.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= MaxLimit {
+			l.Limit = n
+		}
+	}
+
+	for key, vals := range values {
+		m := filterParam.FindStringSubmatch(key)
+		if m == nil {
+			continue
+		}
+
+		op := Op(m[2])
+		if op == "" {
+			op = Eq
+		}
+
+		for _, raw := range vals {
+			f, err := schema.filter(m[1], op, raw)
+			if err != nil {
+				return nil, err
+			}
+			l.Filters = append(l.Filters, f)
+		}
+	}
+
+	sort, err := schema.parseSort(values.Get("sort"))
+	if err != nil {
+		return nil, err
+	}
+	l.Sort = sort
+
+	if v := values.Get("cursor"); v != "" {
+		c, err := decodeCursor(v, l)
+		if err != nil {
+			return nil, err
+		}
+		l.After = c
+	}
+
+	return l, nil
+}
+
+func (s Schema) filter(name string, op Op, raw string) (Filter, error) {
+	field, ok := s.Fields[name]
+	if !ok || !field.Filter {
+		return Filter{}, fmt.Errorf("%w: %s", ErrUnknownField, name)
+	}
+
+	allowed := false
+	for _, o := range kindOps[field.Kind] {
+		if o == op {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return Filter{}, fmt.Errorf("%w: %s[%s]", ErrUnknownOp, name, op)
+	}
+
+	if op == In {
+		parts := strings.Split(raw, ",")
+		items := make([]any, 0, len(parts))
+		for _, p := range parts {
+			v, err := parseValue(field.Kind, strings.TrimSpace(p))
+			if err != nil {
+				return Filter{}, fmt.Errorf("%w: %s", ErrInvalidValue, name)
+			}
+			items = append(items, v)
+		}
+		return Filter{Field: name, Op: op, Value: items}, nil
+	}
+
+	v, err := parseValue(field.Kind, raw)
+	if err != nil {
+		return Filter{}, fmt.Errorf("%w: %s", ErrInvalidValue, name)
+	}
+
+	return Filter{Field: name, Op: op, Value: v}, nil
+}
+
+func (s Schema) parseSort(raw string) ([]Sort, error) {
+	if raw == "" {
+		return s.Default, nil
+	}
+
+	var sort []Sort
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(part, "-")
+
+		field, ok := s.Fields[name]
+		if !ok || !field.Sortable {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownField, name)
+		}
+
+		sort = append(sort, Sort{Field: name, Desc: desc})
+	}
+
+	return sort, nil
+}
+
+func parseValue(kind Kind, raw string) (any, error) {
+	switch kind {
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case UUID:
+		return uuid.Parse(raw)
+	case Time:
+		if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, raw)
+	default:
+		return raw, nil
+	}
+}
+
+// sortKey — сигнатура сортировки; курсор действителен только для неё
+func (l *List) sortKey() string {
+	parts := make([]string, 0, len(l.Sort))
+	for _, s := range l.Sort {
+		if s.Desc {
+			parts = append(parts, "-"+s.Field)
+		} else {
+			parts = append(parts, s.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// LogValue выводит в лог только разобранные параметры, без схемы
+func (l *List) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("filters", l.Filters),
+		slog.String("sort", l.sortKey()),
+		slog.Int("limit", l.Limit),
+		slog.Bool("cursor", l.After != nil),
+	)
+}
+
+// Key — короткий ключ кеша для страницы: одинаковые запросы дают один ключ
+// независимо от порядка параметров в URL
+func (l *List) Key() string {
+	filters := make([]string, 0, len(l.Filters))
+	for _, f := range l.Filters {
+		filters = append(filters, fmt.Sprintf("%s:%s:%v", f.Field, f.Op, f.Value))
+	}
+	slices.Sort(filters)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "f=%s|s=%s|l=%d", strings.Join(filters, ";"), l.sortKey(), l.Limit)
+	if l.After != nil {
+		fmt.Fprintf(&b, "|c=%v:%s", l.After.Values, l.After.ID)
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,122 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Price     int       `json:"price"`
+	Name      string    `json:"service_name"`
+}
+
+func TestParse_FiltersAndSort(t *testing.T) {
+	categoryID := uuid.New()
+	values := url.Values{
+		"filter[category_id]":  {categoryID.String()},
+		"filter[price][gte]":   {"100"},
+		"filter[status][in]":   {"active,past_due"},
+		"sort":                 {"-price,service_name"},
+		"limit":                {"5"},
+		"unrelated[parameter]": {"x"},
+	}
+
+	l, err := Parse(values, Subscriptions)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, l.Limit)
+	assert.Equal(t, []Sort{{Field: "price", Desc: true}, {Field: "service_name"}}, l.Sort)
+	assert.ElementsMatch(t, []Filter{
+		{Field: "category_id", Op: Eq, Value: categoryID},
+		{Field: "price", Op: Gte, Value: int64(100)},
+		{Field: "status", Op: In, Value: []any{"active", "past_due"}},
+	}, l.Filters)
+}
+
+func TestParse_Defaults(t *testing.T) {
+	l, err := Parse(url.Values{"limit": {"1000"}}, Payments)
+
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultLimit, l.Limit)
+	assert.Equal(t, []Sort{{Field: "created_at"}}, l.Sort)
+}
+
+func TestParse_RejectsFieldsOutsideWhitelist(t *testing.T) {
+	_, err := Parse(url.Values{"filter[password]": {"x"}}, Users)
+	assert.ErrorIs(t, err, ErrUnknownField)
+
+	_, err = Parse(url.Values{"sort": {"role"}}, Users)
+	assert.ErrorIs(t, err, ErrUnknownField)
+
+	_, err = Parse(url.Values{"filter[email][gt]": {"a"}}, Users)
+	assert.ErrorIs(t, err, ErrUnknownOp)
+
+	_, err = Parse(url.Values{"filter[amount]": {"ten"}}, Payments)
+	assert.ErrorIs(t, err, ErrInvalidValue)
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	l, err := Parse(url.Values{"sort": {"-price,service_name"}, "limit": {"2"}}, Subscriptions)
+	assert.NoError(t, err)
+
+	last := item{ID: uuid.New(), Price: 300, Name: "Кинопоиск"}
+	cursor := NextCursor(l, []item{{ID: uuid.New(), Price: 500}, last})
+	assert.NotNil(t, cursor)
+
+	next, err := Parse(url.Values{"sort": {"-price,service_name"}, "limit": {"2"}, "cursor": {*cursor}}, Subscriptions)
+	assert.NoError(t, err)
+	assert.Equal(t, &Cursor{Values: []any{int64(300), "Кинопоиск"}, ID: last.ID}, next.After)
+
+	where, args := next.keyset()
+	assert.Equal(t,
+		"((subscriptions.price < ?) OR (subscriptions.price = ? AND services.name > ?) OR "+
+			"(subscriptions.price = ? AND services.name = ? AND subscriptions.id > ?))",
+		where)
+	assert.Equal(t, []any{int64(300), int64(300), "Кинопоиск", int64(300), "Кинопоиск", last.ID}, args)
+}
+
+func TestCursor_RejectsOtherSort(t *testing.T) {
+	l, _ := Parse(url.Values{"limit": {"1"}}, Subscriptions)
+	cursor := NextCursor(l, []item{{ID: uuid.New(), CreatedAt: time.Now()}})
+	assert.NotNil(t, cursor)
+
+	_, err := Parse(url.Values{"sort": {"price"}, "cursor": {*cursor}}, Subscriptions)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = Parse(url.Values{"cursor": {"not-a-cursor"}}, Subscriptions)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestNextCursor_LastPage(t *testing.T) {
+	l, _ := Parse(url.Values{"limit": {"3"}}, Services)
+
+	assert.Nil(t, NextCursor(l, []item{{ID: uuid.New()}}))
+}
+
+func TestList_KeyIgnoresParameterOrder(t *testing.T) {
+	a := &List{
+		Filters: []Filter{{Field: "price", Op: Gte, Value: int64(100)}, {Field: "status", Op: Eq, Value: "active"}},
+		Sort:    []Sort{{Field: "price", Desc: true}},
+		Limit:   20,
+	}
+	b := &List{
+		Filters: []Filter{a.Filters[1], a.Filters[0]},
+		Sort:    a.Sort,
+		Limit:   20,
+	}
+
+	assert.Equal(t, a.Key(), b.Key())
+
+	b.Limit = 10
+	assert.NotEqual(t, a.Key(), b.Key())
+
+	b.Limit = 20
+	b.After = &Cursor{Values: []any{int64(100)}, ID: uuid.New()}
+	assert.NotEqual(t, a.Key(), b.Key())
+}
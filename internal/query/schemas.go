@@ -0,0 +1,61 @@
+package query
+
+// Белые списки полей list-эндпоинтов. Сортировать можно только по NOT NULL
+// колонкам: NULL не сравнивается в условии курсора
+
+var byCreatedAt = []Sort{{Field: "created_at"}}
+
+var Users = Schema{
+	IDColumn: "id",
+	Default:  byCreatedAt,
+	Fields: map[string]Field{
+		"created_at": {Column: "created_at", Kind: Time, Filter: true, Sortable: true},
+		"email":      {Column: "email", Kind: String, Filter: true, Sortable: true},
+		"first_name": {Column: "first_name", Kind: String, Filter: true, Sortable: true},
+		"last_name":  {Column: "last_name", Kind: String, Filter: true, Sortable: true},
+		"role":       {Column: "roles", Kind: String, Filter: true},
+	},
+}
+
+var Services = Schema{
+	IDColumn: "id",
+	Default:  byCreatedAt,
+	Fields: map[string]Field{
+		"id":          {Column: "id", Kind: UUID, Filter: true},
+		"created_at":  {Column: "created_at", Kind: Time, Filter: true, Sortable: true},
+		"name":        {Column: "name", Kind: String, Filter: true, Sortable: true},
+		"category_id": {Column: "category_id", Kind: UUID, Filter: true},
+		"website":     {Column: "website", Kind: String, Filter: true},
+	},
+}
+
+var Subscriptions = Schema{
+	IDColumn: "subscriptions.id",
+	Default:  byCreatedAt,
+	Fields: map[string]Field{
+		"created_at":   {Column: "subscriptions.created_at", Kind: Time, Filter: true, Sortable: true},
+		"start_date":   {Column: "subscriptions.start_date", Kind: Time, Filter: true, Sortable: true},
+		"end_date":     {Column: "subscriptions.end_date", Kind: Time, Filter: true},
+		"price":        {Column: "subscriptions.price", Kind: Int, Filter: true, Sortable: true},
+		"status":       {Column: "subscriptions.status", Kind: String, Filter: true},
+		"user_id":      {Column: "subscriptions.user_id", Kind: UUID, Filter: true},
+		"service_id":   {Column: "subscriptions.service_id", Kind: UUID, Filter: true},
+		"service_name": {Column: "services.name", Kind: String, Filter: true, Sortable: true},
+		"category_id":  {Column: "services.category_id", Kind: UUID, Filter: true},
+	},
+}
+
+var Payments = Schema{
+	IDColumn: "id",
+	Default:  byCreatedAt,
+	Fields: map[string]Field{
+		"created_at":      {Column: "created_at", Kind: Time, Filter: true, Sortable: true},
+		"paid_at":         {Column: "paid_at", Kind: Time, Filter: true},
+		"amount":          {Column: "amount", Kind: Int, Filter: true, Sortable: true},
+		"currency":        {Column: "currency", Kind: String, Filter: true},
+		"payment_status":  {Column: "payment_status", Kind: String, Filter: true},
+		"provider":        {Column: "provider", Kind: String, Filter: true},
+		"subscription_id": {Column: "subscription_id", Kind: UUID, Filter: true},
+		"order_id":        {Column: "order_id", Kind: UUID, Filter: true},
+	},
+}
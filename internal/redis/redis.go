@@ -2,15 +2,14 @@ package redis
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-var Ctx = context.Background()
-
-func New(addr string) *redis.Client {
+// New создаёт клиент и проверяет соединение. Клиент возвращается и при ошибке:
+// go-redis переподключается сам, когда Redis станет доступен
+func New(ctx context.Context, addr string) (*redis.Client, error) {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:         addr,
 		DialTimeout:  5 * time.Second,
@@ -18,9 +17,9 @@ func New(addr string) *redis.Client {
 		WriteTimeout: 3 * time.Second,
 	})
 
-	if err := rdb.Ping(Ctx).Err(); err != nil {
-		log.Fatalf("Redis error: %v", err)
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		return rdb, err
 	}
 
-	return rdb
+	return rdb, nil
 }
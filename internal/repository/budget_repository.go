@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/models"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BudgetRepository interface {
+	Create(ctx context.Context, budget *models.Budget) error
+
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Budget, error)
+
+	ListUserIDs(ctx context.Context) ([]uuid.UUID, error)
+
+	GetByID(ctx context.Context, id string) (*models.Budget, error)
+
+	Update(ctx context.Context, budget *models.Budget) error
+
+	Delete(ctx context.Context, id string) error
+}
+
+type gormBudgetRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewBudgetRepository(db *gorm.DB, logger *slog.Logger) BudgetRepository {
+	return &gormBudgetRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+func (r *gormBudgetRepository) Create(ctx context.Context, budget *models.Budget) error {
+	op := "repository.budget.create"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("budget", budget),
+	)
+
+	if err := conn(ctx, r.DB).Create(budget).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormBudgetRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Budget, error) {
+	op := "repository.budget.list_by_user"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("user_id", userID),
+	)
+
+	var budgets []models.Budget
+	if err := conn(ctx, r.DB).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&budgets).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+func (r *gormBudgetRepository) ListUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	op := "repository.budget.list_user_ids"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+	)
+
+	var ids []uuid.UUID
+	if err := conn(ctx, r.DB).
+		Model(&models.Budget{}).
+		Distinct("user_id").
+		Pluck("user_id", &ids).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *gormBudgetRepository) GetByID(ctx context.Context, id string) (*models.Budget, error) {
+	op := "repository.budget.get_by_id"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id),
+	)
+
+	var budget models.Budget
+	if err := conn(ctx, r.DB).First(&budget, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return &budget, nil
+}
+
+func (r *gormBudgetRepository) Update(ctx context.Context, budget *models.Budget) error {
+	op := "repository.budget.update"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("budget", budget),
+	)
+
+	if err := conn(ctx, r.DB).Save(budget).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormBudgetRepository) Delete(ctx context.Context, id string) error {
+	op := "repository.budget.delete"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id),
+	)
+
+	if err := conn(ctx, r.DB).Delete(&models.Budget{}, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CatalogRepository interface {
+	// CategoryCounts считает сервисы и активных подписчиков по всем категориям
+	CategoryCounts(ctx context.Context, at time.Time) ([]dto.CategoryCounts, error)
+
+	// Snapshot возвращает все категории и сервисы каталога
+	Snapshot(ctx context.Context) ([]models.Category, []models.Service, error)
+
+	// Import создаёт категории и сервисы и обновляет существующие сервисы в одной транзакции
+	Import(
+		ctx context.Context,
+		categories []models.Category,
+		created []models.Service,
+		updated []models.Service,
+	) error
+}
+
+type gormCatalogRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewCatalogRepository(db *gorm.DB, logger *slog.Logger) CatalogRepository {
+	return &gormCatalogRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// categoryCountsQuery раскрывает каждую категорию в её поддерево (root_id → id)
+// и считает собственные и суммарные по поддереву агрегаты одним проходом
+const categoryCountsQuery = `
+WITH RECURSIVE tree AS (
+	SELECT id AS root_id, id, 0 AS depth
+	FROM categories
+	WHERE deleted_at IS NULL
+	UNION ALL
+	SELECT tree.root_id, c.id, tree.depth + 1
+	FROM categories c
+	JOIN tree ON c.parent_id = tree.id
+	WHERE c.deleted_at IS NULL AND tree.depth < @max_depth
+)
+SELECT
+	tree.root_id AS category_id,
+	COUNT(DISTINCT services.id) FILTER (WHERE tree.depth = 0) AS services_count,
+	COUNT(DISTINCT subscriptions.user_id) FILTER (WHERE tree.depth = 0) AS active_subscribers,
+	COUNT(DISTINCT services.id) AS total_services,
+	COUNT(DISTINCT subscriptions.user_id) AS total_subscribers
+FROM tree
+LEFT JOIN services ON services.category_id = tree.id AND services.deleted_at IS NULL
+LEFT JOIN subscriptions ON subscriptions.service_id = services.id
+	AND subscriptions.deleted_at IS NULL
+	AND subscriptions.status = @status
+	AND subscriptions.start_date <= @at
+	AND (subscriptions.end_date IS NULL OR subscriptions.end_date > @at)
+GROUP BY tree.root_id`
+
+func (r *gormCatalogRepository) CategoryCounts(ctx context.Context, at time.Time) ([]dto.CategoryCounts, error) {
+	op := "repository.catalog.category_counts"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Time("at", at),
+	)
+
+	var rows []dto.CategoryCounts
+
+	if err := conn(ctx, r.DB).Raw(categoryCountsQuery, map[string]any{
+		"status":    models.SubscriptionActive,
+		"at":        at,
+		"max_depth": maxCategoryDepth,
+	}).Scan(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *gormCatalogRepository) Snapshot(ctx context.Context) ([]models.Category, []models.Service, error) {
+	op := "repository.catalog.snapshot"
+
+	r.logger.Debug("db call", slog.String("op", op))
+
+	var categories []models.Category
+	if err := conn(ctx, r.DB).
+		Order("name ASC").
+		Find(&categories).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, nil, err
+	}
+
+	var services []models.Service
+	if err := conn(ctx, r.DB).
+		Order("name ASC").
+		Find(&services).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, nil, err
+	}
+
+	return categories, services, nil
+}
+
+func (r *gormCatalogRepository) Import(
+	ctx context.Context,
+	categories []models.Category,
+	created []models.Service,
+	updated []models.Service,
+) error {
+	op := "repository.catalog.import"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Int("categories", len(categories)),
+		slog.Int("created", len(created)),
+		slog.Int("updated", len(updated)),
+	)
+
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if len(categories) > 0 {
+			if err := tx.Create(&categories).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(created) > 0 {
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range updated {
+			if err := tx.Model(&updated[i]).
+				Select("website", "logo_url").
+				Updates(&updated[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
@@ -11,20 +11,45 @@ import (
 )
 
 type CategoryRepository interface {
-	Create(category *models.Category) error
+	Create(ctx context.Context, category *models.Category) error
 
 	List(ctx context.Context,
 		limit int,
 		lastCreatedAt *time.Time,
 		lastID *uuid.UUID) ([]models.Category, error)
 
-	GetByID(id string) (*models.Category, error)
+	GetByID(ctx context.Context, id string) (*models.Category, error)
 
-	Update(category *models.Category) error
+	Update(ctx context.Context, category *models.Category) error
 
-	Delete(id string) error
+	Delete(ctx context.Context, id string) error
+
+	// All возвращает все категории, упорядоченные по названию
+	All(ctx context.Context) ([]models.Category, error)
+
+	// Ancestors возвращает путь от корня до категории включительно
+	Ancestors(ctx context.Context, id uuid.UUID) ([]models.Category, error)
+
+	// SubtreeIDs возвращает id категории и всех её потомков
+	SubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+
+	Children(ctx context.Context, id uuid.UUID) ([]models.Category, error)
+
+	// DeleteAndReparent удаляет категорию и переносит её дочерние категории к parentID
+	DeleteAndReparent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error
+
+	// LockTree берёт блокировку дерева категорий до конца транзакции: проверка
+	// на цикл и перенос должны идти без параллельных перемещений
+	LockTree(ctx context.Context) error
 }
 
+// Ограничение глубины рекурсии; циклы запрещены на уровне сервиса,
+// а ограничение не даёт запросу зациклиться на испорченных данных
+const maxCategoryDepth = 32
+
+// Ключ advisory-блокировки дерева категорий
+const categoryTreeLockKey int64 = 0x63617465676f7279
+
 type gormCategoryRepository struct {
 	DB     *gorm.DB
 	logger *slog.Logger
@@ -37,7 +62,7 @@ func NewCategoryRepository(db *gorm.DB, logger *slog.Logger) CategoryRepository
 	}
 }
 
-func (r *gormCategoryRepository) Create(category *models.Category) error {
+func (r *gormCategoryRepository) Create(ctx context.Context, category *models.Category) error {
 	op := "repository.category.create"
 
 	r.logger.Debug("db call",
@@ -45,7 +70,7 @@ func (r *gormCategoryRepository) Create(category *models.Category) error {
 		slog.Any("category", category),
 	)
 
-	if err := r.DB.Create(category).Error; err != nil {
+	if err := conn(ctx, r.DB).Create(category).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -67,12 +92,12 @@ func (r *gormCategoryRepository) List(
 
 	rows := make([]models.Category, 0, limit)
 
-	q := r.DB.
-		WithContext(ctx).
+	q := conn(ctx, r.DB).
 		Model(&models.Category{}).
 		Select(`
 	id,
 	name,
+	parent_id,
 	created_at
 	`).
 		Order("created_at ASC").
@@ -93,7 +118,7 @@ func (r *gormCategoryRepository) List(
 	return rows, nil
 }
 
-func (r *gormCategoryRepository) GetByID(id string) (*models.Category, error) {
+func (r *gormCategoryRepository) GetByID(ctx context.Context, id string) (*models.Category, error) {
 	op := "repository.category.get_by_id"
 
 	r.logger.Debug("db call",
@@ -102,7 +127,7 @@ func (r *gormCategoryRepository) GetByID(id string) (*models.Category, error) {
 	)
 
 	var category models.Category
-	if err := r.DB.First(&category, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).First(&category, "id = ?", id).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return nil, err
 	}
@@ -110,7 +135,7 @@ func (r *gormCategoryRepository) GetByID(id string) (*models.Category, error) {
 	return &category, nil
 }
 
-func (r *gormCategoryRepository) Update(category *models.Category) error {
+func (r *gormCategoryRepository) Update(ctx context.Context, category *models.Category) error {
 	op := "repository.category.update"
 
 	r.logger.Debug("db call",
@@ -118,7 +143,7 @@ func (r *gormCategoryRepository) Update(category *models.Category) error {
 		slog.Any("category", category),
 	)
 
-	if err := r.DB.Save(category).Error; err != nil {
+	if err := conn(ctx, r.DB).Save(category).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -126,7 +151,7 @@ func (r *gormCategoryRepository) Update(category *models.Category) error {
 	return nil
 }
 
-func (r *gormCategoryRepository) Delete(id string) error {
+func (r *gormCategoryRepository) Delete(ctx context.Context, id string) error {
 	op := "repository.category.delete"
 
 	r.logger.Debug("db call",
@@ -134,10 +159,155 @@ func (r *gormCategoryRepository) Delete(id string) error {
 		slog.String("id", id),
 	)
 
-	if err := r.DB.Delete(&models.Category{}, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).Delete(&models.Category{}, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormCategoryRepository) All(ctx context.Context) ([]models.Category, error) {
+	op := "repository.category.all"
+
+	r.logger.Debug("db call", slog.String("op", op))
+
+	var categories []models.Category
+	if err := conn(ctx, r.DB).
+		Order("name ASC").
+		Order("id ASC").
+		Find(&categories).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *gormCategoryRepository) Ancestors(ctx context.Context, id uuid.UUID) ([]models.Category, error) {
+	op := "repository.category.ancestors"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id.String()),
+	)
+
+	var categories []models.Category
+	if err := conn(ctx, r.DB).Raw(`
+		WITH RECURSIVE path AS (
+			SELECT categories.*, 0 AS depth
+			FROM categories
+			WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.*, path.depth + 1
+			FROM categories c
+			JOIN path ON c.id = path.parent_id
+			WHERE c.deleted_at IS NULL AND path.depth < ?
+		)
+		SELECT * FROM path ORDER BY depth DESC
+	`, id, maxCategoryDepth).Scan(&categories).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (r *gormCategoryRepository) SubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	op := "repository.category.subtree_ids"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id.String()),
+	)
+
+	var ids []uuid.UUID
+	if err := conn(ctx, r.DB).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth
+			FROM categories
+			WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, subtree.depth + 1
+			FROM categories c
+			JOIN subtree ON c.parent_id = subtree.id
+			WHERE c.deleted_at IS NULL AND subtree.depth < ?
+		)
+		SELECT id FROM subtree
+	`, id, maxCategoryDepth).Scan(&ids).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *gormCategoryRepository) Children(ctx context.Context, id uuid.UUID) ([]models.Category, error) {
+	op := "repository.category.children"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id.String()),
+	)
+
+	var children []models.Category
+	if err := conn(ctx, r.DB).
+		Where("parent_id = ?", id).
+		Order("name ASC").
+		Find(&children).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return children, nil
+}
+
+func (r *gormCategoryRepository) DeleteAndReparent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	op := "repository.category.delete_and_reparent"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id.String()),
+		slog.Any("parent_id", parentID),
+	)
+
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := lockCategoryTree(tx); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Category{}).
+			Where("parent_id = ?", id).
+			Update("parent_id", parentID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.Category{}, "id = ?", id).Error
+	})
+	if err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
 
 	return nil
 }
+
+func (r *gormCategoryRepository) LockTree(ctx context.Context) error {
+	op := "repository.category.lock_tree"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+	)
+
+	if err := lockCategoryTree(conn(ctx, r.DB)); err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// lockCategoryTree держит блокировку до конца текущей транзакции
+func lockCategoryTree(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", categoryTreeLockKey).Error
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/models"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CouponRepository interface {
+	Create(ctx context.Context, coupon *models.Coupon) error
+
+	List(
+		ctx context.Context,
+		limit int,
+		lastCreatedAt *time.Time,
+		lastID *uuid.UUID,
+	) ([]models.Coupon, error)
+
+	GetByID(ctx context.Context, id string) (*models.Coupon, error)
+
+	GetByCode(ctx context.Context, code string) (*models.Coupon, error)
+
+	Update(ctx context.Context, coupon *models.Coupon) error
+
+	Delete(ctx context.Context, id string) error
+
+	// Redeem под блокировкой купона передаёт в check число уже сделанных погашений
+	// (всего и для пользователя) и сохраняет redemption, если check не вернул ошибку
+	Redeem(
+		ctx context.Context,
+		redemption *models.Redemption,
+		check func(total, perUser int64) error,
+	) error
+}
+
+type gormCouponRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewCouponRepository(db *gorm.DB, logger *slog.Logger) CouponRepository {
+	return &gormCouponRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+func (r *gormCouponRepository) Create(ctx context.Context, coupon *models.Coupon) error {
+	op := "repository.coupon.create"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("coupon", coupon),
+	)
+
+	if err := conn(ctx, r.DB).Create(coupon).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormCouponRepository) List(
+	ctx context.Context,
+	limit int,
+	lastCreatedAt *time.Time,
+	lastID *uuid.UUID,
+) ([]models.Coupon, error) {
+	op := "repository.coupon.list"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Int("limit", limit),
+		slog.Any("lastCreatedAt", lastCreatedAt),
+		slog.Any("lastID", lastID),
+	)
+
+	coupons := make([]models.Coupon, 0, limit)
+
+	q := conn(ctx, r.DB).
+		Model(&models.Coupon{}).
+		Order("created_at ASC").
+		Order("id ASC").
+		Limit(limit)
+
+	if lastCreatedAt != nil && lastID != nil {
+		q = q.Where(
+			"(created_at > ?) OR (created_at = ? AND id > ?)",
+			*lastCreatedAt,
+			*lastCreatedAt,
+			*lastID,
+		)
+	}
+
+	if err := q.Find(&coupons).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return coupons, nil
+}
+
+func (r *gormCouponRepository) GetByID(ctx context.Context, id string) (*models.Coupon, error) {
+	op := "repository.coupon.get_by_id"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id),
+	)
+
+	var coupon models.Coupon
+	if err := conn(ctx, r.DB).First(&coupon, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return &coupon, nil
+}
+
+func (r *gormCouponRepository) GetByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	op := "repository.coupon.get_by_code"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("code", code),
+	)
+
+	var coupon models.Coupon
+	if err := conn(ctx, r.DB).First(&coupon, "code = ?", code).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return &coupon, nil
+}
+
+func (r *gormCouponRepository) Update(ctx context.Context, coupon *models.Coupon) error {
+	op := "repository.coupon.update"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("coupon", coupon),
+	)
+
+	if err := conn(ctx, r.DB).Save(coupon).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormCouponRepository) Delete(ctx context.Context, id string) error {
+	op := "repository.coupon.delete"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id),
+	)
+
+	if err := conn(ctx, r.DB).Delete(&models.Coupon{}, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormCouponRepository) Redeem(
+	ctx context.Context,
+	redemption *models.Redemption,
+	check func(total, perUser int64) error,
+) error {
+	op := "repository.coupon.redeem"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("redemption", redemption),
+	)
+
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		// блокируем купон, чтобы параллельные погашения не обошли лимиты
+		var coupon models.Coupon
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&coupon, "id = ?", redemption.CouponID).Error; err != nil {
+			return err
+		}
+
+		var total, perUser int64
+		if err := tx.Model(&models.Redemption{}).
+			Where("coupon_id = ?", redemption.CouponID).
+			Count(&total).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Redemption{}).
+			Where("coupon_id = ? AND user_id = ?", redemption.CouponID, redemption.UserID).
+			Count(&perUser).Error; err != nil {
+			return err
+		}
+
+		if err := check(total, perUser); err != nil {
+			return err
+		}
+
+		return tx.Create(redemption).Error
+	})
+	if err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
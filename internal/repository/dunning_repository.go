@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/models"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DunningRepository interface {
+	GetPolicy(ctx context.Context) (*models.DunningPolicy, error)
+
+	SavePolicy(ctx context.Context, policy *models.DunningPolicy) error
+
+	// CreateCase возвращает gorm.ErrDuplicatedKey, если у подписки уже есть
+	// открытый процесс: уникальность держит частичный индекс по subscription_id
+	CreateCase(ctx context.Context, dunningCase *models.DunningCase) error
+
+	GetOpenCase(ctx context.Context, subscriptionID uuid.UUID) (*models.DunningCase, error)
+
+	// ClaimDue забирает открытые процессы, у которых подошло время повторной попытки,
+	// и сдвигает им next_retry_at на lease: пока аренда не истекла, другие реплики
+	// и следующий тик воркера их не видят. Строки, заблокированные другим
+	// забором, пропускаются
+	ClaimDue(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]models.DunningCase, error)
+
+	ListCases(ctx context.Context, status models.DunningStatus, limit int) ([]models.DunningCase, error)
+
+	// LockCase перечитывает процесс с блокировкой строки до конца транзакции
+	LockCase(ctx context.Context, id uuid.UUID) (*models.DunningCase, error)
+
+	UpdateCase(ctx context.Context, dunningCase *models.DunningCase) error
+}
+
+type gormDunningRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewDunningRepository(db *gorm.DB, logger *slog.Logger) DunningRepository {
+	return &gormDunningRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+func (r *gormDunningRepository) GetPolicy(ctx context.Context) (*models.DunningPolicy, error) {
+	op := "repository.dunning.get_policy"
+
+	r.logger.Debug("db call", slog.String("op", op))
+
+	var policy models.DunningPolicy
+	if err := conn(ctx, r.DB).Order("created_at ASC").First(&policy).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+func (r *gormDunningRepository) SavePolicy(ctx context.Context, policy *models.DunningPolicy) error {
+	op := "repository.dunning.save_policy"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("policy", policy),
+	)
+
+	if err := conn(ctx, r.DB).Save(policy).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormDunningRepository) CreateCase(ctx context.Context, dunningCase *models.DunningCase) error {
+	op := "repository.dunning.create_case"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("case", dunningCase),
+	)
+
+	// DO NOTHING вместо ошибки уникальности: ошибка оборвала бы всю транзакцию
+	res := conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(dunningCase)
+	if res.Error != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", res.Error))
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrDuplicatedKey
+	}
+
+	return nil
+}
+
+func (r *gormDunningRepository) GetOpenCase(ctx context.Context, subscriptionID uuid.UUID) (*models.DunningCase, error) {
+	op := "repository.dunning.get_open_case"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("subscription_id", subscriptionID),
+	)
+
+	var dunningCase models.DunningCase
+	if err := conn(ctx, r.DB).
+		Where("subscription_id = ? AND status = ?", subscriptionID, models.DunningOpen).
+		First(&dunningCase).Error; err != nil {
+		return nil, err
+	}
+
+	return &dunningCase, nil
+}
+
+func (r *gormDunningRepository) ClaimDue(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]models.DunningCase, error) {
+	op := "repository.dunning.claim_due"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Time("at", at),
+		slog.Duration("lease", lease),
+		slog.Int("limit", limit),
+	)
+
+	var cases []models.DunningCase
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_retry_at <= ?", models.DunningOpen, at).
+			Order("next_retry_at ASC").
+			Limit(limit).
+			Find(&cases).Error; err != nil {
+			return err
+		}
+		if len(cases) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(cases))
+		for i := range cases {
+			ids[i] = cases[i].ID
+		}
+
+		return tx.Model(&models.DunningCase{}).
+			Where("id IN ?", ids).
+			Update("next_retry_at", at.Add(lease)).Error
+	})
+	if err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return cases, nil
+}
+
+func (r *gormDunningRepository) ListCases(ctx context.Context, status models.DunningStatus, limit int) ([]models.DunningCase, error) {
+	op := "repository.dunning.list_cases"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("status", string(status)),
+		slog.Int("limit", limit),
+	)
+
+	q := conn(ctx, r.DB).
+		Order("created_at DESC").
+		Limit(limit)
+
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	var cases []models.DunningCase
+	if err := q.Find(&cases).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return cases, nil
+}
+
+func (r *gormDunningRepository) LockCase(ctx context.Context, id uuid.UUID) (*models.DunningCase, error) {
+	op := "repository.dunning.lock_case"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("id", id),
+	)
+
+	var dunningCase models.DunningCase
+	if err := conn(ctx, r.DB).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&dunningCase, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return &dunningCase, nil
+}
+
+func (r *gormDunningRepository) UpdateCase(ctx context.Context, dunningCase *models.DunningCase) error {
+	op := "repository.dunning.update_case"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("case", dunningCase),
+	)
+
+	if err := conn(ctx, r.DB).Save(dunningCase).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/models"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FavoriteRepository interface {
+	// Add добавляет сервис в избранное; повторное добавление ничего не меняет
+	Add(ctx context.Context, favorite *models.Favorite) error
+
+	Remove(ctx context.Context, userID, serviceID uuid.UUID) error
+
+	// ServiceIDs возвращает избранные сервисы пользователя, новые первыми
+	ServiceIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+	Count(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+type gormFavoriteRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewFavoriteRepository(db *gorm.DB, logger *slog.Logger) FavoriteRepository {
+	return &gormFavoriteRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+func (r *gormFavoriteRepository) Add(ctx context.Context, favorite *models.Favorite) error {
+	op := "repository.favorite.add"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("user_id", favorite.UserID.String()),
+		slog.String("service_id", favorite.ServiceID.String()),
+	)
+
+	if err := conn(ctx, r.DB).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Omit(clause.Associations).
+		Create(favorite).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormFavoriteRepository) Remove(ctx context.Context, userID, serviceID uuid.UUID) error {
+	op := "repository.favorite.remove"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+		slog.String("service_id", serviceID.String()),
+	)
+
+	if err := conn(ctx, r.DB).
+		Where("user_id = ? AND service_id = ?", userID, serviceID).
+		Delete(&models.Favorite{}).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormFavoriteRepository) ServiceIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	op := "repository.favorite.service_ids"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+	)
+
+	var ids []uuid.UUID
+	if err := conn(ctx, r.DB).
+		Model(&models.Favorite{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Pluck("service_id", &ids).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *gormFavoriteRepository) Count(ctx context.Context, userID uuid.UUID) (int64, error) {
+	op := "repository.favorite.count"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+	)
+
+	var count int64
+	if err := conn(ctx, r.DB).
+		Model(&models.Favorite{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return 0, err
+	}
+
+	return count, nil
+}
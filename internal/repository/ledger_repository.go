@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LedgerRepository interface {
+	// Post под блокировкой платежа передаёт в check текущие итоги журнала
+	// и сохраняет проводки, если check не вернул ошибку
+	Post(
+		ctx context.Context,
+		paymentID uuid.UUID,
+		entries []models.LedgerEntry,
+		check func(summary dto.PaymentLedger) error,
+	) error
+
+	ListByPayment(ctx context.Context, paymentID uuid.UUID) ([]models.LedgerEntry, error)
+
+	AccountBalances(ctx context.Context) ([]dto.AccountBalance, error)
+
+	// PaymentMismatches возвращает платежи, сумма которых расходится со списаниями в журнале
+	PaymentMismatches(ctx context.Context) ([]dto.PaymentMismatch, error)
+}
+
+type gormLedgerRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewLedgerRepository(db *gorm.DB, logger *slog.Logger) LedgerRepository {
+	return &gormLedgerRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+func (r *gormLedgerRepository) Post(
+	ctx context.Context,
+	paymentID uuid.UUID,
+	entries []models.LedgerEntry,
+	check func(summary dto.PaymentLedger) error,
+) error {
+	op := "repository.ledger.post"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("payment_id", paymentID),
+		slog.Int("entries", len(entries)),
+	)
+
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		var payment models.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&payment, "id = ?", paymentID).Error; err != nil {
+			return err
+		}
+
+		summary, err := paymentLedgerSummary(tx, paymentID)
+		if err != nil {
+			return err
+		}
+
+		if err := check(summary); err != nil {
+			return err
+		}
+
+		return tx.Create(&entries).Error
+	})
+	if err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormLedgerRepository) ListByPayment(ctx context.Context, paymentID uuid.UUID) ([]models.LedgerEntry, error) {
+	op := "repository.ledger.list_by_payment"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("payment_id", paymentID),
+	)
+
+	var entries []models.LedgerEntry
+	if err := conn(ctx, r.DB).
+		Where("payment_id = ?", paymentID).
+		Order("created_at ASC").
+		Order("transaction_id ASC").
+		Find(&entries).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *gormLedgerRepository) AccountBalances(ctx context.Context) ([]dto.AccountBalance, error) {
+	op := "repository.ledger.account_balances"
+
+	r.logger.Debug("db call", slog.String("op", op))
+
+	var balances []dto.AccountBalance
+	if err := conn(ctx, r.DB).
+		Model(&models.LedgerEntry{}).
+		Select(`
+			account,
+			currency,
+			SUM(debit) AS debit,
+			SUM(credit) AS credit,
+			SUM(debit) - SUM(credit) AS balance
+		`).
+		Group("account, currency").
+		Order("currency ASC, account ASC").
+		Scan(&balances).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+func (r *gormLedgerRepository) PaymentMismatches(ctx context.Context) ([]dto.PaymentMismatch, error) {
+	op := "repository.ledger.payment_mismatches"
+
+	r.logger.Debug("db call", slog.String("op", op))
+
+	charged := "COALESCE(SUM(e.debit) FILTER (WHERE e.kind = 'charge' AND e.account = 'provider_clearing'), 0)"
+
+	var rows []dto.PaymentMismatch
+	if err := conn(ctx, r.DB).
+		Table("payments AS p").
+		Select(`
+			p.id AS payment_id,
+			p.payment_status,
+			p.currency,
+			p.amount,
+			`+charged+` AS charged
+		`).
+		Joins("LEFT JOIN ledger_entries e ON e.payment_id = p.id").
+		Where("p.deleted_at IS NULL").
+		Group("p.id, p.payment_status, p.currency, p.amount").
+		Having(
+			"(p.payment_status = ? AND "+charged+" <> p.amount) OR (p.payment_status <> ? AND "+charged+" <> 0)",
+			models.PaymentSucces, models.PaymentSucces,
+		).
+		Order("p.id ASC").
+		Scan(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// paymentLedgerSummary считает итоги журнала по платежу внутри переданной транзакции
+func paymentLedgerSummary(tx *gorm.DB, paymentID uuid.UUID) (dto.PaymentLedger, error) {
+	var summary dto.PaymentLedger
+
+	err := tx.Model(&models.LedgerEntry{}).
+		Select(`
+			COALESCE(SUM(debit) FILTER (WHERE kind = 'charge' AND account = 'provider_clearing'), 0) AS charged,
+			COALESCE(SUM(credit) FILTER (WHERE kind = 'refund' AND account = 'provider_clearing'), 0) AS refunded,
+			COALESCE(SUM(credit - debit) FILTER (WHERE kind = 'adjustment' AND account = 'revenue'), 0) AS adjusted
+		`).
+		Where("payment_id = ?", paymentID).
+		Scan(&summary).Error
+
+	return summary, err
+}
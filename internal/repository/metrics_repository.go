@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MetricsRepository interface {
+	SubscriptionsStartedBefore(
+		ctx context.Context,
+		to time.Time,
+	) ([]dto.MetricsSubscriptionRow, error)
+
+	RevenueByMonth(
+		ctx context.Context,
+		f dto.MetricsFilter,
+	) ([]dto.RevenueRow, error)
+}
+
+type gormMetricsRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewMetricsRepository(db *gorm.DB, logger *slog.Logger) MetricsRepository {
+	return &gormMetricsRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+func (r *gormMetricsRepository) SubscriptionsStartedBefore(
+	ctx context.Context,
+	to time.Time,
+) ([]dto.MetricsSubscriptionRow, error) {
+	op := "repository.metrics.subscriptions_started_before"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Time("to", to),
+	)
+
+	var rows []dto.MetricsSubscriptionRow
+
+	if err := conn(ctx, r.DB).
+		Model(&models.Subscription{}).
+		Select("user_id, start_date, end_date, price, billing_interval AS interval, paused_until, status").
+		Where("start_date <= ?", to).
+		Order("start_date ASC").
+		Scan(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *gormMetricsRepository) RevenueByMonth(
+	ctx context.Context,
+	f dto.MetricsFilter,
+) ([]dto.RevenueRow, error) {
+	op := "repository.metrics.revenue_by_month"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("filter", f),
+	)
+
+	var rows []dto.RevenueRow
+
+	if err := conn(ctx, r.DB).
+		Table("payments").
+		Select(`
+			date_trunc('month', payments.paid_at) AS month,
+			SUM(payments.amount) AS revenue,
+			COUNT(DISTINCT subscriptions.user_id) AS paying_users
+		`).
+		Joins("JOIN subscriptions ON subscriptions.id = payments.subscription_id").
+		Where("payments.deleted_at IS NULL").
+		Where("payments.payment_status = ?", models.PaymentSucces).
+		Where("payments.currency = ?", f.Currency).
+		Where("payments.paid_at >= ? AND payments.paid_at < ?", f.From, f.To.AddDate(0, 1, 0)).
+		Group("month").
+		Order("month ASC").
+		Scan(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
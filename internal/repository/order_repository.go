@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"effective-project/internal/models"
 	"log/slog"
 
@@ -8,11 +9,11 @@ import (
 )
 
 type OrderRepository interface {
-	Create(order *models.Order) error
+	Create(ctx context.Context, order *models.Order) error
 
-	GetByID(id string) (*models.Order, error)
+	GetByID(ctx context.Context, id string) (*models.Order, error)
 
-	Update(order *models.Order) error
+	Update(ctx context.Context, order *models.Order) error
 }
 
 type gormOrderRepository struct {
@@ -27,7 +28,7 @@ func NewOrderRepository(db *gorm.DB, logger *slog.Logger) OrderRepository {
 	}
 }
 
-func (r *gormOrderRepository) Create(order *models.Order) error {
+func (r *gormOrderRepository) Create(ctx context.Context, order *models.Order) error {
 	op := "repository.order.create"
 
 	r.logger.Debug("db call",
@@ -35,7 +36,7 @@ func (r *gormOrderRepository) Create(order *models.Order) error {
 		slog.Any("order", order),
 	)
 
-	if err := r.db.Create(order).Error; err != nil {
+	if err := conn(ctx, r.db).Create(order).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -43,7 +44,7 @@ func (r *gormOrderRepository) Create(order *models.Order) error {
 	return nil
 }
 
-func (r *gormOrderRepository) GetByID(id string) (*models.Order, error) {
+func (r *gormOrderRepository) GetByID(ctx context.Context, id string) (*models.Order, error) {
 	op := "repository.order.get_by_id"
 
 	r.logger.Debug("db call",
@@ -52,7 +53,7 @@ func (r *gormOrderRepository) GetByID(id string) (*models.Order, error) {
 	)
 
 	var order models.Order
-	if err := r.db.First(&order, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&order, "id = ?", id).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return nil, err
 	}
@@ -60,7 +61,7 @@ func (r *gormOrderRepository) GetByID(id string) (*models.Order, error) {
 	return &order, nil
 }
 
-func (r *gormOrderRepository) Update(order *models.Order) error {
+func (r *gormOrderRepository) Update(ctx context.Context, order *models.Order) error {
 	op := "repository.order.update"
 
 	r.logger.Debug("db call",
@@ -68,7 +69,7 @@ func (r *gormOrderRepository) Update(order *models.Order) error {
 		slog.Any("order", order),
 	)
 
-	if err := r.db.Save(order).Error; err != nil {
+	if err := conn(ctx, r.db).Save(order).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
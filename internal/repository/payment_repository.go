@@ -2,30 +2,34 @@ package repository
 
 import (
 	"context"
-	"time"
 
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"log/slog"
 
-	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PaymentRepository interface {
-	Create(service *models.Payment) error
+	Create(ctx context.Context, service *models.Payment) error
 
-	List(
-		ctx context.Context,
-		limit int,
-		lastCreatedAt *time.Time,
-		lastID *uuid.UUID,
-	) ([]models.Payment, error)
+	List(ctx context.Context, q *query.List) ([]models.Payment, error)
 
-	GetByID(id string) (*models.Payment, error)
+	GetByID(ctx context.Context, id string) (*models.Payment, error)
 
-	Update(service *models.Payment) error
+	// GetForUpdate читает платёж с блокировкой строки до конца транзакции
+	GetForUpdate(ctx context.Context, id string) (*models.Payment, error)
 
-	Delete(id string) error
+	Update(ctx context.Context, service *models.Payment) error
+
+	Delete(ctx context.Context, id string) error
+
+	// CreateWithLedger сохраняет платёж и его проводки в одной транзакции
+	CreateWithLedger(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error
+
+	// UpdateWithLedger обновляет платёж и добавляет проводки в одной транзакции
+	UpdateWithLedger(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error
 }
 
 type gormPaymentRepository struct {
@@ -40,7 +44,7 @@ func NewPaymentRepository(db *gorm.DB, logger *slog.Logger) PaymentRepository {
 	}
 }
 
-func (r *gormPaymentRepository) Create(payment *models.Payment) error {
+func (r *gormPaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
 	op := "repository.payment.create"
 
 	r.logger.Debug("db call",
@@ -48,7 +52,7 @@ func (r *gormPaymentRepository) Create(payment *models.Payment) error {
 		slog.Any("payment", payment),
 	)
 
-	if err := r.DB.Create(payment).Error; err != nil {
+	if err := conn(ctx, r.DB).Create(payment).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -58,39 +62,22 @@ func (r *gormPaymentRepository) Create(payment *models.Payment) error {
 
 func (r *gormPaymentRepository) List(
 	ctx context.Context,
-	limit int,
-	lastCreatedAt *time.Time,
-	lastID *uuid.UUID,
+	q *query.List,
 ) ([]models.Payment, error) {
 
 	op := "repository.payment.list"
 
 	r.logger.Debug("db call",
 		slog.String("op", op),
-		slog.Int("limit", limit),
-		slog.Any("lastCreatedAt", lastCreatedAt),
-		slog.Any("lastID", lastID),
+		slog.Any("query", q),
 	)
 
-	payments := make([]models.Payment, 0, limit)
-
-	q := r.DB.
-		WithContext(ctx).
-		Model(&models.Payment{}).
-		Order("created_at ASC").
-		Order("id ASC").
-		Limit(limit)
-
-	if lastCreatedAt != nil && lastID != nil {
-		q = q.Where(
-			"(created_at > ?) OR (created_at = ? AND id > ?)",
-			*lastCreatedAt,
-			*lastCreatedAt,
-			*lastID,
-		)
-	}
+	payments := make([]models.Payment, 0, q.Limit)
+
+	db := q.Apply(conn(ctx, r.DB).
+		Model(&models.Payment{}))
 
-	if err := q.Find(&payments).Error; err != nil {
+	if err := db.Find(&payments).Error; err != nil {
 		r.logger.Error("db error",
 			slog.String("op", op),
 			slog.Any("error", err),
@@ -101,7 +88,7 @@ func (r *gormPaymentRepository) List(
 	return payments, nil
 }
 
-func (r *gormPaymentRepository) GetByID(id string) (*models.Payment, error) {
+func (r *gormPaymentRepository) GetByID(ctx context.Context, id string) (*models.Payment, error) {
 	op := "repository.payment.get_by_id"
 
 	r.logger.Debug("db call",
@@ -110,7 +97,26 @@ func (r *gormPaymentRepository) GetByID(id string) (*models.Payment, error) {
 	)
 
 	var payment models.Payment
-	if err := r.DB.First(&payment, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).First(&payment, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *gormPaymentRepository) GetForUpdate(ctx context.Context, id string) (*models.Payment, error) {
+	op := "repository.payment.get_for_update"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id),
+	)
+
+	var payment models.Payment
+	if err := conn(ctx, r.DB).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&payment, "id = ?", id).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return nil, err
 	}
@@ -118,7 +124,7 @@ func (r *gormPaymentRepository) GetByID(id string) (*models.Payment, error) {
 	return &payment, nil
 }
 
-func (r *gormPaymentRepository) Update(payment *models.Payment) error {
+func (r *gormPaymentRepository) Update(ctx context.Context, payment *models.Payment) error {
 	op := "repository.payment.update"
 
 	r.logger.Debug("db call",
@@ -126,7 +132,7 @@ func (r *gormPaymentRepository) Update(payment *models.Payment) error {
 		slog.Any("payment", payment),
 	)
 
-	if err := r.DB.Save(payment).Error; err != nil {
+	if err := conn(ctx, r.DB).Save(payment).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -134,7 +140,7 @@ func (r *gormPaymentRepository) Update(payment *models.Payment) error {
 	return nil
 }
 
-func (r *gormPaymentRepository) Delete(id string) error {
+func (r *gormPaymentRepository) Delete(ctx context.Context, id string) error {
 	op := "repository.payment.delete"
 
 	r.logger.Debug("db call",
@@ -142,7 +148,67 @@ func (r *gormPaymentRepository) Delete(id string) error {
 		slog.String("id", id),
 	)
 
-	if err := r.DB.Delete(&models.Payment{}, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).Delete(&models.Payment{}, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormPaymentRepository) CreateWithLedger(
+	ctx context.Context,
+	payment *models.Payment,
+	entries []models.LedgerEntry,
+) error {
+	op := "repository.payment.create_with_ledger"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("payment", payment),
+		slog.Int("entries", len(entries)),
+	)
+
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Create(&entries).Error
+	})
+	if err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormPaymentRepository) UpdateWithLedger(
+	ctx context.Context,
+	payment *models.Payment,
+	entries []models.LedgerEntry,
+) error {
+	op := "repository.payment.update_with_ledger"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("payment", payment),
+		slog.Int("entries", len(entries)),
+	)
+
+	err := conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(payment).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Create(&entries).Error
+	})
+	if err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
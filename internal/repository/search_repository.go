@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+type SearchRepository interface {
+	Search(ctx context.Context, q dto.SearchQuery, tsQuery string) ([]dto.SearchHit, error)
+}
+
+type gormSearchRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewSearchRepository(db *gorm.DB, logger *slog.Logger) SearchRepository {
+	return &gormSearchRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Оценка складывается из триграммного сходства (устойчиво к опечаткам)
+// и ранга полнотекстового совпадения. Округление нужно, чтобы курсор
+// сравнивался с теми же значениями, что попали в выдачу.
+// Подсветка — HTML, поэтому название экранируется до ts_headline: иначе
+// разметка из названия попала бы в ответ как есть. Парсер tsvector считает
+// сущности вроде &amp; отдельными токенами и не ищет по ним
+const searchQuery = `
+WITH RECURSIVE subtree AS (
+	SELECT id, 0 AS depth
+	FROM categories
+	WHERE id = @category_id AND deleted_at IS NULL
+	UNION ALL
+	SELECT c.id, subtree.depth + 1
+	FROM categories c
+	JOIN subtree ON c.parent_id = subtree.id
+	WHERE c.deleted_at IS NULL AND subtree.depth < @max_depth
+),
+hits AS (
+	SELECT
+		'category' AS type,
+		c.id,
+		c.name,
+		'' AS website,
+		NULL::uuid AS category_id,
+		c.created_at,
+		ROUND((
+			GREATEST(similarity(c.name, @q), word_similarity(@q, c.name))
+			+ ts_rank(to_tsvector('simple', c.name), to_tsquery('simple', @tsq))
+		)::numeric, 6)::float8 AS score,
+		ts_headline('simple', replace(replace(replace(replace(replace(c.name,
+			'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'), to_tsquery('simple', @tsq),
+			'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight
+	FROM categories c
+	WHERE c.deleted_at IS NULL
+		AND @type IN ('', 'category')
+		AND (NOT @has_category OR c.id IN (SELECT id FROM subtree))
+		AND (
+			c.name % @q
+			OR @q <% c.name
+			OR to_tsvector('simple', c.name) @@ to_tsquery('simple', @tsq)
+		)
+
+	UNION ALL
+
+	SELECT
+		'service' AS type,
+		s.id,
+		s.name,
+		s.website,
+		s.category_id,
+		s.created_at,
+		ROUND((
+			GREATEST(
+				similarity(s.name, @q),
+				word_similarity(@q, s.name),
+				word_similarity(@q, coalesce(s.website, '')) * 0.8
+			)
+			+ ts_rank(
+				to_tsvector('simple', s.name || ' ' || coalesce(s.website, '')),
+				to_tsquery('simple', @tsq)
+			)
+		)::numeric, 6)::float8 AS score,
+		ts_headline('simple', replace(replace(replace(replace(replace(s.name,
+			'&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'), to_tsquery('simple', @tsq),
+			'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS highlight
+	FROM services s
+	WHERE s.deleted_at IS NULL
+		AND @type IN ('', 'service')
+		AND (NOT @has_category OR s.category_id IN (SELECT id FROM subtree))
+		AND (
+			s.name % @q
+			OR @q <% s.name
+			OR @q <% coalesce(s.website, '')
+			OR to_tsvector('simple', s.name || ' ' || coalesce(s.website, '')) @@ to_tsquery('simple', @tsq)
+		)
+)
+SELECT * FROM hits
+WHERE NOT @has_cursor
+	OR score < @last_score
+	OR (score = @last_score AND created_at > @last_created_at)
+	OR (score = @last_score AND created_at = @last_created_at AND id > @last_id)
+ORDER BY score DESC, created_at ASC, id ASC
+LIMIT @limit`
+
+func (r *gormSearchRepository) Search(
+	ctx context.Context,
+	q dto.SearchQuery,
+	tsQuery string,
+) ([]dto.SearchHit, error) {
+	op := "repository.search.search"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("query", q.Query),
+		slog.String("type", q.Type),
+		slog.Int("limit", q.Limit),
+	)
+
+	args := map[string]any{
+		"q":               q.Query,
+		"tsq":             tsQuery,
+		"type":            q.Type,
+		"limit":           q.Limit,
+		"has_cursor":      q.LastScore != nil,
+		"last_score":      0.0,
+		"last_created_at": nil,
+		"last_id":         nil,
+		"has_category":    q.CategoryID != nil,
+		"category_id":     q.CategoryID,
+		"max_depth":       maxCategoryDepth,
+	}
+	if q.LastScore != nil {
+		args["last_score"] = *q.LastScore
+		args["last_created_at"] = *q.LastCreatedAt
+		args["last_id"] = *q.LastID
+	}
+
+	var hits []dto.SearchHit
+
+	if err := conn(ctx, r.DB).Raw(searchQuery, args).Scan(&hits).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return hits, nil
+}
@@ -2,30 +2,24 @@ package repository
 
 import (
 	"context"
-	"time"
 
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"log/slog"
 
-	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type ServiceRepository interface {
-	Create(service *models.Service) error
+	Create(ctx context.Context, service *models.Service) error
 
-	List(
-		ctx context.Context,
-		limit int,
-		lastCreatedAt *time.Time,
-		lastID *uuid.UUID,
-	) ([]models.Service, error)
+	List(ctx context.Context, q *query.List) ([]models.Service, error)
 
-	GetByID(id string) (*models.Service, error)
+	GetByID(ctx context.Context, id string) (*models.Service, error)
 
-	Update(service *models.Service) error
+	Update(ctx context.Context, service *models.Service) error
 
-	Delete(id string) error
+	Delete(ctx context.Context, id string) error
 }
 
 type gormServiceRepository struct {
@@ -40,7 +34,7 @@ func NewServiceRepository(db *gorm.DB, logger *slog.Logger) ServiceRepository {
 	}
 }
 
-func (r *gormServiceRepository) Create(service *models.Service) error {
+func (r *gormServiceRepository) Create(ctx context.Context, service *models.Service) error {
 	op := "repository.service.create"
 
 	r.logger.Debug("db call",
@@ -48,7 +42,7 @@ func (r *gormServiceRepository) Create(service *models.Service) error {
 		slog.Any("service", service),
 	)
 
-	if err := r.DB.Create(service).Error; err != nil {
+	if err := conn(ctx, r.DB).Create(service).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -58,39 +52,22 @@ func (r *gormServiceRepository) Create(service *models.Service) error {
 
 func (r *gormServiceRepository) List(
 	ctx context.Context,
-	limit int,
-	lastCreatedAt *time.Time,
-	lastID *uuid.UUID,
+	q *query.List,
 ) ([]models.Service, error) {
 
 	op := "repository.service.list"
 
 	r.logger.Debug("db call",
 		slog.String("op", op),
-		slog.Int("limit", limit),
-		slog.Any("lastCreatedAt", lastCreatedAt),
-		slog.Any("lastID", lastID),
+		slog.Any("query", q),
 	)
 
-	services := make([]models.Service, 0, limit)
-
-	q := r.DB.
-		WithContext(ctx).
-		Model(&models.Service{}).
-		Order("created_at ASC").
-		Order("id ASC").
-		Limit(limit)
-
-	if lastCreatedAt != nil && lastID != nil {
-		q = q.Where(
-			"(created_at > ?) OR (created_at = ? AND id > ?)",
-			*lastCreatedAt,
-			*lastCreatedAt,
-			*lastID,
-		)
-	}
+	services := make([]models.Service, 0, q.Limit)
+
+	db := q.Apply(conn(ctx, r.DB).
+		Model(&models.Service{}))
 
-	if err := q.Find(&services).Error; err != nil {
+	if err := db.Find(&services).Error; err != nil {
 		r.logger.Error("db error",
 			slog.String("op", op),
 			slog.Any("error", err),
@@ -101,7 +78,7 @@ func (r *gormServiceRepository) List(
 	return services, nil
 }
 
-func (r *gormServiceRepository) GetByID(id string) (*models.Service, error) {
+func (r *gormServiceRepository) GetByID(ctx context.Context, id string) (*models.Service, error) {
 	op := "repository.service.get_by_id"
 
 	r.logger.Debug("db call",
@@ -110,7 +87,7 @@ func (r *gormServiceRepository) GetByID(id string) (*models.Service, error) {
 	)
 
 	var service models.Service
-	if err := r.DB.First(&service, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).First(&service, "id = ?", id).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return nil, err
 	}
@@ -118,7 +95,7 @@ func (r *gormServiceRepository) GetByID(id string) (*models.Service, error) {
 	return &service, nil
 }
 
-func (r *gormServiceRepository) Update(service *models.Service) error {
+func (r *gormServiceRepository) Update(ctx context.Context, service *models.Service) error {
 	op := "repository.service.update"
 
 	r.logger.Debug("db call",
@@ -126,7 +103,7 @@ func (r *gormServiceRepository) Update(service *models.Service) error {
 		slog.Any("service", service),
 	)
 
-	if err := r.DB.Save(service).Error; err != nil {
+	if err := conn(ctx, r.DB).Save(service).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -134,7 +111,7 @@ func (r *gormServiceRepository) Update(service *models.Service) error {
 	return nil
 }
 
-func (r *gormServiceRepository) Delete(id string) error {
+func (r *gormServiceRepository) Delete(ctx context.Context, id string) error {
 	op := "repository.service.delete"
 
 	r.logger.Debug("db call",
@@ -142,7 +119,7 @@ func (r *gormServiceRepository) Delete(id string) error {
 		slog.String("id", id),
 	)
 
-	if err := r.DB.Delete(&models.Service{}, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).Delete(&models.Service{}, "id = ?", id).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
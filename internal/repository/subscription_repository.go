@@ -4,6 +4,7 @@ import (
 	"context"
 	"effective-project/internal/dto"
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"log/slog"
 	"time"
 
@@ -12,27 +13,32 @@ import (
 )
 
 type SubscriptionRepository interface {
-	Create(subscription *models.Subscription) error
+	Create(ctx context.Context, subscription *models.Subscription) error
 
-	List(
-		ctx context.Context,
-		limit int,
-		lastCreatedAt *time.Time,
-		lastID *uuid.UUID,
-	) ([]dto.SubscriptionResponse, error)
+	List(ctx context.Context, q *query.List) ([]dto.SubscriptionResponse, error)
+
+	GetByID(ctx context.Context, id string) (*dto.SubscriptionResponse, error)
 
-	GetByID(id string) (*dto.SubscriptionResponse, error)
+	Update(ctx context.Context, subscription *models.Subscription) error
 
-	Update(subscription *models.Subscription) error
+	// UpdateStatus пишет только статус и, если она передана, дату окончания:
+	// Save перезаписал бы поля, изменённые параллельно
+	UpdateStatus(ctx context.Context, id uuid.UUID, status models.SubscriptionStatus, endDate *time.Time) error
 
-	Delete(id string) error
+	Delete(ctx context.Context, id string) error
 
 	FindForTotal(
 		ctx context.Context,
 		f dto.TotalFilter,
 	) ([]dto.SubscriptionRow, error)
 
-	GetModelByID(id string) (*models.Subscription, error)
+	GetModelByID(ctx context.Context, id string) (*models.Subscription, error)
+
+	ListActiveByUser(
+		ctx context.Context,
+		userID uuid.UUID,
+		at time.Time,
+	) ([]dto.UpcomingSubscriptionRow, error)
 }
 
 type gormSubscriptionRepository struct {
@@ -47,7 +53,7 @@ func NewSubscriptionRepository(db *gorm.DB, logger *slog.Logger) SubscriptionRep
 	}
 }
 
-func (r *gormSubscriptionRepository) Create(subscription *models.Subscription) error {
+func (r *gormSubscriptionRepository) Create(ctx context.Context, subscription *models.Subscription) error {
 	op := "repository.subscription.create"
 
 	r.logger.Debug("db call",
@@ -55,7 +61,7 @@ func (r *gormSubscriptionRepository) Create(subscription *models.Subscription) e
 		slog.Any("subscription", subscription),
 	)
 
-	if err := r.DB.Create(subscription).Error; err != nil {
+	if err := conn(ctx, r.DB).Create(subscription).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -65,44 +71,32 @@ func (r *gormSubscriptionRepository) Create(subscription *models.Subscription) e
 
 func (r *gormSubscriptionRepository) List(
 	ctx context.Context,
-	limit int,
-	lastCreatedAt *time.Time,
-	lastID *uuid.UUID,
+	q *query.List,
 ) ([]dto.SubscriptionResponse, error) {
 
 	op := "repository.subscription.get_all"
 
 	r.logger.Debug("db call",
 		slog.String("op", op),
+		slog.Any("query", q),
 	)
 
-	result := make([]dto.SubscriptionResponse, 0, limit)
+	result := make([]dto.SubscriptionResponse, 0, q.Limit)
 
-	q := r.DB.WithContext(ctx).
+	db := q.Apply(conn(ctx, r.DB).
 		Table("subscriptions").
 		Select(`
 			subscriptions.id,
+			subscriptions.created_at,
 			subscriptions.start_date,
 			subscriptions.end_date,
 			subscriptions.price,
 			subscriptions.service_id,
 			services.name AS service_name
 		`).
-		Joins("JOIN services ON services.id = subscriptions.service_id").
-		Order("subscriptions.created_at ASC").
-		Order("subscriptions.id ASC").
-		Limit(limit)
-
-	if lastCreatedAt != nil && lastID != nil {
-		q = q.Where(
-			"(subscriptions.created_at > ?) OR (subscriptions.created_at = ? AND subscriptions.id > ?)",
-			*lastCreatedAt,
-			*lastCreatedAt,
-			*lastID,
-		)
-	}
+		Joins("JOIN services ON services.id = subscriptions.service_id"))
 
-	if err := q.Scan(&result).Error; err != nil {
+	if err := db.Scan(&result).Error; err != nil {
 		r.logger.Error("db error",
 			slog.String("op", op),
 			slog.Any("error", err),
@@ -113,7 +107,7 @@ func (r *gormSubscriptionRepository) List(
 	return result, nil
 }
 
-func (r *gormSubscriptionRepository) GetByID(id string) (*dto.SubscriptionResponse, error) {
+func (r *gormSubscriptionRepository) GetByID(ctx context.Context, id string) (*dto.SubscriptionResponse, error) {
 	op := "repository.subscription.get_by_id"
 
 	r.logger.Debug("db call",
@@ -123,7 +117,7 @@ func (r *gormSubscriptionRepository) GetByID(id string) (*dto.SubscriptionRespon
 
 	var result dto.SubscriptionResponse
 
-	if err := r.DB.
+	if err := conn(ctx, r.DB).
 		Table("subscriptions").
 		Select(`
 			subscriptions.id,
@@ -147,7 +141,7 @@ func (r *gormSubscriptionRepository) GetByID(id string) (*dto.SubscriptionRespon
 	return &result, nil
 }
 
-func (r *gormSubscriptionRepository) Update(subscription *models.Subscription) error {
+func (r *gormSubscriptionRepository) Update(ctx context.Context, subscription *models.Subscription) error {
 	op := "repository.subscription.update"
 
 	r.logger.Debug("db call",
@@ -155,7 +149,7 @@ func (r *gormSubscriptionRepository) Update(subscription *models.Subscription) e
 		slog.Any("subscription", subscription),
 	)
 
-	if err := r.DB.Save(subscription).Error; err != nil {
+	if err := conn(ctx, r.DB).Save(subscription).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -163,7 +157,37 @@ func (r *gormSubscriptionRepository) Update(subscription *models.Subscription) e
 	return nil
 }
 
-func (r *gormSubscriptionRepository) Delete(id string) error {
+func (r *gormSubscriptionRepository) UpdateStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	status models.SubscriptionStatus,
+	endDate *time.Time,
+) error {
+	op := "repository.subscription.update_status"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("id", id),
+		slog.Any("status", status),
+	)
+
+	updates := map[string]any{"status": status}
+	if endDate != nil {
+		updates["end_date"] = *endDate
+	}
+
+	if err := conn(ctx, r.DB).
+		Model(&models.Subscription{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormSubscriptionRepository) Delete(ctx context.Context, id string) error {
 	op := "repository.subscription.delete"
 
 	r.logger.Debug("db call",
@@ -171,7 +195,7 @@ func (r *gormSubscriptionRepository) Delete(id string) error {
 		slog.String("id", id),
 	)
 
-	if err := r.DB.Delete(&models.Subscription{}, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).Delete(&models.Subscription{}, "id = ?", id).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -184,19 +208,22 @@ func (r *gormSubscriptionRepository) FindForTotal(
 	f dto.TotalFilter,
 ) ([]dto.SubscriptionRow, error) {
 
-	q := r.DB.WithContext(ctx).
+	q := conn(ctx, r.DB).
 		Table("subscriptions").
 		Select(`
 			subscriptions.start_date,
 			subscriptions.end_date,
 			subscriptions.price,
+			subscriptions.billing_interval AS interval,
+			subscriptions.paused_until,
 			services.name AS service_name
 		`).
 		Joins("JOIN services ON services.id = subscriptions.service_id").
-		Where("subscriptions.start_date <= ?", f.To).
+		Where("subscriptions.deleted_at IS NULL").
+		Where("subscriptions.start_date < ?", f.To.AddDate(0, 1, 0)).
 		Where("(subscriptions.end_date IS NULL OR subscriptions.end_date >= ?)", f.From)
 
-	if f.UserID.String() != "" {
+	if f.UserID != uuid.Nil {
 		q = q.Where("subscriptions.user_id = ?", f.UserID)
 	}
 
@@ -204,12 +231,20 @@ func (r *gormSubscriptionRepository) FindForTotal(
 		q = q.Where("services.name = ?", f.ServiceName)
 	}
 
+	if f.ServiceID != uuid.Nil {
+		q = q.Where("subscriptions.service_id = ?", f.ServiceID)
+	}
+
+	if f.CategoryID != uuid.Nil {
+		q = q.Where("services.category_id = ?", f.CategoryID)
+	}
+
 	var rows []dto.SubscriptionRow
 	err := q.Scan(&rows).Error
 	return rows, err
 }
 
-func (r *gormSubscriptionRepository) GetModelByID(id string) (*models.Subscription, error) {
+func (r *gormSubscriptionRepository) GetModelByID(ctx context.Context, id string) (*models.Subscription, error) {
 	op := "repository.subscription.get_model_by_id"
 	var subscription models.Subscription
 
@@ -218,10 +253,49 @@ func (r *gormSubscriptionRepository) GetModelByID(id string) (*models.Subscripti
 		slog.Any("id", id),
 	)
 
-	if err := r.DB.First(&subscription, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).First(&subscription, "id = ?", id).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return nil, err
 	}
 
 	return &subscription, nil
 }
+
+func (r *gormSubscriptionRepository) ListActiveByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+	at time.Time,
+) ([]dto.UpcomingSubscriptionRow, error) {
+	op := "repository.subscription.list_active_by_user"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("user_id", userID),
+	)
+
+	var rows []dto.UpcomingSubscriptionRow
+
+	if err := conn(ctx, r.DB).
+		Table("subscriptions").
+		Select(`
+			subscriptions.id,
+			subscriptions.service_id,
+			services.name AS service_name,
+			subscriptions.start_date,
+			subscriptions.end_date,
+			subscriptions.paused_until,
+			subscriptions.price,
+			subscriptions.billing_interval AS interval
+		`).
+		Joins("JOIN services ON services.id = subscriptions.service_id").
+		Where("subscriptions.deleted_at IS NULL").
+		Where("subscriptions.user_id = ?", userID).
+		Where("(subscriptions.end_date IS NULL OR subscriptions.end_date >= ?)", at).
+		Order("subscriptions.start_date ASC").
+		Scan(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
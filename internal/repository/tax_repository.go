@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TaxRepository interface {
+	CreateRule(ctx context.Context, rule *models.TaxRule) error
+
+	ListRules(ctx context.Context, country string) ([]models.TaxRule, error)
+
+	GetRuleByID(ctx context.Context, id string) (*models.TaxRule, error)
+
+	UpdateRule(ctx context.Context, rule *models.TaxRule) error
+
+	DeleteRule(ctx context.Context, id string) error
+
+	// EffectiveRules возвращает ставки страны и региона, действующие на дату at
+	EffectiveRules(ctx context.Context, country, region string, at time.Time) ([]models.TaxRule, error)
+
+	CreateLines(ctx context.Context, lines []models.TaxLine) error
+
+	Report(ctx context.Context, f dto.TaxReportFilter) ([]dto.TaxReportRow, error)
+}
+
+type gormTaxRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewTaxRepository(db *gorm.DB, logger *slog.Logger) TaxRepository {
+	return &gormTaxRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+func (r *gormTaxRepository) CreateRule(ctx context.Context, rule *models.TaxRule) error {
+	op := "repository.tax.create_rule"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("rule", rule),
+	)
+
+	if err := conn(ctx, r.DB).Create(rule).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormTaxRepository) ListRules(ctx context.Context, country string) ([]models.TaxRule, error) {
+	op := "repository.tax.list_rules"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("country", country),
+	)
+
+	q := conn(ctx, r.DB).
+		Model(&models.TaxRule{}).
+		Order("country ASC").
+		Order("region ASC").
+		Order("effective_from ASC")
+
+	if country != "" {
+		q = q.Where("country = ?", country)
+	}
+
+	var rules []models.TaxRule
+	if err := q.Find(&rules).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *gormTaxRepository) GetRuleByID(ctx context.Context, id string) (*models.TaxRule, error) {
+	op := "repository.tax.get_rule_by_id"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id),
+	)
+
+	var rule models.TaxRule
+	if err := conn(ctx, r.DB).First(&rule, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (r *gormTaxRepository) UpdateRule(ctx context.Context, rule *models.TaxRule) error {
+	op := "repository.tax.update_rule"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("rule", rule),
+	)
+
+	if err := conn(ctx, r.DB).Save(rule).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormTaxRepository) DeleteRule(ctx context.Context, id string) error {
+	op := "repository.tax.delete_rule"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("id", id),
+	)
+
+	if err := conn(ctx, r.DB).Delete(&models.TaxRule{}, "id = ?", id).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormTaxRepository) EffectiveRules(
+	ctx context.Context,
+	country, region string,
+	at time.Time,
+) ([]models.TaxRule, error) {
+	op := "repository.tax.effective_rules"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("country", country),
+		slog.String("region", region),
+		slog.Time("at", at),
+	)
+
+	var rules []models.TaxRule
+	if err := conn(ctx, r.DB).
+		Where("country = ?", country).
+		Where("region = '' OR LOWER(region) = LOWER(?)", region).
+		Where("effective_from <= ?", at).
+		Where("effective_to IS NULL OR effective_to > ?", at).
+		Order("region ASC").
+		Order("effective_from ASC").
+		Find(&rules).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *gormTaxRepository) CreateLines(ctx context.Context, lines []models.TaxLine) error {
+	op := "repository.tax.create_lines"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Int("count", len(lines)),
+	)
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	if err := conn(ctx, r.DB).Create(&lines).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormTaxRepository) Report(ctx context.Context, f dto.TaxReportFilter) ([]dto.TaxReportRow, error) {
+	op := "repository.tax.report"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("filter", f),
+	)
+
+	var rows []dto.TaxReportRow
+
+	q := conn(ctx, r.DB).
+		Model(&models.TaxLine{}).
+		Select(`
+			date_trunc(?, created_at) AS period,
+			country,
+			region,
+			currency,
+			SUM(taxable) AS taxable,
+			SUM(amount) AS tax,
+			COUNT(*) AS lines
+		`, f.Period).
+		Where("created_at >= ? AND created_at < ?", f.From, f.To)
+
+	if f.Country != "" {
+		q = q.Where("country = ?", f.Country)
+	}
+
+	if err := q.
+		Group("period, country, region, currency").
+		Order("period ASC, country ASC, region ASC").
+		Scan(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"effective-project/internal/models"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TranslationRepository interface {
+	// CategoryTranslations возвращает переводы категорий на язык locale
+	CategoryTranslations(ctx context.Context, locale string, ids []uuid.UUID) ([]models.CategoryTranslation, error)
+
+	// ServiceTranslations возвращает переводы сервисов на язык locale
+	ServiceTranslations(ctx context.Context, locale string, ids []uuid.UUID) ([]models.ServiceTranslation, error)
+
+	ListCategory(ctx context.Context, categoryID uuid.UUID) ([]models.CategoryTranslation, error)
+	ListService(ctx context.Context, serviceID uuid.UUID) ([]models.ServiceTranslation, error)
+
+	// UpsertCategory создаёт или заменяет перевод категории
+	UpsertCategory(ctx context.Context, t *models.CategoryTranslation) error
+
+	// UpsertService создаёт или заменяет перевод сервиса
+	UpsertService(ctx context.Context, t *models.ServiceTranslation) error
+
+	// DeleteCategory удаляет перевод; возвращает false, если перевода не было
+	DeleteCategory(ctx context.Context, categoryID uuid.UUID, locale string) (bool, error)
+
+	// DeleteService удаляет перевод; возвращает false, если перевода не было
+	DeleteService(ctx context.Context, serviceID uuid.UUID, locale string) (bool, error)
+}
+
+type gormTranslationRepository struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewTranslationRepository(db *gorm.DB, logger *slog.Logger) TranslationRepository {
+	return &gormTranslationRepository{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+func (r *gormTranslationRepository) CategoryTranslations(
+	ctx context.Context,
+	locale string,
+	ids []uuid.UUID,
+) ([]models.CategoryTranslation, error) {
+	op := "repository.translation.category_translations"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("locale", locale),
+		slog.Int("ids", len(ids)),
+	)
+
+	var rows []models.CategoryTranslation
+	if len(ids) == 0 {
+		return rows, nil
+	}
+
+	if err := conn(ctx, r.DB).
+		Where("locale = ? AND category_id IN ?", locale, ids).
+		Find(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *gormTranslationRepository) ServiceTranslations(
+	ctx context.Context,
+	locale string,
+	ids []uuid.UUID,
+) ([]models.ServiceTranslation, error) {
+	op := "repository.translation.service_translations"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("locale", locale),
+		slog.Int("ids", len(ids)),
+	)
+
+	var rows []models.ServiceTranslation
+	if len(ids) == 0 {
+		return rows, nil
+	}
+
+	if err := conn(ctx, r.DB).
+		Where("locale = ? AND service_id IN ?", locale, ids).
+		Find(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *gormTranslationRepository) ListCategory(ctx context.Context, categoryID uuid.UUID) ([]models.CategoryTranslation, error) {
+	op := "repository.translation.list_category"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("category_id", categoryID.String()),
+	)
+
+	var rows []models.CategoryTranslation
+	if err := conn(ctx, r.DB).
+		Where("category_id = ?", categoryID).
+		Order("locale ASC").
+		Find(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *gormTranslationRepository) ListService(ctx context.Context, serviceID uuid.UUID) ([]models.ServiceTranslation, error) {
+	op := "repository.translation.list_service"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("service_id", serviceID.String()),
+	)
+
+	var rows []models.ServiceTranslation
+	if err := conn(ctx, r.DB).
+		Where("service_id = ?", serviceID).
+		Order("locale ASC").
+		Find(&rows).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *gormTranslationRepository) UpsertCategory(ctx context.Context, t *models.CategoryTranslation) error {
+	op := "repository.translation.upsert_category"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("translation", t),
+	)
+
+	if err := conn(ctx, r.DB).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "category_id"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+		}).
+		Create(t).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormTranslationRepository) UpsertService(ctx context.Context, t *models.ServiceTranslation) error {
+	op := "repository.translation.upsert_service"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Any("translation", t),
+	)
+
+	if err := conn(ctx, r.DB).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "service_id"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+		}).
+		Create(t).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *gormTranslationRepository) DeleteCategory(ctx context.Context, categoryID uuid.UUID, locale string) (bool, error) {
+	op := "repository.translation.delete_category"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("category_id", categoryID.String()),
+		slog.String("locale", locale),
+	)
+
+	res := conn(ctx, r.DB).
+		Where("category_id = ? AND locale = ?", categoryID, locale).
+		Delete(&models.CategoryTranslation{})
+	if res.Error != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", res.Error))
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *gormTranslationRepository) DeleteService(ctx context.Context, serviceID uuid.UUID, locale string) (bool, error) {
+	op := "repository.translation.delete_service"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.String("service_id", serviceID.String()),
+		slog.String("locale", locale),
+	)
+
+	res := conn(ctx, r.DB).
+		Where("service_id = ? AND locale = ?", serviceID, locale).
+		Delete(&models.ServiceTranslation{})
+	if res.Error != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", res.Error))
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// TxManager объединяет вызовы нескольких репозиториев в одну транзакцию.
+// Транзакция передаётся через контекст, и репозитории берут её сами
+type TxManager interface {
+	// WithinTx выполняет fn в транзакции: ошибка или паника fn откатывают всё,
+	// что записано через ctx. Вложенный вызов открывает точку сохранения,
+	// и его ошибка откатывает только вложенную часть
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type gormTxManager struct {
+	DB     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewTxManager(db *gorm.DB, logger *slog.Logger) TxManager {
+	return &gormTxManager{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+func (m *gormTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	op := "repository.tx.within"
+
+	_, nested := ctx.Value(txKey{}).(*gorm.DB)
+	m.logger.Debug("db call", slog.String("op", op), slog.Bool("nested", nested))
+
+	return conn(ctx, m.DB).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn возвращает транзакцию из ctx, а вне транзакции — db.
+// Запросы репозиториев строятся только от conn, иначе они пройдут мимо транзакции
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
@@ -3,30 +3,28 @@ package repository
 import (
 	"context"
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"log/slog"
 	"time"
 
-	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	Create(user *models.User) error
+	Create(ctx context.Context, user *models.User) error
 
-	List(
-		ctx context.Context,
-		limit int,
-		lastCreatedAt *time.Time,
-		lastID *uuid.UUID,
-	) ([]models.User, error)
+	List(ctx context.Context, q *query.List) ([]models.User, error)
 
-	GetByID(id string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
 
-	GetByEmail(email string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
 
-	Update(user *models.User) error
+	Update(ctx context.Context, user *models.User) error
 
-	Delete(id string) error
+	Delete(ctx context.Context, id string) error
+
+	// Hot возвращает до limit пользователей с наибольшим числом заказов начиная с since
+	Hot(ctx context.Context, since time.Time, limit int) ([]models.User, error)
 }
 
 type gormUserRepository struct {
@@ -41,7 +39,7 @@ func NewUserRepository(db *gorm.DB, logger *slog.Logger) UserRepository {
 	}
 }
 
-func (r *gormUserRepository) Create(user *models.User) error {
+func (r *gormUserRepository) Create(ctx context.Context, user *models.User) error {
 	op := "repository.user.create"
 
 	r.logger.Debug("db call",
@@ -49,7 +47,7 @@ func (r *gormUserRepository) Create(user *models.User) error {
 		slog.Any("user", user),
 	)
 
-	if err := r.DB.Create(user).Error; err != nil {
+	if err := conn(ctx, r.DB).Create(user).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -59,35 +57,21 @@ func (r *gormUserRepository) Create(user *models.User) error {
 
 func (r *gormUserRepository) List(
 	ctx context.Context,
-	limit int,
-	lastCreatedAt *time.Time,
-	lastID *uuid.UUID,
+	q *query.List,
 ) ([]models.User, error) {
 	op := "repository.user.get_all"
 
 	r.logger.Debug("db call",
 		slog.String("op", op),
+		slog.Any("query", q),
 	)
 
-	user := make([]models.User, 0, limit)
+	user := make([]models.User, 0, q.Limit)
 
-	q := r.DB.
-		WithContext(ctx).
-		Model(&models.User{}).
-		Order("created_at ASC").
-		Order("id ASC").
-		Limit(limit)
-
-	if lastCreatedAt != nil && lastID != nil {
-		q = q.Where(
-			"(created_at > ?) OR (created_at = ? AND id > ?)",
-			*lastCreatedAt,
-			*lastCreatedAt,
-			*lastID,
-		)
-	}
+	db := q.Apply(conn(ctx, r.DB).
+		Model(&models.User{}))
 
-	if err := q.Find(&user).Error; err != nil {
+	if err := db.Find(&user).Error; err != nil {
 		r.logger.Error("db error",
 			slog.String("op", op),
 			slog.Any("error", err),
@@ -98,7 +82,7 @@ func (r *gormUserRepository) List(
 	return user, nil
 }
 
-func (r *gormUserRepository) GetByID(id string) (*models.User, error) {
+func (r *gormUserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
 	op := "repository.user.get_by_id"
 
 	r.logger.Debug("db call",
@@ -107,14 +91,14 @@ func (r *gormUserRepository) GetByID(id string) (*models.User, error) {
 	)
 
 	var user models.User
-	if err := r.DB.First(&user, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).First(&user, "id = ?", id).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *gormUserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *gormUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	op := "repository.user.get_by_email"
 
 	r.logger.Debug("db call",
@@ -123,14 +107,14 @@ func (r *gormUserRepository) GetByEmail(email string) (*models.User, error) {
 	)
 
 	var user models.User
-	if err := r.DB.First(&user, "email = ?", email).Error; err != nil {
+	if err := conn(ctx, r.DB).First(&user, "email = ?", email).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *gormUserRepository) Update(user *models.User) error {
+func (r *gormUserRepository) Update(ctx context.Context, user *models.User) error {
 	op := "repository.user.update"
 
 	r.logger.Debug("db call",
@@ -138,7 +122,7 @@ func (r *gormUserRepository) Update(user *models.User) error {
 		slog.Any("user", user),
 	)
 
-	if err := r.DB.Save(user).Error; err != nil {
+	if err := conn(ctx, r.DB).Save(user).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
@@ -146,7 +130,7 @@ func (r *gormUserRepository) Update(user *models.User) error {
 	return nil
 }
 
-func (r *gormUserRepository) Delete(id string) error {
+func (r *gormUserRepository) Delete(ctx context.Context, id string) error {
 	op := "repository.user.delete"
 
 	r.logger.Debug("db call",
@@ -154,10 +138,36 @@ func (r *gormUserRepository) Delete(id string) error {
 		slog.String("id", id),
 	)
 
-	if err := r.DB.Delete(&models.User{}, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).Delete(&models.User{}, "id = ?", id).Error; err != nil {
 		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
 		return err
 	}
 
 	return nil
 }
+
+func (r *gormUserRepository) Hot(ctx context.Context, since time.Time, limit int) ([]models.User, error) {
+	op := "repository.user.hot"
+
+	r.logger.Debug("db call",
+		slog.String("op", op),
+		slog.Time("since", since),
+		slog.Int("limit", limit),
+	)
+
+	users := make([]models.User, 0, limit)
+	if err := conn(ctx, r.DB).
+		Model(&models.User{}).
+		Joins("JOIN orders ON orders.user_id = users.id AND orders.deleted_at IS NULL").
+		Where("orders.created_at >= ?", since).
+		Group("users.id").
+		Order("COUNT(orders.id) DESC").
+		Order("users.id ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		r.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return users, nil
+}
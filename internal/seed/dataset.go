@@ -0,0 +1,247 @@
+package seed
+
+import (
+	"fmt"
+	"strings"
+
+	"effective-project/internal/models"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// Пароли сидов известны заранее, чтобы под ними можно было войти
+const (
+	AdminEmail    = "admin@example.com"
+	AdminPassword = "admin12345"
+	UserPassword  = "password123"
+)
+
+type Options struct {
+	// Seed задаёт генератор: при одном Seed и объёмах набор данных одинаков
+	Seed int64
+
+	Users               int
+	ServicesPerCategory int
+	// SubscriptionsPerUser — верхняя граница, у каждого пользователя от 0 до неё
+	SubscriptionsPerUser int
+	// CartOrders — верхняя граница неоплаченных заказов в корзине пользователя
+	CartOrders int
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Seed:                 42,
+		Users:                50,
+		ServicesPerCategory:  5,
+		SubscriptionsPerUser: 3,
+		CartOrders:           2,
+	}
+}
+
+// Dataset — сгенерированные записи без идентификаторов: связи заданы
+// индексами в срезах, а строки в базе находятся по естественным ключам
+type Dataset struct {
+	Users         []UserSeed
+	Categories    []CategorySeed
+	Services      []ServiceSeed
+	Subscriptions []SubscriptionSeed
+	CartOrders    []CartOrderSeed
+}
+
+// UserSeed — пользователь; естественный ключ — email
+type UserSeed struct {
+	Email     string
+	FirstName string
+	LastName  string
+	Role      models.Role
+	Address   models.BillingAddress
+}
+
+// CategorySeed — категория; естественный ключ — название и родитель.
+// Parent равен -1 у корневых
+type CategorySeed struct {
+	Name   string
+	Parent int
+}
+
+// ServiceSeed — сервис; естественный ключ — название и категория
+type ServiceSeed struct {
+	Name        string
+	Description string
+	Category    int
+	Website     string
+	// Color — основной цвет сгенерированного логотипа
+	Color [3]uint8
+}
+
+// SubscriptionSeed — подписка; естественный ключ — пользователь и сервис.
+// Даты отсчитываются от дня запуска, оплаты строятся по периодам подписки
+type SubscriptionSeed struct {
+	User     int
+	Service  int
+	Price    int
+	Interval models.BillingInterval
+	// StartedDaysAgo — сколько дней назад оформлена подписка
+	StartedDaysAgo int
+	// EndsInDays — через сколько дней подписка заканчивается; 0 — бессрочная
+	EndsInDays int
+	Provider   string
+}
+
+// CartOrderSeed — неоплаченный заказ; естественный ключ — пользователь и сервис
+type CartOrderSeed struct {
+	User    int
+	Service int
+	Price   int
+}
+
+// Дерево каталога и известные сервисы; остальные сервисы получают выдуманные названия
+var catalog = []struct {
+	name     string
+	children []string
+	services []string
+}{
+	{name: "Музыка", children: []string{"Подкасты"}, services: []string{"Яндекс Музыка", "Spotify", "Apple Music", "Deezer", "Звук"}},
+	{name: "Кино", children: []string{"Сериалы", "Аниме"}, services: []string{"Кинопоиск", "Okko", "Иви", "Netflix", "Start"}},
+	{name: "Спорт", children: []string{"Фитнес"}, services: []string{"Матч ТВ", "DAZN", "Strava", "Nike Training Club"}},
+	{name: "Игры", services: []string{"Xbox Game Pass", "PlayStation Plus", "Steam", "GeForce Now"}},
+	{name: "Книги", services: []string{"Литрес", "Букмейт", "Storytel", "Audible"}},
+	{name: "Облако", services: []string{"Яндекс 360", "Google One", "iCloud+", "Dropbox"}},
+}
+
+var providers = []string{"yookassa", "cloudpayments", "stripe"}
+
+var countries = []string{"RU", "RU", "RU", "KZ", "BY", "DE", "US"}
+
+// Generate строит набор данных. Значения берутся из генератора в одном и том же
+// порядке, поэтому результат зависит только от opts
+func Generate(opts Options) *Dataset {
+	faker := gofakeit.New(opts.Seed)
+	data := &Dataset{}
+
+	data.Users = append(data.Users, UserSeed{
+		Email:     AdminEmail,
+		FirstName: "Admin",
+		LastName:  "Admin",
+		Role:      models.RoleAdmin,
+	})
+	for i := 1; i <= opts.Users; i++ {
+		data.Users = append(data.Users, UserSeed{
+			Email:     fmt.Sprintf("user%03d@example.com", i),
+			FirstName: faker.FirstName(),
+			LastName:  faker.LastName(),
+			Role:      models.RoleUser,
+			Address: models.BillingAddress{
+				Country:    faker.RandomString(countries),
+				City:       faker.City(),
+				PostalCode: faker.Zip(),
+				Line1:      faker.Street(),
+			},
+		})
+	}
+
+	for _, root := range catalog {
+		rootIndex := len(data.Categories)
+		data.Categories = append(data.Categories, CategorySeed{Name: root.name, Parent: -1})
+		for _, child := range root.children {
+			data.Categories = append(data.Categories, CategorySeed{Name: child, Parent: rootIndex})
+		}
+
+		names := map[string]bool{}
+		for i := 0; i < opts.ServicesPerCategory; i++ {
+			name := faker.AppName()
+			if i < len(root.services) {
+				name = root.services[i]
+			}
+			for names[name] {
+				name = faker.AppName()
+			}
+			names[name] = true
+
+			data.Services = append(data.Services, ServiceSeed{
+				Name:        name,
+				Description: faker.Sentence(12),
+				Category:    rootIndex,
+				Website:     "https://" + slug(name) + ".example.com",
+				Color:       [3]uint8{uint8(faker.Number(0, 255)), uint8(faker.Number(0, 255)), uint8(faker.Number(0, 255))},
+			})
+		}
+	}
+
+	if len(data.Services) == 0 {
+		return data
+	}
+
+	// у администратора подписок и корзины нет
+	for user := 1; user < len(data.Users); user++ {
+		services := faker.Number(0, opts.SubscriptionsPerUser)
+		for _, service := range pick(faker, len(data.Services), services) {
+			sub := SubscriptionSeed{
+				User:           user,
+				Service:        service,
+				Price:          faker.Number(9, 99)*10 - 1,
+				Interval:       models.IntervalMonth,
+				StartedDaysAgo: faker.Number(0, 720),
+				Provider:       faker.RandomString(providers),
+			}
+			if faker.Number(1, 5) == 1 {
+				sub.Interval = models.IntervalYear
+				sub.Price *= 10
+			}
+			// часть подписок уже закончилась, часть закончится в ближайший месяц
+			if faker.Number(1, 4) == 1 {
+				sub.EndsInDays = faker.Number(-sub.StartedDaysAgo, 30)
+				if sub.EndsInDays == 0 {
+					sub.EndsInDays = 1
+				}
+			}
+			data.Subscriptions = append(data.Subscriptions, sub)
+		}
+
+		cart := faker.Number(0, opts.CartOrders)
+		for _, service := range pick(faker, len(data.Services), cart) {
+			data.CartOrders = append(data.CartOrders, CartOrderSeed{
+				User:    user,
+				Service: service,
+				Price:   faker.Number(9, 99)*10 - 1,
+			})
+		}
+	}
+
+	return data
+}
+
+// pick выбирает n разных индексов из [0, total)
+func pick(faker *gofakeit.Faker, total, n int) []int {
+	indexes := make([]int, total)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	faker.ShuffleAnySlice(indexes)
+
+	return indexes[:min(n, total)]
+}
+
+// slug оставляет от названия латинские буквы и цифры; кириллица транслитерируется
+func slug(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case translit[r] != "":
+			b.WriteString(translit[r])
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+
+	return strings.Trim(b.String(), "-")
+}
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "sch", 'ы': "y", 'э': "e", 'ю': "yu", 'я': "ya",
+}
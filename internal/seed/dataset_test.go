@@ -0,0 +1,80 @@
+package seed
+
+import (
+	"testing"
+
+	"effective-project/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_SameSeedSameData(t *testing.T) {
+	opts := DefaultOptions()
+
+	assert.Equal(t, Generate(opts), Generate(opts))
+
+	other := opts
+	other.Seed++
+	assert.NotEqual(t, Generate(opts).Users, Generate(other).Users)
+}
+
+func TestGenerate_RespectsVolumes(t *testing.T) {
+	opts := Options{Seed: 1, Users: 7, ServicesPerCategory: 6, SubscriptionsPerUser: 2, CartOrders: 1}
+	data := Generate(opts)
+
+	assert.Len(t, data.Users, opts.Users+1)
+	assert.Equal(t, AdminEmail, data.Users[0].Email)
+	assert.Equal(t, models.RoleAdmin, data.Users[0].Role)
+	assert.Len(t, data.Services, opts.ServicesPerCategory*len(catalog))
+
+	perUser := map[int]int{}
+	for _, sub := range data.Subscriptions {
+		assert.NotZero(t, sub.User, "у администратора нет подписок")
+		perUser[sub.User]++
+	}
+	for _, n := range perUser {
+		assert.LessOrEqual(t, n, opts.SubscriptionsPerUser)
+	}
+}
+
+// естественные ключи не должны повторяться, иначе вторая запись молча сольётся с первой
+func TestGenerate_NaturalKeysAreUnique(t *testing.T) {
+	data := Generate(Options{Seed: 7, Users: 200, ServicesPerCategory: 30, SubscriptionsPerUser: 10, CartOrders: 5})
+
+	emails := map[string]bool{}
+	for _, u := range data.Users {
+		assert.False(t, emails[u.Email], u.Email)
+		emails[u.Email] = true
+	}
+
+	type serviceKey struct {
+		name     string
+		category int
+	}
+	services := map[serviceKey]bool{}
+	for _, svc := range data.Services {
+		key := serviceKey{svc.Name, svc.Category}
+		assert.False(t, services[key], svc.Name)
+		services[key] = true
+	}
+
+	type pair struct{ user, service int }
+	subscriptions := map[pair]bool{}
+	for _, sub := range data.Subscriptions {
+		key := pair{sub.User, sub.Service}
+		assert.False(t, subscriptions[key])
+		subscriptions[key] = true
+	}
+	carts := map[pair]bool{}
+	for _, order := range data.CartOrders {
+		key := pair{order.User, order.Service}
+		assert.False(t, carts[key])
+		carts[key] = true
+	}
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "yandeks-muzyka", slug("Яндекс Музыка"))
+	assert.Equal(t, "icloud", slug("iCloud+"))
+	assert.Equal(t, "xbox-game-pass", slug("Xbox Game Pass"))
+}
@@ -0,0 +1,347 @@
+// Package seed наполняет базу для локальной разработки воспроизводимым набором данных.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"log/slog"
+	"strings"
+	"time"
+
+	"effective-project/internal/media"
+	"effective-project/internal/models"
+	"effective-project/internal/service"
+	"effective-project/internal/storage"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Стороны миниатюр логотипа — те же, что сохраняет загрузка логотипа
+var logoSizes = []int{256, 128, 64}
+
+// Оплаты строятся не больше чем за столько периодов подписки
+const maxPeriods = 24
+
+// Report — сколько записей создано; найденные по естественному ключу не считаются
+type Report struct {
+	Users         int
+	Categories    int
+	Services      int
+	Subscriptions int
+	Orders        int
+	Payments      int
+	LedgerEntries int
+}
+
+// Seeder записывает Dataset в базу. Каждая запись ищется по естественному ключу
+// и создаётся, только если её нет, поэтому повторный запуск ничего не дублирует
+// и не меняет данные, поправленные вручную
+type Seeder struct {
+	db       *gorm.DB
+	blobs    storage.BlobStore
+	mediaURL string
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+func New(db *gorm.DB, blobs storage.BlobStore, mediaURL string, logger *slog.Logger) *Seeder {
+	return &Seeder{
+		db:       db,
+		blobs:    blobs,
+		mediaURL: strings.TrimRight(mediaURL, "/"),
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Reset очищает все таблицы приложения, кроме истории миграций
+func (s *Seeder) Reset(ctx context.Context) error {
+	op := "seed.reset"
+
+	var tables []string
+	err := s.db.WithContext(ctx).
+		Raw("SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'").
+		Scan(&tables).Error
+	if err != nil {
+		s.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+
+	for i, table := range tables {
+		tables[i] = `"` + strings.ReplaceAll(table, `"`, `""`) + `"`
+	}
+
+	if err := s.db.WithContext(ctx).Exec("TRUNCATE " + strings.Join(tables, ", ") + " CASCADE").Error; err != nil {
+		s.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return err
+	}
+
+	s.logger.Info("tables truncated", slog.String("op", op), slog.Int("tables", len(tables)))
+	return nil
+}
+
+// Run записывает данные одной транзакцией. Логотипы сохраняются в хранилище
+// до неё: ключ зависит от содержимого, и повторная запись ничего не меняет
+func (s *Seeder) Run(ctx context.Context, data *Dataset) (*Report, error) {
+	op := "seed.run"
+
+	adminHash, err := bcrypt.GenerateFromPassword([]byte(AdminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	userHash, err := bcrypt.GenerateFromPassword([]byte(UserPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	logos := make([]string, len(data.Services))
+	for i, svc := range data.Services {
+		logos[i], err = s.storeLogo(ctx, svc.Color)
+		if err != nil {
+			s.logger.Error("failed to store logo", slog.String("op", op), slog.String("service", svc.Name), slog.Any("error", err))
+			return nil, err
+		}
+	}
+
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	report := &Report{}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		users := make([]uuid.UUID, len(data.Users))
+		for i, u := range data.Users {
+			hash := userHash
+			if u.Role == models.RoleAdmin {
+				hash = adminHash
+			}
+
+			var row models.User
+			res := tx.Where("email = ?", u.Email).Attrs(models.User{
+				Email:          u.Email,
+				Password:       string(hash),
+				FirstName:      u.FirstName,
+				LastName:       u.LastName,
+				BillingAddress: u.Address,
+				Roles:          u.Role,
+			}).FirstOrCreate(&row)
+			if res.Error != nil {
+				return res.Error
+			}
+			users[i] = row.ID
+			report.Users += int(res.RowsAffected)
+		}
+
+		categories := make([]uuid.UUID, len(data.Categories))
+		for i, c := range data.Categories {
+			var row models.Category
+			query := tx.Where("name = ? AND parent_id IS NULL", c.Name)
+			attrs := models.Category{Name: c.Name}
+			if c.Parent >= 0 {
+				query = tx.Where("name = ? AND parent_id = ?", c.Name, categories[c.Parent])
+				attrs.ParentID = &categories[c.Parent]
+			}
+
+			res := query.Attrs(attrs).FirstOrCreate(&row)
+			if res.Error != nil {
+				return res.Error
+			}
+			categories[i] = row.ID
+			report.Categories += int(res.RowsAffected)
+		}
+
+		services := make([]uuid.UUID, len(data.Services))
+		for i, svc := range data.Services {
+			var row models.Service
+			res := tx.Where("name = ? AND category_id = ?", svc.Name, categories[svc.Category]).Attrs(models.Service{
+				Name:        svc.Name,
+				Description: svc.Description,
+				CategoryID:  categories[svc.Category],
+				Website:     svc.Website,
+				LogoUrl:     logos[i],
+			}).FirstOrCreate(&row)
+			if res.Error != nil {
+				return res.Error
+			}
+			services[i] = row.ID
+			report.Services += int(res.RowsAffected)
+		}
+
+		for _, sub := range data.Subscriptions {
+			start := today.AddDate(0, 0, -sub.StartedDaysAgo)
+			row := models.Subscription{
+				Base:      models.Base{CreatedAt: start},
+				UserID:    users[sub.User],
+				ServiceID: services[sub.Service],
+				StartDate: start,
+				Price:     sub.Price,
+				ListPrice: sub.Price,
+				Interval:  sub.Interval,
+				Status:    models.SubscriptionActive,
+			}
+			if sub.EndsInDays != 0 {
+				end := today.AddDate(0, 0, sub.EndsInDays)
+				row.EndDate = &end
+				if !end.After(today) {
+					row.Status = models.SubscriptionCancelled
+				}
+			}
+
+			res := tx.Where("user_id = ? AND service_id = ?", row.UserID, row.ServiceID).Attrs(row).FirstOrCreate(&row)
+			if res.Error != nil {
+				return res.Error
+			}
+			report.Subscriptions += int(res.RowsAffected)
+
+			if err := s.payments(tx, &row, sub.Provider, today, report); err != nil {
+				return err
+			}
+		}
+
+		for _, cart := range data.CartOrders {
+			var row models.Order
+			res := tx.Where("user_id = ? AND service_id = ? AND is_paid = false", users[cart.User], services[cart.Service]).
+				Attrs(models.Order{
+					UserID:    users[cart.User],
+					ServiceID: services[cart.Service],
+					ListPrice: cart.Price,
+					Price:     cart.Price,
+				}).FirstOrCreate(&row)
+			if res.Error != nil {
+				return res.Error
+			}
+			report.Orders += int(res.RowsAffected)
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("db error", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// payments создаёт оплаченный заказ и проведённый платёж за каждый начавшийся период подписки.
+// Заказ периода находится по пользователю, сервису и началу периода,
+// поэтому при запуске позже добавляются только новые периоды
+func (s *Seeder) payments(tx *gorm.DB, sub *models.Subscription, provider string, today time.Time, report *Report) error {
+	until := today
+	if sub.EndDate != nil && sub.EndDate.Before(until) {
+		until = *sub.EndDate
+	}
+
+	start := sub.StartDate.UTC()
+	for period := 0; period < maxPeriods; period++ {
+		paidAt := start.AddDate(0, period, 0)
+		if sub.Interval == models.IntervalYear {
+			paidAt = start.AddDate(period, 0, 0)
+		}
+		if paidAt.After(until) {
+			break
+		}
+
+		var order models.Order
+		res := tx.Where("user_id = ? AND service_id = ? AND created_at = ?", sub.UserID, sub.ServiceID, paidAt).
+			Attrs(models.Order{
+				Base:      models.Base{CreatedAt: paidAt},
+				UserID:    sub.UserID,
+				ServiceID: sub.ServiceID,
+				IsPaid:    true,
+				ListPrice: sub.ListPrice,
+				Discount:  sub.Discount,
+				Price:     sub.Price,
+			}).FirstOrCreate(&order)
+		if res.Error != nil {
+			return res.Error
+		}
+		report.Orders += int(res.RowsAffected)
+
+		var payment models.Payment
+		res = tx.Where("order_id = ? AND attempt = 1", order.ID).Attrs(models.Payment{
+			Base:           models.Base{CreatedAt: paidAt},
+			SubscriptionID: sub.ID,
+			OrderID:        order.ID,
+			Amount:         sub.Price,
+			Currency:       models.DefaultCurrency,
+			PaidAt:         paidAt,
+			PaymentStatus:  models.PaymentSucces,
+			Provider:       provider,
+			Attempt:        1,
+		}).FirstOrCreate(&payment)
+		if res.Error != nil {
+			return res.Error
+		}
+		report.Payments += int(res.RowsAffected)
+
+		// проводки пишутся только вместе с новым платежом: у найденного они уже есть
+		if res.RowsAffected == 1 {
+			entries := service.ChargeEntries(&payment)
+			if err := tx.Create(&entries).Error; err != nil {
+				return err
+			}
+			report.LedgerEntries += len(entries)
+		}
+	}
+
+	return nil
+}
+
+// storeLogo рисует логотип цвета c и сохраняет миниатюры так же, как загрузка логотипа.
+// Возвращает ссылку на самую крупную
+func (s *Seeder) storeLogo(ctx context.Context, c [3]uint8) (string, error) {
+	img := logo(color.NRGBA{R: c[0], G: c[1], B: c[2], A: 255}, logoSizes[0])
+
+	full, err := media.EncodePNG(img)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(full)
+	prefix := "logos/seed/" + hex.EncodeToString(sum[:8])
+
+	var src image.Image = img
+	for _, size := range logoSizes {
+		thumb := media.Thumbnail(src, size)
+		src = thumb
+
+		encoded, err := media.EncodePNG(thumb)
+		if err != nil {
+			return "", err
+		}
+
+		key := fmt.Sprintf("%s/%d.png", prefix, size)
+		if err := s.blobs.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), "image/png"); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%s/%s/%d.png", s.mediaURL, prefix, logoSizes[0]), nil
+}
+
+// logo — квадрат цвета c с белым кругом в центре
+func logo(c color.NRGBA, size int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	center, radius := size/2, size/4
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := x-center, y-center
+			if dx*dx+dy*dy <= radius*radius {
+				img.SetNRGBA(x, y, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+			} else {
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+
+	return img
+}
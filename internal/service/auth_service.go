@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"effective-project/internal/repository"
 	"errors"
 	"log/slog"
@@ -25,7 +26,7 @@ type UserClaims struct {
 }
 
 type AuthService interface {
-	Login(email, password string) (string, error)
+	Login(ctx context.Context, email, password string) (string, error)
 
 	GenerateToken(userID uuid.UUID, role string) (string, error)
 }
@@ -44,10 +45,10 @@ func NewAuthService(
 	return &authService{userRepo: userRepo, jwtCfg: jwtCfg, logger: logger}
 }
 
-func (s *authService) Login(email, password string) (string, error) {
+func (s *authService) Login(ctx context.Context, email, password string) (string, error) {
 	s.logger.Debug("Попытка входа", "email", email)
 
-	user, err := s.userRepo.GetByEmail(email)
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		s.logger.Warn("неверные учетные данные — пользователь не найден", "email", email)
 		return "", ErrInvalidCredentials
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/repository"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// budgetTrackingSubscriptionService пересчитывает бюджеты пользователя после изменения его подписок
+type budgetTrackingSubscriptionService struct {
+	SubscriptionService
+
+	subscriptionRepo repository.SubscriptionRepository
+	budgets          BudgetService
+	logger           *slog.Logger
+}
+
+func NewBudgetTrackingSubscriptionService(
+	inner SubscriptionService,
+	subscriptionRepo repository.SubscriptionRepository,
+	budgets BudgetService,
+	logger *slog.Logger,
+) SubscriptionService {
+	return &budgetTrackingSubscriptionService{
+		SubscriptionService: inner,
+		subscriptionRepo:    subscriptionRepo,
+		budgets:             budgets,
+		logger:              logger,
+	}
+}
+
+func (s *budgetTrackingSubscriptionService) Create(ctx context.Context, req *dto.SubscriptionCreateRequest) (*models.Subscription, error) {
+	subscription, err := s.SubscriptionService.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluateBudgets(ctx, s.budgets, subscription.UserID, s.logger)
+	return subscription, nil
+}
+
+func (s *budgetTrackingSubscriptionService) Update(ctx context.Context, id string, req *dto.SubscriptionUpdateRequest) (*models.Subscription, error) {
+	subscription, err := s.SubscriptionService.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluateBudgets(ctx, s.budgets, subscription.UserID, s.logger)
+	return subscription, nil
+}
+
+func (s *budgetTrackingSubscriptionService) Delete(ctx context.Context, id string) error {
+	subscription, _ := s.subscriptionRepo.GetModelByID(ctx, id)
+
+	if err := s.SubscriptionService.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if subscription != nil {
+		evaluateBudgets(ctx, s.budgets, subscription.UserID, s.logger)
+	}
+	return nil
+}
+
+// budgetTrackingPaymentService пересчитывает бюджеты владельца подписки после изменения платежей
+type budgetTrackingPaymentService struct {
+	PaymentService
+
+	subscriptionRepo repository.SubscriptionRepository
+	budgets          BudgetService
+	logger           *slog.Logger
+}
+
+func NewBudgetTrackingPaymentService(
+	inner PaymentService,
+	subscriptionRepo repository.SubscriptionRepository,
+	budgets BudgetService,
+	logger *slog.Logger,
+) PaymentService {
+	return &budgetTrackingPaymentService{
+		PaymentService:   inner,
+		subscriptionRepo: subscriptionRepo,
+		budgets:          budgets,
+		logger:           logger,
+	}
+}
+
+func (s *budgetTrackingPaymentService) Create(ctx context.Context, req *dto.PaymentCreateRequest) (models.Payment, error) {
+	payment, err := s.PaymentService.Create(ctx, req)
+	if err != nil {
+		return payment, err
+	}
+
+	s.evaluateForSubscription(ctx, payment.SubscriptionID)
+	return payment, nil
+}
+
+func (s *budgetTrackingPaymentService) Update(ctx context.Context, id string, req *dto.PaymentUpdateRequest) (*models.Payment, error) {
+	payment, err := s.PaymentService.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.evaluateForSubscription(ctx, payment.SubscriptionID)
+	return payment, nil
+}
+
+func (s *budgetTrackingPaymentService) Delete(ctx context.Context, id string) error {
+	payment, _ := s.PaymentService.GetByID(ctx, id)
+
+	if err := s.PaymentService.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if payment != nil {
+		s.evaluateForSubscription(ctx, payment.SubscriptionID)
+	}
+	return nil
+}
+
+func (s *budgetTrackingPaymentService) evaluateForSubscription(ctx context.Context, subscriptionID uuid.UUID) {
+	subscription, err := s.subscriptionRepo.GetModelByID(afterCommit(ctx), subscriptionID.String())
+	if err != nil {
+		s.logger.Warn("service.budget_hooks: failed to get subscription for payment", slog.Any("error", err))
+		return
+	}
+
+	evaluateBudgets(ctx, s.budgets, subscription.UserID, s.logger)
+}
+
+// evaluateBudgets не прерывает основную операцию: ошибки пересчёта только логируются,
+// а пропущенные пороги догонит плановая проверка
+func evaluateBudgets(ctx context.Context, budgets BudgetService, userID uuid.UUID, logger *slog.Logger) {
+	if err := budgets.EvaluateUser(afterCommit(ctx), userID); err != nil {
+		logger.Warn("service.budget_hooks: failed to evaluate budgets",
+			slog.Any("user_id", userID),
+			slog.Any("error", err),
+		)
+	}
+}
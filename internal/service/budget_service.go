@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/notification"
+	"effective-project/internal/repository"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrBudgetNotFound = errors.New("бюджет не найден")
+
+// Пороги расхода бюджета в процентах, о которых уведомляем пользователя
+var budgetThresholds = []int{100, 80}
+
+type BudgetService interface {
+	Create(ctx context.Context, userID uuid.UUID, req *dto.BudgetCreateRequest) (*models.Budget, error)
+
+	List(ctx context.Context, userID uuid.UUID) ([]dto.BudgetStatus, error)
+
+	GetByID(ctx context.Context, userID uuid.UUID, id string) (*dto.BudgetStatus, error)
+
+	Update(ctx context.Context, userID uuid.UUID, id string, req *dto.BudgetUpdateRequest) (*models.Budget, error)
+
+	Delete(ctx context.Context, userID uuid.UUID, id string) error
+
+	EvaluateUser(ctx context.Context, userID uuid.UUID) error
+
+	EvaluateAll(ctx context.Context) error
+}
+
+// totalCalculator — часть SubscriptionService, по которой считается расход бюджета
+type totalCalculator interface {
+	CalculateTotal(ctx context.Context, f dto.TotalFilter) (int, error)
+}
+
+type budgetService struct {
+	budgetRepo repository.BudgetRepository
+	totals     totalCalculator
+	notifier   notification.Notifier
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+func NewBudgetService(
+	budgetRepo repository.BudgetRepository,
+	totals totalCalculator,
+	notifier notification.Notifier,
+	logger *slog.Logger,
+) BudgetService {
+	return &budgetService{
+		budgetRepo: budgetRepo,
+		totals:     totals,
+		notifier:   notifier,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+func (s *budgetService) Create(ctx context.Context, userID uuid.UUID, req *dto.BudgetCreateRequest) (*models.Budget, error) {
+	budget := &models.Budget{
+		UserID:       userID,
+		MonthlyLimit: req.MonthlyLimit,
+		Currency:     req.Currency,
+		CategoryID:   req.CategoryID,
+		ServiceID:    req.ServiceID,
+	}
+
+	if err := s.budgetRepo.Create(ctx, budget); err != nil {
+		s.logger.Error("service.budget.create: failed to create budget", slog.Any("error", err))
+		return nil, err
+	}
+
+	if _, err := s.evaluate(ctx, budget); err != nil {
+		s.logger.Warn("service.budget.create: failed to evaluate budget", slog.Any("error", err))
+	}
+
+	return budget, nil
+}
+
+func (s *budgetService) List(ctx context.Context, userID uuid.UUID) ([]dto.BudgetStatus, error) {
+	budgets, err := s.budgetRepo.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("service.budget.list: failed to get budgets", slog.Any("error", err))
+		return nil, err
+	}
+
+	result := make([]dto.BudgetStatus, 0, len(budgets))
+	for i := range budgets {
+		status, err := s.status(ctx, &budgets[i])
+		if err != nil {
+			s.logger.Error("service.budget.list: failed to calculate spending", slog.Any("error", err))
+			return nil, err
+		}
+		result = append(result, *status)
+	}
+
+	return result, nil
+}
+
+func (s *budgetService) GetByID(ctx context.Context, userID uuid.UUID, id string) (*dto.BudgetStatus, error) {
+	budget, err := s.getOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.status(ctx, budget)
+}
+
+func (s *budgetService) Update(ctx context.Context, userID uuid.UUID, id string, req *dto.BudgetUpdateRequest) (*models.Budget, error) {
+	budget, err := s.getOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MonthlyLimit != nil {
+		budget.MonthlyLimit = *req.MonthlyLimit
+	}
+	if req.Currency != nil {
+		budget.Currency = *req.Currency
+	}
+	if req.CategoryID != nil {
+		budget.CategoryID = req.CategoryID
+	}
+	if req.ServiceID != nil {
+		budget.ServiceID = req.ServiceID
+	}
+
+	// после изменения лимита пороги считаются заново
+	budget.AlertedThreshold = 0
+
+	if err := s.budgetRepo.Update(ctx, budget); err != nil {
+		s.logger.Error("service.budget.update: failed to update budget", slog.Any("error", err))
+		return nil, err
+	}
+
+	if _, err := s.evaluate(ctx, budget); err != nil {
+		s.logger.Warn("service.budget.update: failed to evaluate budget", slog.Any("error", err))
+	}
+
+	return budget, nil
+}
+
+func (s *budgetService) Delete(ctx context.Context, userID uuid.UUID, id string) error {
+	if _, err := s.getOwned(ctx, userID, id); err != nil {
+		return err
+	}
+
+	if err := s.budgetRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("service.budget.delete: failed to delete budget", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *budgetService) EvaluateUser(ctx context.Context, userID uuid.UUID) error {
+	budgets, err := s.budgetRepo.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("service.budget.evaluate_user: failed to get budgets", slog.Any("error", err))
+		return err
+	}
+
+	var errs []error
+	for i := range budgets {
+		if _, err := s.evaluate(ctx, &budgets[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *budgetService) EvaluateAll(ctx context.Context) error {
+	userIDs, err := s.budgetRepo.ListUserIDs(ctx)
+	if err != nil {
+		s.logger.Error("service.budget.evaluate_all: failed to get users", slog.Any("error", err))
+		return err
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.EvaluateUser(ctx, userID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// evaluate считает расход и отправляет уведомление, если пересечён новый порог
+func (s *budgetService) evaluate(ctx context.Context, budget *models.Budget) (*dto.BudgetStatus, error) {
+	status, err := s.status(ctx, budget)
+	if err != nil {
+		s.logger.Error("service.budget.evaluate: failed to calculate spending", slog.Any("error", err))
+		return nil, err
+	}
+
+	if budget.AlertedPeriod != status.Period {
+		budget.AlertedPeriod = status.Period
+		budget.AlertedThreshold = 0
+	}
+
+	crossed := 0
+	for _, threshold := range budgetThresholds {
+		if status.Spent*100 >= budget.MonthlyLimit*threshold {
+			crossed = threshold
+			break
+		}
+	}
+
+	if crossed <= budget.AlertedThreshold {
+		return status, nil
+	}
+
+	if err := s.notifier.Notify(ctx, notification.Notification{
+		UserID: budget.UserID,
+		Type:   notification.TypeBudgetThreshold,
+		Title:  fmt.Sprintf("Израсходовано %d%% бюджета", crossed),
+		Message: fmt.Sprintf(
+			"Расходы на подписки за %s составили %d %s из %d %s",
+			status.Period, status.Spent, budget.Currency, budget.MonthlyLimit, budget.Currency,
+		),
+		Data: map[string]any{
+			"budget_id": budget.ID,
+			"threshold": crossed,
+			"spent":     status.Spent,
+			"limit":     budget.MonthlyLimit,
+		},
+	}); err != nil {
+		s.logger.Error("service.budget.evaluate: failed to send notification", slog.Any("error", err))
+		return nil, err
+	}
+
+	budget.AlertedThreshold = crossed
+	if err := s.budgetRepo.Update(ctx, budget); err != nil {
+		s.logger.Error("service.budget.evaluate: failed to save alert state", slog.Any("error", err))
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func (s *budgetService) status(ctx context.Context, budget *models.Budget) (*dto.BudgetStatus, error) {
+	period := startOfMonth(s.now().UTC())
+
+	f := dto.TotalFilter{
+		From:   period,
+		To:     period,
+		UserID: budget.UserID,
+	}
+	if budget.ServiceID != nil {
+		f.ServiceID = *budget.ServiceID
+	}
+	if budget.CategoryID != nil {
+		f.CategoryID = *budget.CategoryID
+	}
+
+	spent, err := s.totals.CalculateTotal(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.BudgetStatus{
+		Budget:  *budget,
+		Period:  period.Format(monthLayout),
+		Spent:   spent,
+		Percent: math.Round(float64(spent)/float64(budget.MonthlyLimit)*10000) / 100,
+	}, nil
+}
+
+func (s *budgetService) getOwned(ctx context.Context, userID uuid.UUID, id string) (*models.Budget, error) {
+	budget, err := s.budgetRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("service.budget.get_by_id: failed to get budget", slog.Any("error", err))
+		return nil, err
+	}
+
+	if budget.UserID != userID {
+		return nil, ErrBudgetNotFound
+	}
+
+	return budget, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type totalsStub struct {
+	total   int
+	filters []dto.TotalFilter
+}
+
+func (s *totalsStub) CalculateTotal(ctx context.Context, f dto.TotalFilter) (int, error) {
+	s.filters = append(s.filters, f)
+	return s.total, nil
+}
+
+func newTestBudgetService(budget *models.Budget, totals *totalsStub, notifier *mock.MockNotifier) (*budgetService, *int) {
+	updates := 0
+	repo := &mock.MockBudgetRepository{
+		ListByUserFn: func(ctx context.Context, userID uuid.UUID) ([]models.Budget, error) {
+			return []models.Budget{*budget}, nil
+		},
+		GetByIDFn: func(ctx context.Context, id string) (*models.Budget, error) {
+			b := *budget
+			return &b, nil
+		},
+		UpdateFn: func(ctx context.Context, b *models.Budget) error {
+			updates++
+			*budget = *b
+			return nil
+		},
+	}
+
+	svc := NewBudgetService(repo, totals, notifier, newLogger()).(*budgetService)
+	svc.now = func() time.Time { return time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC) }
+	return svc, &updates
+}
+
+func TestBudgetService_EvaluateUser_Thresholds(t *testing.T) {
+	userID := uuid.New()
+	categoryID := uuid.New()
+	budget := &models.Budget{Base: models.Base{ID: uuid.New()}, UserID: userID, MonthlyLimit: 1000, Currency: "RUB", CategoryID: &categoryID}
+
+	totals := &totalsStub{total: 500}
+	notifier := &mock.MockNotifier{}
+	svc, _ := newTestBudgetService(budget, totals, notifier)
+
+	// ниже 80% — уведомлений нет
+	assert.NoError(t, svc.EvaluateUser(context.Background(), userID))
+	assert.Empty(t, notifier.Sent)
+
+	assert.Equal(t, categoryID, totals.filters[0].CategoryID)
+	assert.Equal(t, userID, totals.filters[0].UserID)
+	assert.Equal(t, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), totals.filters[0].From)
+
+	// 80%
+	totals.total = 850
+	assert.NoError(t, svc.EvaluateUser(context.Background(), userID))
+	assert.Len(t, notifier.Sent, 1)
+	assert.Equal(t, 80, notifier.Sent[0].Data["threshold"])
+
+	// повторная проверка на том же пороге не дублирует уведомление
+	assert.NoError(t, svc.EvaluateUser(context.Background(), userID))
+	assert.Len(t, notifier.Sent, 1)
+
+	// 100%
+	totals.total = 1000
+	assert.NoError(t, svc.EvaluateUser(context.Background(), userID))
+	assert.Len(t, notifier.Sent, 2)
+	assert.Equal(t, 100, notifier.Sent[1].Data["threshold"])
+	assert.Equal(t, "2025-03", budget.AlertedPeriod)
+}
+
+func TestBudgetService_EvaluateUser_NewPeriodResetsAlerts(t *testing.T) {
+	userID := uuid.New()
+	budget := &models.Budget{
+		Base:             models.Base{ID: uuid.New()},
+		UserID:           userID,
+		MonthlyLimit:     1000,
+		AlertedThreshold: 100,
+		AlertedPeriod:    "2025-02",
+	}
+
+	notifier := &mock.MockNotifier{}
+	svc, _ := newTestBudgetService(budget, &totalsStub{total: 900}, notifier)
+
+	assert.NoError(t, svc.EvaluateUser(context.Background(), userID))
+	assert.Len(t, notifier.Sent, 1)
+	assert.Equal(t, 80, budget.AlertedThreshold)
+}
+
+func TestBudgetService_GetByID_OtherUser(t *testing.T) {
+	budget := &models.Budget{Base: models.Base{ID: uuid.New()}, UserID: uuid.New(), MonthlyLimit: 1000}
+	svc, _ := newTestBudgetService(budget, &totalsStub{}, &mock.MockNotifier{})
+
+	_, err := svc.GetByID(context.Background(), uuid.New(), budget.ID.String())
+
+	assert.ErrorIs(t, err, ErrBudgetNotFound)
+}
+
+func TestBudgetService_List(t *testing.T) {
+	budget := &models.Budget{Base: models.Base{ID: uuid.New()}, UserID: uuid.New(), MonthlyLimit: 400}
+	svc, updates := newTestBudgetService(budget, &totalsStub{total: 100}, &mock.MockNotifier{})
+
+	list, err := svc.List(context.Background(), budget.UserID)
+
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, 100, list[0].Spent)
+	assert.Equal(t, 25.0, list[0].Percent)
+	assert.Equal(t, "2025-03", list[0].Period)
+	assert.Zero(t, *updates)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/cache"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/repository"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+var ErrUnknownCachePrefix = errors.New("неизвестный префикс кеша")
+
+// DefaultWarmHotUsers — сколько активных пользователей загружается при прогреве по умолчанию
+const DefaultWarmHotUsers = 100
+
+// Активность пользователя для прогрева считается по заказам за этот период
+const hotUsersWindow = 30 * 24 * time.Hour
+
+type CacheService interface {
+	// Stats возвращает счётчики попаданий по уровням и пространствам ключей
+	// этой реплики и занятость общего хранилища
+	Stats(ctx context.Context) dto.CacheStats
+
+	// Warm загружает в кеш все категории и сервисы и самых активных пользователей
+	Warm(ctx context.Context, req *dto.CacheWarmRequest) (*dto.CacheWarmReport, error)
+
+	// Flush удаляет ключи зарегистрированного пространства
+	Flush(ctx context.Context, prefix string) (*dto.CacheFlushResult, error)
+}
+
+type cacheService struct {
+	stats         cache.StatsProvider
+	inspector     cache.Inspector
+	catalogRepo   repository.CatalogRepository
+	userRepo      repository.UserRepository
+	categoryCache cache.Cache[*models.Category]
+	serviceCache  cache.Cache[*models.Service]
+	userCache     cache.Cache[*models.User]
+	logger        *slog.Logger
+}
+
+func NewCacheService(
+	stats cache.StatsProvider,
+	inspector cache.Inspector,
+	catalogRepo repository.CatalogRepository,
+	userRepo repository.UserRepository,
+	categoryCache cache.Cache[*models.Category],
+	serviceCache cache.Cache[*models.Service],
+	userCache cache.Cache[*models.User],
+	logger *slog.Logger,
+) CacheService {
+	return &cacheService{
+		stats:         stats,
+		inspector:     inspector,
+		catalogRepo:   catalogRepo,
+		userRepo:      userRepo,
+		categoryCache: categoryCache,
+		serviceCache:  serviceCache,
+		userCache:     userCache,
+		logger:        logger,
+	}
+}
+
+func (s *cacheService) Stats(ctx context.Context) dto.CacheStats {
+	stats := s.stats.Stats()
+
+	usage, err := s.usage(ctx)
+	if err != nil {
+		s.logger.Warn("service.cache.stats: failed to get usage", slog.Any("error", err))
+	}
+
+	result := dto.CacheStats{
+		Local:          tierStats(stats.Local),
+		Remote:         tierStats(stats.Remote),
+		UsageAvailable: err == nil,
+		Prefixes:       make([]dto.CachePrefixStats, 0, len(cache.Prefixes)),
+	}
+
+	for _, prefix := range cache.Prefixes {
+		result.Prefixes = append(result.Prefixes, dto.CachePrefixStats{
+			Prefix:         prefix,
+			Keys:           usage[prefix].Keys,
+			MemoryBytes:    usage[prefix].Bytes,
+			CacheTierStats: tierStats(cache.PrefixStats(prefix)),
+		})
+	}
+
+	return result
+}
+
+// usage обходит все пространства; при первой ошибке хранилище считается недоступным
+func (s *cacheService) usage(ctx context.Context) (map[string]cache.Usage, error) {
+	result := make(map[string]cache.Usage, len(cache.Prefixes))
+	for _, prefix := range cache.Prefixes {
+		usage, err := s.inspector.Usage(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		result[prefix] = usage
+	}
+	return result, nil
+}
+
+func (s *cacheService) Warm(ctx context.Context, req *dto.CacheWarmRequest) (*dto.CacheWarmReport, error) {
+	hotUsers := req.HotUsers
+	if hotUsers <= 0 {
+		hotUsers = DefaultWarmHotUsers
+	}
+
+	categories, services, err := s.catalogRepo.Snapshot(ctx)
+	if err != nil {
+		s.logger.Error("service.cache.warm: failed to load catalog", slog.Any("error", err))
+		return nil, err
+	}
+
+	users, err := s.userRepo.Hot(ctx, time.Now().Add(-hotUsersWindow), hotUsers)
+	if err != nil {
+		s.logger.Error("service.cache.warm: failed to get hot users", slog.Any("error", err))
+		return nil, err
+	}
+
+	report := &dto.CacheWarmReport{}
+
+	for i := range categories {
+		if s.warm(s.categoryCache.Set(ctx, categories[i].ID.String(), &categories[i], categoryCacheTTL)) {
+			report.Categories++
+		}
+	}
+	for i := range services {
+		if s.warm(s.serviceCache.Set(ctx, services[i].ID.String(), &services[i], serviceCacheTTL)) {
+			report.Services++
+		}
+	}
+	for i := range users {
+		if s.warm(s.userCache.Set(ctx, users[i].ID.String(), &users[i], userCacheTTL)) {
+			report.Users++
+		}
+	}
+
+	s.logger.Info("cache warmed",
+		slog.Int("categories", report.Categories),
+		slog.Int("services", report.Services),
+		slog.Int("users", report.Users),
+	)
+
+	return report, nil
+}
+
+// warm сообщает, записано ли значение. Ошибка записи не прерывает прогрев
+func (s *cacheService) warm(err error) bool {
+	if err != nil && !errors.Is(err, cache.ErrCircuitOpen) {
+		s.logger.Warn("service.cache.warm: failed to set value", slog.Any("error", err))
+	}
+	return err == nil
+}
+
+func (s *cacheService) Flush(ctx context.Context, prefix string) (*dto.CacheFlushResult, error) {
+	if !cache.IsPrefix(prefix) {
+		return nil, ErrUnknownCachePrefix
+	}
+
+	deleted, err := s.inspector.DeletePrefix(ctx, prefix)
+	if err != nil {
+		s.logger.Error("service.cache.flush: failed to delete keys", slog.String("prefix", prefix), slog.Any("error", err))
+		return nil, err
+	}
+
+	s.logger.Info("cache flushed", slog.String("prefix", prefix), slog.Int64("deleted", deleted))
+
+	return &dto.CacheFlushResult{Prefix: prefix, Deleted: deleted}, nil
+}
+
+func tierStats(t cache.TierStats) dto.CacheTierStats {
+	result := dto.CacheTierStats{Hits: t.Hits, Misses: t.Misses}
+	if total := t.Hits + t.Misses; total > 0 {
+		result.HitRatio = float64(t.Hits) / float64(total)
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/cache"
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCacheService(store *cache.LRUStore, catalog *mock.MockCatalogRepository, users *mock.MockUserRepository) CacheService {
+	return NewCacheService(
+		store,
+		store,
+		catalog,
+		users,
+		cache.New[*models.Category](store, cache.PrefixCategory, cache.Options{}),
+		cache.NewTagged(store, cache.PrefixService, ServiceTags, cache.Options{}),
+		cache.NewTagged(store, cache.PrefixUser, UserTags, cache.Options{}),
+		newLogger(),
+	)
+}
+
+func TestCacheService_Warm(t *testing.T) {
+	store := cache.NewLRUStore(100, 0)
+	music := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Музыка"}
+	spotify := models.Service{Base: models.Base{ID: uuid.New()}, Name: "Spotify", CategoryID: music.ID}
+	user := models.User{Base: models.Base{ID: uuid.New()}, Email: "hot@mail.com"}
+
+	var gotLimit int
+	var gotSince time.Time
+	svc := newTestCacheService(store,
+		&mock.MockCatalogRepository{
+			SnapshotFn: func(ctx context.Context) ([]models.Category, []models.Service, error) {
+				return []models.Category{music}, []models.Service{spotify}, nil
+			},
+		},
+		&mock.MockUserRepository{
+			HotFn: func(ctx context.Context, since time.Time, limit int) ([]models.User, error) {
+				gotSince, gotLimit = since, limit
+				return []models.User{user}, nil
+			},
+		},
+	)
+
+	report, err := svc.Warm(context.Background(), &dto.CacheWarmRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, &dto.CacheWarmReport{Categories: 1, Services: 1, Users: 1}, report)
+	assert.Equal(t, DefaultWarmHotUsers, gotLimit)
+	assert.WithinDuration(t, time.Now().Add(-hotUsersWindow), gotSince, time.Minute)
+
+	for _, key := range []string{
+		cache.PrefixCategory + music.ID.String(),
+		cache.PrefixService + spotify.ID.String(),
+		cache.PrefixUser + user.ID.String(),
+	} {
+		_, err := store.Get(context.Background(), key)
+		assert.NoError(t, err, key)
+	}
+}
+
+func TestCacheService_FlushOnlyRegisteredPrefixes(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewLRUStore(100, 0)
+	svc := newTestCacheService(store, &mock.MockCatalogRepository{}, &mock.MockUserRepository{})
+
+	require.NoError(t, store.Set(ctx, cache.PrefixCategory+"1", []byte("1"), 0))
+	require.NoError(t, store.Set(ctx, cache.PrefixCategory+"2", []byte("2"), 0))
+	require.NoError(t, store.Set(ctx, cache.PrefixService+"1", []byte("3"), 0))
+
+	_, err := svc.Flush(ctx, "")
+	assert.ErrorIs(t, err, ErrUnknownCachePrefix)
+	_, err = svc.Flush(ctx, "cat")
+	assert.ErrorIs(t, err, ErrUnknownCachePrefix)
+
+	result, err := svc.Flush(ctx, cache.PrefixCategory)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.CacheFlushResult{Prefix: cache.PrefixCategory, Deleted: 2}, result)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestCacheService_StatsPerPrefix(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewLRUStore(100, 0)
+	svc := newTestCacheService(store, &mock.MockCatalogRepository{}, &mock.MockUserRepository{})
+
+	orders := cache.New[*models.Order](store, cache.PrefixOrder, cache.Options{})
+	require.NoError(t, orders.Set(ctx, "1", &models.Order{Price: 100}, time.Minute))
+	before := cache.PrefixStats(cache.PrefixOrder)
+	_, _ = orders.Get(ctx, "1")
+	_, _ = orders.Get(ctx, "2")
+
+	stats := svc.Stats(ctx)
+
+	assert.True(t, stats.UsageAvailable)
+	require.Len(t, stats.Prefixes, len(cache.Prefixes))
+	for _, p := range stats.Prefixes {
+		if p.Prefix != cache.PrefixOrder {
+			continue
+		}
+		assert.Equal(t, int64(1), p.Keys)
+		assert.Positive(t, p.MemoryBytes)
+		assert.Equal(t, before.Hits+1, p.Hits)
+		assert.Equal(t, before.Misses+1, p.Misses)
+	}
+}
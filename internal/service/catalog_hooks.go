@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+)
+
+// countsInvalidatingServiceService сбрасывает агрегаты категорий после изменения сервисов
+type countsInvalidatingServiceService struct {
+	ServiceService
+
+	catalog CatalogService
+}
+
+func NewCountsInvalidatingServiceService(inner ServiceService, catalog CatalogService) ServiceService {
+	return &countsInvalidatingServiceService{
+		ServiceService: inner,
+		catalog:        catalog,
+	}
+}
+
+func (s *countsInvalidatingServiceService) Create(ctx context.Context, req *dto.ServiceCreateRequest) (*models.Service, error) {
+	svc, err := s.ServiceService.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.catalog.InvalidateCounts(afterCommit(ctx))
+	return svc, nil
+}
+
+func (s *countsInvalidatingServiceService) Update(ctx context.Context, id string, req *dto.ServiceUpdateRequest) (*models.Service, error) {
+	svc, err := s.ServiceService.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.catalog.InvalidateCounts(afterCommit(ctx))
+	return svc, nil
+}
+
+func (s *countsInvalidatingServiceService) Delete(ctx context.Context, id string) error {
+	if err := s.ServiceService.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.catalog.InvalidateCounts(afterCommit(ctx))
+	return nil
+}
+
+// countsInvalidatingCategoryService сбрасывает агрегаты после переноса и удаления
+// категорий: суммы по поддереву зависят от структуры дерева
+type countsInvalidatingCategoryService struct {
+	CategoryService
+
+	catalog CatalogService
+}
+
+func NewCountsInvalidatingCategoryService(inner CategoryService, catalog CatalogService) CategoryService {
+	return &countsInvalidatingCategoryService{
+		CategoryService: inner,
+		catalog:         catalog,
+	}
+}
+
+func (s *countsInvalidatingCategoryService) Update(ctx context.Context, id string, req *dto.CategoryUpdateRequest) (*models.Category, error) {
+	category, err := s.CategoryService.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		s.catalog.InvalidateCounts(afterCommit(ctx))
+	}
+	return category, nil
+}
+
+func (s *countsInvalidatingCategoryService) Delete(ctx context.Context, id string, reparent bool) error {
+	if err := s.CategoryService.Delete(ctx, id, reparent); err != nil {
+		return err
+	}
+
+	s.catalog.InvalidateCounts(afterCommit(ctx))
+	return nil
+}
+
+// countsInvalidatingSubscriptionService сбрасывает агрегаты категорий после изменения подписок
+type countsInvalidatingSubscriptionService struct {
+	SubscriptionService
+
+	catalog CatalogService
+}
+
+func NewCountsInvalidatingSubscriptionService(inner SubscriptionService, catalog CatalogService) SubscriptionService {
+	return &countsInvalidatingSubscriptionService{
+		SubscriptionService: inner,
+		catalog:             catalog,
+	}
+}
+
+func (s *countsInvalidatingSubscriptionService) Create(ctx context.Context, req *dto.SubscriptionCreateRequest) (*models.Subscription, error) {
+	subscription, err := s.SubscriptionService.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.catalog.InvalidateCounts(afterCommit(ctx))
+	return subscription, nil
+}
+
+func (s *countsInvalidatingSubscriptionService) Update(ctx context.Context, id string, req *dto.SubscriptionUpdateRequest) (*models.Subscription, error) {
+	subscription, err := s.SubscriptionService.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.catalog.InvalidateCounts(afterCommit(ctx))
+	return subscription, nil
+}
+
+func (s *countsInvalidatingSubscriptionService) Delete(ctx context.Context, id string) error {
+	if err := s.SubscriptionService.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.catalog.InvalidateCounts(afterCommit(ctx))
+	return nil
+}
+
+// countsInvalidatingDunningService сбрасывает агрегаты категорий, когда взыскание
+// меняет статус подписок: past_due и cancelled не считаются активными
+type countsInvalidatingDunningService struct {
+	DunningService
+
+	catalog CatalogService
+}
+
+func NewCountsInvalidatingDunningService(inner DunningService, catalog CatalogService) DunningService {
+	return &countsInvalidatingDunningService{
+		DunningService: inner,
+		catalog:        catalog,
+	}
+}
+
+func (s *countsInvalidatingDunningService) PaymentFailed(ctx context.Context, payment *models.Payment) error {
+	defer s.catalog.InvalidateCounts(afterCommit(ctx))
+	return s.DunningService.PaymentFailed(ctx, payment)
+}
+
+func (s *countsInvalidatingDunningService) PaymentSucceeded(ctx context.Context, payment *models.Payment) error {
+	defer s.catalog.InvalidateCounts(afterCommit(ctx))
+	return s.DunningService.PaymentSucceeded(ctx, payment)
+}
+
+func (s *countsInvalidatingDunningService) ProcessDue(ctx context.Context) error {
+	defer s.catalog.InvalidateCounts(afterCommit(ctx))
+	return s.DunningService.ProcessDue(ctx)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// importPlan накапливает изменения каталога по строкам импорта.
+// Категории и сервисы сопоставляются по названию без учёта регистра
+type importPlan struct {
+	categories map[string]models.Category
+	services   map[string]models.Service
+	seen       map[string]int
+
+	newCategories []models.Category
+	created       []models.Service
+	updated       []models.Service
+}
+
+func newImportPlan(categories []models.Category, services []models.Service) *importPlan {
+	p := &importPlan{
+		categories: make(map[string]models.Category, len(categories)),
+		services:   make(map[string]models.Service, len(services)),
+		seen:       map[string]int{},
+	}
+
+	for _, category := range categories {
+		p.categories[catalogKey(category.Name)] = category
+	}
+	for _, svc := range services {
+		p.services[serviceKey(svc.CategoryID, svc.Name)] = svc
+	}
+
+	return p
+}
+
+func (p *importPlan) add(n int, row dto.CatalogRow) dto.CatalogImportRow {
+	row = dto.CatalogRow{
+		Category: strings.TrimSpace(row.Category),
+		Name:     strings.TrimSpace(row.Name),
+		Website:  strings.TrimSpace(row.Website),
+		LogoUrl:  strings.TrimSpace(row.LogoUrl),
+	}
+
+	result := dto.CatalogImportRow{Row: n, Category: row.Category, Name: row.Name}
+	result.Errors = validateCatalogRow(row)
+
+	dupKey := catalogKey(row.Category) + "\x00" + catalogKey(row.Name)
+	if first, ok := p.seen[dupKey]; ok {
+		result.Errors = append(result.Errors, fmt.Sprintf("повторяет строку %d", first))
+	} else {
+		p.seen[dupKey] = n
+	}
+
+	if len(result.Errors) > 0 {
+		return result
+	}
+
+	category, exists := p.categories[catalogKey(row.Category)]
+	if !exists {
+		category = models.Category{Base: models.Base{ID: uuid.New()}, Name: row.Category}
+		p.categories[catalogKey(row.Category)] = category
+		p.newCategories = append(p.newCategories, category)
+	}
+
+	if row.Name == "" {
+		result.Action = dto.ImportSkip
+		if !exists {
+			result.Action = dto.ImportCreate
+		}
+		return result
+	}
+
+	svc, ok := p.services[serviceKey(category.ID, row.Name)]
+	if !ok {
+		p.created = append(p.created, models.Service{
+			Name:       row.Name,
+			CategoryID: category.ID,
+			Website:    row.Website,
+			LogoUrl:    row.LogoUrl,
+		})
+		result.Action = dto.ImportCreate
+		return result
+	}
+
+	// пустые поля в файле не затирают заполненные в каталоге
+	changed := false
+	if row.Website != "" && row.Website != svc.Website {
+		svc.Website = row.Website
+		changed = true
+	}
+	if row.LogoUrl != "" && row.LogoUrl != svc.LogoUrl {
+		svc.LogoUrl = row.LogoUrl
+		changed = true
+	}
+
+	result.Action = dto.ImportSkip
+	if changed {
+		p.updated = append(p.updated, svc)
+		result.Action = dto.ImportUpdate
+	}
+
+	return result
+}
+
+// validateCatalogRow повторяет ограничения запросов создания категории и сервиса
+func validateCatalogRow(row dto.CatalogRow) []string {
+	var errs []string
+
+	if n := utf8.RuneCountInString(row.Category); n < 2 || n > 100 {
+		errs = append(errs, "category: название категории должно содержать от 2 до 100 символов")
+	}
+
+	if row.Name == "" {
+		if row.Website != "" || row.LogoUrl != "" {
+			errs = append(errs, "name: не указано название сервиса")
+		}
+		return errs
+	}
+
+	if n := utf8.RuneCountInString(row.Name); n < 2 || n > 100 {
+		errs = append(errs, "name: название сервиса должно содержать от 2 до 100 символов")
+	}
+	if row.Website != "" && !validHTTPURL(row.Website) {
+		errs = append(errs, "website: некорректный URL")
+	}
+	if row.LogoUrl != "" && !validHTTPURL(row.LogoUrl) {
+		errs = append(errs, "logo_url: некорректный URL")
+	}
+
+	return errs
+}
+
+func validHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && len(raw) <= 255
+}
+
+func catalogKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func serviceKey(categoryID uuid.UUID, name string) string {
+	return categoryID.String() + "\x00" + catalogKey(name)
+}
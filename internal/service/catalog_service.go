@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/cache"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/query"
+	"effective-project/internal/repository"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("категория не найдена")
+	ErrEmptyCatalog     = errors.New("файл импорта не содержит строк")
+	ErrCatalogTooLarge  = errors.New("файл импорта содержит слишком много строк")
+	ErrCatalogInvalid   = errors.New("файл импорта содержит ошибки, изменения не применены")
+)
+
+// MaxCatalogRows ограничивает размер одного импорта
+const MaxCatalogRows = 5000
+
+// Ключ кеша агрегатов в пространстве cache.PrefixCatalog; сбрасывается
+// при изменении категорий, сервисов и подписок
+const categoryCountsKey = "category_counts"
+
+const categoryCountsTTL = 10 * time.Minute
+
+type CatalogService interface {
+	// CategoryServices возвращает сервисы категории с фильтрами и сортировкой запроса;
+	// с subtree — сервисы всего поддерева категории
+	CategoryServices(ctx context.Context, categoryID string, q *query.List, subtree bool) ([]models.Service, error)
+
+	// Tree возвращает дерево категорий; с withCounts узлы дополняются агрегатами
+	Tree(ctx context.Context, withCounts bool) ([]*dto.CategoryNode, error)
+
+	// Breadcrumbs возвращает путь от корня дерева до категории
+	Breadcrumbs(ctx context.Context, categoryID uuid.UUID) ([]dto.CategoryRef, error)
+
+	// WithCounts дополняет категории числом сервисов и активных подписчиков
+	WithCounts(ctx context.Context, categories []models.Category) ([]dto.CategoryWithCounts, error)
+
+	// InvalidateCounts сбрасывает закешированные агрегаты
+	InvalidateCounts(ctx context.Context)
+
+	// Import сопоставляет строки с каталогом по названиям категорий и сервисов.
+	// В режиме dryRun только возвращает отчёт; иначе применяет все строки
+	// в одной транзакции или, при ошибках в строках, ни одной
+	Import(ctx context.Context, rows []dto.CatalogRow, dryRun bool) (*dto.CatalogImportReport, error)
+
+	Export(ctx context.Context) ([]dto.CatalogRow, error)
+}
+
+type catalogService struct {
+	catalogRepo  repository.CatalogRepository
+	categoryRepo repository.CategoryRepository
+	serviceRepo  repository.ServiceRepository
+	cache        cache.Cache[map[string]dto.CategoryCounts]
+	serviceCache cache.Cache[*models.Service]
+	now          func() time.Time
+	logger       *slog.Logger
+}
+
+func NewCatalogService(
+	catalogRepo repository.CatalogRepository,
+	categoryRepo repository.CategoryRepository,
+	serviceRepo repository.ServiceRepository,
+	cache cache.Cache[map[string]dto.CategoryCounts],
+	serviceCache cache.Cache[*models.Service],
+	logger *slog.Logger,
+) CatalogService {
+	return &catalogService{
+		catalogRepo:  catalogRepo,
+		categoryRepo: categoryRepo,
+		serviceRepo:  serviceRepo,
+		cache:        cache,
+		serviceCache: serviceCache,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+func (s *catalogService) CategoryServices(
+	ctx context.Context,
+	categoryID string,
+	q *query.List,
+	subtree bool,
+) ([]models.Service, error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		s.logger.Error("service.catalog.category_services: failed to get category", slog.Any("error", err))
+		return nil, err
+	}
+
+	if subtree {
+		ids, err := s.categoryRepo.SubtreeIDs(ctx, category.ID)
+		if err != nil {
+			s.logger.Error("service.catalog.category_services: failed to get subtree", slog.Any("error", err))
+			return nil, err
+		}
+
+		values := make([]any, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, id)
+		}
+		q.Filters = append(q.Filters, query.Filter{Field: "category_id", Op: query.In, Value: values})
+	} else {
+		q.Filters = append(q.Filters, query.Filter{Field: "category_id", Op: query.Eq, Value: category.ID})
+	}
+
+	services, err := s.serviceRepo.List(ctx, q)
+	if err != nil {
+		s.logger.Error("service.catalog.category_services: failed to get services", slog.Any("error", err))
+		return nil, err
+	}
+
+	return services, nil
+}
+
+func (s *catalogService) Tree(ctx context.Context, withCounts bool) ([]*dto.CategoryNode, error) {
+	categories, err := s.categoryRepo.All(ctx)
+	if err != nil {
+		s.logger.Error("service.catalog.tree: failed to get categories", slog.Any("error", err))
+		return nil, err
+	}
+
+	var counts map[string]dto.CategoryCounts
+	if withCounts {
+		if counts, err = s.categoryCounts(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	nodes := make(map[uuid.UUID]*dto.CategoryNode, len(categories))
+	for _, category := range categories {
+		node := &dto.CategoryNode{
+			ID:       category.ID,
+			Name:     category.Name,
+			ParentID: category.ParentID,
+			Children: []*dto.CategoryNode{},
+		}
+		if withCounts {
+			c := counts[category.ID.String()]
+			node.CategoryCounts = &c
+		}
+		nodes[category.ID] = node
+	}
+
+	// категории уже упорядочены по названию, поэтому дети тоже
+	roots := []*dto.CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return roots, nil
+}
+
+func (s *catalogService) Breadcrumbs(ctx context.Context, categoryID uuid.UUID) ([]dto.CategoryRef, error) {
+	path, err := s.categoryRepo.Ancestors(ctx, categoryID)
+	if err != nil {
+		s.logger.Error("service.catalog.breadcrumbs: failed to get path", slog.Any("error", err))
+		return nil, err
+	}
+
+	refs := make([]dto.CategoryRef, 0, len(path))
+	for _, category := range path {
+		refs = append(refs, dto.CategoryRef{ID: category.ID, Name: category.Name})
+	}
+
+	return refs, nil
+}
+
+func (s *catalogService) WithCounts(
+	ctx context.Context,
+	categories []models.Category,
+) ([]dto.CategoryWithCounts, error) {
+	counts, err := s.categoryCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.CategoryWithCounts, 0, len(categories))
+	for _, category := range categories {
+		result = append(result, dto.CategoryWithCounts{
+			Category:       category,
+			CategoryCounts: counts[category.ID.String()],
+		})
+	}
+
+	return result, nil
+}
+
+func (s *catalogService) InvalidateCounts(ctx context.Context) {
+	if err := s.cache.Delete(ctx, categoryCountsKey); err != nil {
+		s.logger.Warn("service.catalog.invalidate_counts: failed to delete cache", slog.Any("error", err))
+	}
+}
+
+func (s *catalogService) Import(
+	ctx context.Context,
+	rows []dto.CatalogRow,
+	dryRun bool,
+) (*dto.CatalogImportReport, error) {
+	if len(rows) == 0 {
+		return nil, ErrEmptyCatalog
+	}
+	if len(rows) > MaxCatalogRows {
+		return nil, ErrCatalogTooLarge
+	}
+
+	categories, services, err := s.catalogRepo.Snapshot(ctx)
+	if err != nil {
+		s.logger.Error("service.catalog.import: failed to load catalog", slog.Any("error", err))
+		return nil, err
+	}
+
+	plan := newImportPlan(categories, services)
+	report := &dto.CatalogImportReport{DryRun: dryRun, Rows: make([]dto.CatalogImportRow, 0, len(rows))}
+
+	for i, row := range rows {
+		result := plan.add(i+1, row)
+		if len(result.Errors) > 0 {
+			report.Invalid++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	report.CategoriesCreated = len(plan.newCategories)
+	report.ServicesCreated = len(plan.created)
+	report.ServicesUpdated = len(plan.updated)
+
+	if report.Invalid > 0 {
+		return report, ErrCatalogInvalid
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	if err := s.catalogRepo.Import(ctx, plan.newCategories, plan.created, plan.updated); err != nil {
+		s.logger.Error("service.catalog.import: failed to apply import", slog.Any("error", err))
+		return nil, err
+	}
+	report.Applied = true
+
+	tags := []string{servicesTag}
+	for _, svc := range plan.updated {
+		if err := s.serviceCache.Delete(ctx, svc.ID.String()); err != nil {
+			s.logger.Warn("service.catalog.import: failed to delete service cache", slog.Any("error", err))
+		}
+		tags = append(tags, serviceTag(svc.ID.String()))
+	}
+	if err := s.serviceCache.Invalidate(ctx, tags...); err != nil {
+		s.logger.Warn("service.catalog.import: failed to invalidate service cache", slog.Any("error", err))
+	}
+	s.InvalidateCounts(ctx)
+
+	return report, nil
+}
+
+func (s *catalogService) Export(ctx context.Context) ([]dto.CatalogRow, error) {
+	categories, services, err := s.catalogRepo.Snapshot(ctx)
+	if err != nil {
+		s.logger.Error("service.catalog.export: failed to load catalog", slog.Any("error", err))
+		return nil, err
+	}
+
+	byCategory := make(map[uuid.UUID][]models.Service, len(categories))
+	for _, svc := range services {
+		byCategory[svc.CategoryID] = append(byCategory[svc.CategoryID], svc)
+	}
+
+	rows := make([]dto.CatalogRow, 0, len(categories)+len(services))
+	for _, category := range categories {
+		list := byCategory[category.ID]
+		if len(list) == 0 {
+			rows = append(rows, dto.CatalogRow{Category: category.Name})
+			continue
+		}
+		for _, svc := range list {
+			rows = append(rows, dto.CatalogRow{
+				Category: category.Name,
+				Name:     svc.Name,
+				Website:  svc.Website,
+				LogoUrl:  svc.LogoUrl,
+			})
+		}
+	}
+
+	return rows, nil
+}
+
+// categoryCounts считает агрегаты сразу по всем категориям: их немного,
+// а один ключ проще сбрасывать при любом изменении каталога
+func (s *catalogService) categoryCounts(ctx context.Context) (map[string]dto.CategoryCounts, error) {
+	counts, err := s.cache.GetOrLoad(ctx, categoryCountsKey, func(ctx context.Context) (map[string]dto.CategoryCounts, error) {
+		rows, err := s.catalogRepo.CategoryCounts(ctx, s.now())
+		if err != nil {
+			return nil, err
+		}
+
+		counts := make(map[string]dto.CategoryCounts, len(rows))
+		for _, row := range rows {
+			counts[row.CategoryID.String()] = row
+		}
+		return counts, nil
+	}, categoryCountsTTL)
+	if err != nil {
+		s.logger.Error("service.catalog.category_counts: failed to count", slog.Any("error", err))
+		return nil, err
+	}
+
+	return counts, nil
+}
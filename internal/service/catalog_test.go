@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-project/internal/cache"
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+	"effective-project/internal/query"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// memoryCache хранит значения в JSON, как Redis-кеш
+func memoryCache[T any](prefix string) (cache.Cache[T], map[string][]byte) {
+	store := &mock.MockStore{Values: map[string][]byte{}}
+	return cache.New[T](store, prefix, cache.Options{}), store.Values
+}
+
+func TestCatalogService_WithCounts_Cached(t *testing.T) {
+	music := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Музыка"}
+	sport := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Спорт"}
+
+	calls := 0
+	repo := &mock.MockCatalogRepository{
+		CategoryCountsFn: func(ctx context.Context, at time.Time) ([]dto.CategoryCounts, error) {
+			calls++
+			return []dto.CategoryCounts{{CategoryID: music.ID, ServicesCount: 3, ActiveSubscribers: 7}}, nil
+		},
+	}
+	counts, _ := memoryCache[map[string]dto.CategoryCounts]("")
+	svc := NewCatalogService(repo, &mock.MockCategoryRepository{}, &mock.MockServiceRepository{}, counts, &mock.MockCache[*models.Service]{}, newLogger())
+
+	for range 2 {
+		items, err := svc.WithCounts(context.Background(), []models.Category{music, sport})
+
+		assert.NoError(t, err)
+		assert.Len(t, items, 2)
+		assert.Equal(t, 3, items[0].ServicesCount)
+		assert.Equal(t, 7, items[0].ActiveSubscribers)
+		assert.Equal(t, "Спорт", items[1].Name)
+		assert.Zero(t, items[1].ServicesCount)
+	}
+	assert.Equal(t, 1, calls)
+
+	svc.InvalidateCounts(context.Background())
+	_, err := svc.WithCounts(context.Background(), []models.Category{music})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCatalogService_CategoryServices_FiltersByCategory(t *testing.T) {
+	categoryID := uuid.New()
+	categories := &mock.MockCategoryRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Category, error) {
+			return &models.Category{Base: models.Base{ID: categoryID}}, nil
+		},
+	}
+
+	var got *query.List
+	services := &mock.MockServiceRepository{
+		ListFn: func(ctx context.Context, q *query.List) ([]models.Service, error) {
+			got = q
+			return []models.Service{{Name: "Spotify"}}, nil
+		},
+	}
+	svc := NewCatalogService(&mock.MockCatalogRepository{}, categories, services, &mock.MockCache[map[string]dto.CategoryCounts]{}, &mock.MockCache[*models.Service]{}, newLogger())
+
+	list, err := svc.CategoryServices(context.Background(), categoryID.String(), &query.List{Limit: 20}, false)
+
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, []query.Filter{{Field: "category_id", Op: query.Eq, Value: categoryID}}, got.Filters)
+}
+
+func TestCatalogService_CategoryServices_NotFound(t *testing.T) {
+	categories := &mock.MockCategoryRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Category, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	svc := NewCatalogService(&mock.MockCatalogRepository{}, categories, &mock.MockServiceRepository{}, &mock.MockCache[map[string]dto.CategoryCounts]{}, &mock.MockCache[*models.Service]{}, newLogger())
+
+	_, err := svc.CategoryServices(context.Background(), uuid.NewString(), &query.List{Limit: 20}, false)
+
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
+
+func TestCountsInvalidatingServiceService_Create(t *testing.T) {
+	counts, store := memoryCache[map[string]dto.CategoryCounts]("")
+	store[categoryCountsKey] = []byte(`{}`)
+
+	catalog := NewCatalogService(&mock.MockCatalogRepository{}, &mock.MockCategoryRepository{}, &mock.MockServiceRepository{}, counts, &mock.MockCache[*models.Service]{}, newLogger())
+	inner := NewServiceService(&mock.MockServiceRepository{}, &mock.MockCache[*models.Service]{}, &mock.MockCache[[]models.Service]{}, newLogger())
+	svc := NewCountsInvalidatingServiceService(inner, catalog)
+
+	_, err := svc.Create(context.Background(), &dto.ServiceCreateRequest{Name: "Spotify", CategoryID: uuid.New()})
+
+	assert.NoError(t, err)
+	assert.NotContains(t, store, categoryCountsKey)
+}
+
+func TestCatalogService_Import_Applies(t *testing.T) {
+	music := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Музыка"}
+	spotify := models.Service{Base: models.Base{ID: uuid.New()}, Name: "Spotify", CategoryID: music.ID, Website: "https://spotify.com"}
+
+	var gotCategories []models.Category
+	var gotCreated, gotUpdated []models.Service
+	repo := &mock.MockCatalogRepository{
+		SnapshotFn: func(ctx context.Context) ([]models.Category, []models.Service, error) {
+			return []models.Category{music}, []models.Service{spotify}, nil
+		},
+		ImportFn: func(ctx context.Context, categories []models.Category, created []models.Service, updated []models.Service) error {
+			gotCategories, gotCreated, gotUpdated = categories, created, updated
+			return nil
+		},
+	}
+	countsCache, store := memoryCache[map[string]dto.CategoryCounts]("")
+	store[categoryCountsKey] = []byte(`{}`)
+	serviceCache, services := memoryCache[*models.Service]("service:")
+	services["service:"+spotify.ID.String()] = []byte(`{}`)
+
+	svc := NewCatalogService(repo, &mock.MockCategoryRepository{}, &mock.MockServiceRepository{}, countsCache, serviceCache, newLogger())
+
+	report, err := svc.Import(context.Background(), []dto.CatalogRow{
+		{Category: "музыка", Name: "spotify", Website: "https://open.spotify.com"},
+		{Category: "Музыка", Name: "Яндекс Музыка"},
+		{Category: "Кино", Name: "Кинопоиск", Website: "https://kinopoisk.ru"},
+		{Category: "Кино"},
+	}, false)
+
+	assert.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.Equal(t, 1, report.CategoriesCreated)
+	assert.Equal(t, 2, report.ServicesCreated)
+	assert.Equal(t, 1, report.ServicesUpdated)
+	assert.Equal(t, []string{dto.ImportUpdate, dto.ImportCreate, dto.ImportCreate, dto.ImportSkip},
+		[]string{report.Rows[0].Action, report.Rows[1].Action, report.Rows[2].Action, report.Rows[3].Action})
+
+	assert.Len(t, gotCategories, 1)
+	assert.Equal(t, gotCategories[0].ID, gotCreated[1].CategoryID)
+	assert.Equal(t, music.ID, gotCreated[0].CategoryID)
+	assert.Equal(t, "https://open.spotify.com", gotUpdated[0].Website)
+	assert.NotContains(t, store, categoryCountsKey)
+	assert.Empty(t, services)
+}
+
+func TestCatalogService_Import_InvalidRows(t *testing.T) {
+	applied := false
+	repo := &mock.MockCatalogRepository{
+		ImportFn: func(ctx context.Context, categories []models.Category, created []models.Service, updated []models.Service) error {
+			applied = true
+			return nil
+		},
+	}
+	svc := NewCatalogService(repo, &mock.MockCategoryRepository{}, &mock.MockServiceRepository{}, &mock.MockCache[map[string]dto.CategoryCounts]{}, &mock.MockCache[*models.Service]{}, newLogger())
+
+	report, err := svc.Import(context.Background(), []dto.CatalogRow{
+		{Category: "Кино", Name: "Кинопоиск"},
+		{Category: "К", Name: "Okko", Website: "ftp://okko.tv"},
+		{Category: "кино", Name: "кинопоиск"},
+	}, false)
+
+	assert.ErrorIs(t, err, ErrCatalogInvalid)
+	assert.False(t, applied)
+	assert.False(t, report.Applied)
+	assert.Equal(t, 2, report.Invalid)
+	assert.Empty(t, report.Rows[0].Errors)
+	assert.Len(t, report.Rows[1].Errors, 2)
+	assert.Equal(t, []string{"повторяет строку 1"}, report.Rows[2].Errors)
+}
+
+func TestCatalogService_Import_DryRun(t *testing.T) {
+	applied := false
+	repo := &mock.MockCatalogRepository{
+		ImportFn: func(ctx context.Context, categories []models.Category, created []models.Service, updated []models.Service) error {
+			applied = true
+			return nil
+		},
+	}
+	svc := NewCatalogService(repo, &mock.MockCategoryRepository{}, &mock.MockServiceRepository{}, &mock.MockCache[map[string]dto.CategoryCounts]{}, &mock.MockCache[*models.Service]{}, newLogger())
+
+	report, err := svc.Import(context.Background(), []dto.CatalogRow{{Category: "Кино", Name: "Okko"}}, true)
+
+	assert.NoError(t, err)
+	assert.False(t, applied)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.ServicesCreated)
+}
+
+func TestCatalogService_Export(t *testing.T) {
+	music := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Музыка"}
+	cinema := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Кино"}
+	repo := &mock.MockCatalogRepository{
+		SnapshotFn: func(ctx context.Context) ([]models.Category, []models.Service, error) {
+			return []models.Category{cinema, music}, []models.Service{{Name: "Spotify", CategoryID: music.ID, Website: "https://spotify.com"}}, nil
+		},
+	}
+	svc := NewCatalogService(repo, &mock.MockCategoryRepository{}, &mock.MockServiceRepository{}, &mock.MockCache[map[string]dto.CategoryCounts]{}, &mock.MockCache[*models.Service]{}, newLogger())
+
+	rows, err := svc.Export(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.CatalogRow{
+		{Category: "Кино"},
+		{Category: "Музыка", Name: "Spotify", Website: "https://spotify.com"},
+	}, rows)
+}
+
+func TestCatalogService_CategoryServices_Subtree(t *testing.T) {
+	categoryID := uuid.New()
+	childID := uuid.New()
+	categories := &mock.MockCategoryRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Category, error) {
+			return &models.Category{Base: models.Base{ID: categoryID}}, nil
+		},
+		SubtreeIDsFn: func(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+			return []uuid.UUID{categoryID, childID}, nil
+		},
+	}
+
+	var got *query.List
+	services := &mock.MockServiceRepository{
+		ListFn: func(ctx context.Context, q *query.List) ([]models.Service, error) {
+			got = q
+			return nil, nil
+		},
+	}
+	svc := NewCatalogService(&mock.MockCatalogRepository{}, categories, services, &mock.MockCache[map[string]dto.CategoryCounts]{}, &mock.MockCache[*models.Service]{}, newLogger())
+
+	_, err := svc.CategoryServices(context.Background(), categoryID.String(), &query.List{Limit: 20}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []query.Filter{{Field: "category_id", Op: query.In, Value: []any{categoryID, childID}}}, got.Filters)
+}
+
+func TestCatalogService_Tree(t *testing.T) {
+	media := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Медиа"}
+	cinema := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Кино", ParentID: &media.ID}
+	music := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Музыка", ParentID: &media.ID}
+	sport := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Спорт"}
+
+	categories := &mock.MockCategoryRepository{
+		AllFn: func(ctx context.Context) ([]models.Category, error) {
+			return []models.Category{cinema, media, music, sport}, nil
+		},
+	}
+	repo := &mock.MockCatalogRepository{
+		CategoryCountsFn: func(ctx context.Context, at time.Time) ([]dto.CategoryCounts, error) {
+			return []dto.CategoryCounts{
+				{CategoryID: media.ID, TotalServices: 5, TotalSubscribers: 4},
+				{CategoryID: music.ID, ServicesCount: 3, TotalServices: 3, ActiveSubscribers: 4, TotalSubscribers: 4},
+			}, nil
+		},
+	}
+	counts, _ := memoryCache[map[string]dto.CategoryCounts]("")
+	svc := NewCatalogService(repo, categories, &mock.MockServiceRepository{}, counts, &mock.MockCache[*models.Service]{}, newLogger())
+
+	tree, err := svc.Tree(context.Background(), true)
+
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Медиа", tree[0].Name)
+	assert.Equal(t, 5, tree[0].TotalServices)
+	assert.Len(t, tree[0].Children, 2)
+	assert.Equal(t, "Кино", tree[0].Children[0].Name)
+	assert.Equal(t, 3, tree[0].Children[1].ServicesCount)
+	assert.Equal(t, "Спорт", tree[1].Name)
+	assert.Empty(t, tree[1].Children)
+
+	tree, err = svc.Tree(context.Background(), false)
+
+	assert.NoError(t, err)
+	assert.Nil(t, tree[0].CategoryCounts)
+}
+
+func TestCatalogService_Breadcrumbs(t *testing.T) {
+	media := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Медиа"}
+	music := models.Category{Base: models.Base{ID: uuid.New()}, Name: "Музыка", ParentID: &media.ID}
+	categories := &mock.MockCategoryRepository{
+		AncestorsFn: func(ctx context.Context, id uuid.UUID) ([]models.Category, error) {
+			return []models.Category{media, music}, nil
+		},
+	}
+	svc := NewCatalogService(&mock.MockCatalogRepository{}, categories, &mock.MockServiceRepository{}, &mock.MockCache[map[string]dto.CategoryCounts]{}, &mock.MockCache[*models.Service]{}, newLogger())
+
+	path, err := svc.Breadcrumbs(context.Background(), music.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.CategoryRef{{ID: media.ID, Name: "Медиа"}, {ID: music.ID, Name: "Музыка"}}, path)
+}
@@ -6,52 +6,78 @@ import (
 	"effective-project/internal/dto"
 	"effective-project/internal/models"
 	"effective-project/internal/repository"
+	"errors"
 	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrParentCategoryNotFound = errors.New("родительская категория не найдена")
+	ErrCategoryCycle          = errors.New("категорию нельзя вложить в саму себя или в её подкатегорию")
+	ErrCategoryHasChildren    = errors.New("у категории есть подкатегории")
 )
 
 type CategoryService interface {
-	Create(category *dto.CategoryCreateRequest) (*models.Category, error)
+	Create(ctx context.Context, category *dto.CategoryCreateRequest) (*models.Category, error)
 
 	List(ctx context.Context,
 		limit int,
 		lastCreatedAt *time.Time,
 		lastID *uuid.UUID) ([]models.Category, error)
 
-	GetByID(id string) (*models.Category, error)
+	GetByID(ctx context.Context, id string) (*models.Category, error)
 
-	Update(id string, category *dto.CategoryUpdateRequest) (*models.Category, error)
+	Update(ctx context.Context, id string, category *dto.CategoryUpdateRequest) (*models.Category, error)
 
-	Delete(id string) error
+	// Delete удаляет категорию. Категорию с подкатегориями можно удалить
+	// только с reparent: подкатегории переходят к её родителю
+	Delete(ctx context.Context, id string, reparent bool) error
 }
 
+// categoryCacheTTL — срок жизни категории в кеше
+const categoryCacheTTL = 5 * time.Minute
+
 type categoryService struct {
 	categoryRepo  repository.CategoryRepository
-	categoryCache cache.CategoryCache
+	txManager     repository.TxManager
+	categoryCache cache.Cache[*models.Category]
 	logger       *slog.Logger
 }
 
-func NewCategoryService(categoryRepo repository.CategoryRepository, categoryCache cache.CategoryCache, logger *slog.Logger) CategoryService {
+func NewCategoryService(categoryRepo repository.CategoryRepository, txManager repository.TxManager, categoryCache cache.Cache[*models.Category], logger *slog.Logger) CategoryService {
 	return &categoryService{
 		categoryRepo:  categoryRepo,
+		txManager:     txManager,
 		categoryCache: categoryCache,
 		logger:       logger,
 	}
 }
 
-func (s *categoryService) Create(req *dto.CategoryCreateRequest) (*models.Category, error) {
+func (s *categoryService) Create(ctx context.Context, req *dto.CategoryCreateRequest) (*models.Category, error) {
 	category := &models.Category{
-		Name: req.Name,
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	}
+
+	if req.ParentID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, req.ParentID.String()); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrParentCategoryNotFound
+			}
+			s.logger.Error("service.category.create: failed to get parent category", slog.Any("error", err))
+			return nil, err
+		}
 	}
 
-	if err := s.categoryRepo.Create(category); err != nil {
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
 		s.logger.Error("service.category.create: failed to create category", slog.Any("error", err))
 		return nil, err
 	}
 
-	if err := s.categoryCache.Set(context.Background(), category, time.Minute*5); err != nil {
+	if err := s.categoryCache.Set(afterCommit(ctx), category.ID.String(), category, categoryCacheTTL); err != nil {
 		s.logger.Warn("service.category.create: failed to set category cache", slog.Any("error", err))
 	}
 
@@ -71,60 +97,146 @@ func (s *categoryService) List(ctx context.Context,
 	return categories, nil
 }
 
-func (s *categoryService) GetByID(id string) (*models.Category, error) {
-	category, err := s.categoryCache.GetByID(context.Background(), id)
+func (s *categoryService) GetByID(ctx context.Context, id string) (*models.Category, error) {
+	category, err := s.categoryCache.GetOrLoad(ctx, id, func(ctx context.Context) (*models.Category, error) {
+		return s.categoryRepo.GetByID(ctx, id)
+	}, categoryCacheTTL)
 	if err != nil {
-		s.logger.Warn("service.category.get_by_id: failed to get category from cache", slog.Any("error", err))
-	}
-	if category != nil {
-		return category, nil
+		s.logger.Error("service.category.get_by_id: failed to get category", slog.Any("error", err))
+		return nil, err
 	}
 
-	category, err = s.categoryRepo.GetByID(id)
+	return category, nil
+}
+
+func (s *categoryService) Update(ctx context.Context, id string, req *dto.CategoryUpdateRequest) (*models.Category, error) {
+	var category *models.Category
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// без блокировки два встречных переноса прошли бы проверку на цикл
+		// каждый по старому дереву и вместе замкнули бы его
+		if req.ParentID != nil {
+			if err := s.categoryRepo.LockTree(ctx); err != nil {
+				s.logger.Error("service.category.update: failed to lock category tree", slog.Any("error", err))
+				return err
+			}
+		}
+
+		var err error
+		category, err = s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			s.logger.Error("service.category.update: failed to get category", slog.Any("error", err))
+			return err
+		}
+
+		if req.Name != nil {
+			category.Name = *req.Name
+		}
+
+		if req.ParentID != nil {
+			parentID, err := s.resolveParent(ctx, category.ID, *req.ParentID)
+			if err != nil {
+				return err
+			}
+			category.ParentID = parentID
+		}
+
+		if err := s.categoryRepo.Update(ctx, category); err != nil {
+			s.logger.Error("service.category.update: failed to update category", slog.Any("error", err))
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		s.logger.Error("service.category.get_by_id: failed to get category", slog.Any("error", err))
 		return nil, err
 	}
 
-	if err := s.categoryCache.Set(context.Background(), category, time.Minute*5); err != nil {
-		s.logger.Warn("service.category.get_by_id: failed to set category cache", slog.Any("error", err))
+	if err := s.categoryCache.Set(afterCommit(ctx), category.ID.String(), category, categoryCacheTTL); err != nil {
+		s.logger.Warn("service.category.update: failed to update category cache", slog.Any("error", err))
 	}
 
 	return category, nil
 }
 
-func (s *categoryService) Update(id string, req *dto.CategoryUpdateRequest) (*models.Category, error) {
-	category, err := s.categoryRepo.GetByID(id)
+func (s *categoryService) Delete(ctx context.Context, id string, reparent bool) error {
+	categoryID, err := uuid.Parse(id)
 	if err != nil {
-		s.logger.Error("service.category.update: failed to get category", slog.Any("error", err))
-		return nil, err
+		return ErrCategoryNotFound
 	}
 
-	if req.Name != nil {
-		category.Name = *req.Name
+	children, err := s.categoryRepo.Children(ctx, categoryID)
+	if err != nil {
+		s.logger.Error("service.category.delete: failed to get children", slog.Any("error", err))
+		return err
 	}
 
-	if err := s.categoryRepo.Update(category); err != nil {
-		s.logger.Error("service.category.update: failed to update category", slog.Any("error", err))
-		return nil, err
+	if len(children) == 0 {
+		if err := s.categoryRepo.Delete(ctx, id); err != nil {
+			s.logger.Error("service.category.delete: failed to delete category", slog.Any("error", err))
+			return err
+		}
+	} else {
+		if !reparent {
+			return ErrCategoryHasChildren
+		}
+
+		category, err := s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			s.logger.Error("service.category.delete: failed to get category", slog.Any("error", err))
+			return err
+		}
+
+		if err := s.categoryRepo.DeleteAndReparent(ctx, categoryID, category.ParentID); err != nil {
+			s.logger.Error("service.category.delete: failed to delete category", slog.Any("error", err))
+			return err
+		}
+
+		// в кеше подкатегорий остался старый parent_id
+		for _, child := range children {
+			if err := s.categoryCache.Delete(afterCommit(ctx), child.ID.String()); err != nil {
+				s.logger.Warn("service.category.delete: failed to delete child category cache", slog.Any("error", err))
+			}
+		}
 	}
 
-	if err := s.categoryCache.Set(context.Background(), category, time.Minute*5); err != nil {
-		s.logger.Warn("service.category.update: failed to update category cache", slog.Any("error", err))
+	if err := s.categoryCache.Delete(afterCommit(ctx), id); err != nil {
+		s.logger.Warn("service.category.delete: failed to delete category cache", slog.Any("error", err))
 	}
 
-	return category, nil
+	return nil
 }
 
-func (s *categoryService) Delete(id string) error {
-	if err := s.categoryRepo.Delete(id); err != nil {
-		s.logger.Error("service.category.delete: failed to delete category", slog.Any("error", err))
-		return err
+// resolveParent проверяет нового родителя категории: он должен существовать
+// и не лежать в поддереве самой категории. Пустая строка означает корень.
+// Вызывается под LockTree
+func (s *categoryService) resolveParent(ctx context.Context, categoryID uuid.UUID, parent string) (*uuid.UUID, error) {
+	if parent == "" {
+		return nil, nil
 	}
 
-	if err := s.categoryCache.Delete(context.Background(), id); err != nil {
-		s.logger.Warn("service.category.delete: failed to delete category cache", slog.Any("error", err))
+	parentID, err := uuid.Parse(parent)
+	if err != nil {
+		return nil, ErrParentCategoryNotFound
+	}
+	if parentID == categoryID {
+		return nil, ErrCategoryCycle
 	}
 
-	return nil
+	path, err := s.categoryRepo.Ancestors(ctx, parentID)
+	if err != nil {
+		s.logger.Error("service.category.update: failed to get parent path", slog.Any("error", err))
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, ErrParentCategoryNotFound
+	}
+
+	for _, ancestor := range path {
+		if ancestor.ID == categoryID {
+			return nil, ErrCategoryCycle
+		}
+	}
+
+	return &parentID, nil
 }
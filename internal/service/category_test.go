@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	"effective-project/internal/cache"
 	"effective-project/internal/dto"
+	mocks "effective-project/internal/mock"
 	"effective-project/internal/models"
 
 	"github.com/google/uuid"
@@ -20,8 +22,8 @@ import (
 
 type categoryRepoMock struct{ mock.Mock }
 
-func (m *categoryRepoMock) Create(category *models.Category) error {
-	args := m.Called(category)
+func (m *categoryRepoMock) Create(ctx context.Context, category *models.Category) error {
+	args := m.Called(ctx, category)
 	return args.Error(0)
 }
 
@@ -30,44 +32,90 @@ func (m *categoryRepoMock) List(ctx context.Context, limit int, lastCreatedAt *t
 	return args.Get(0).([]models.Category), args.Error(1)
 }
 
-func (m *categoryRepoMock) GetByID(id string) (*models.Category, error) {
-	args := m.Called(id)
+func (m *categoryRepoMock) GetByID(ctx context.Context, id string) (*models.Category, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Category), args.Error(1)
 }
 
-func (m *categoryRepoMock) Update(category *models.Category) error {
-	args := m.Called(category)
+func (m *categoryRepoMock) Update(ctx context.Context, category *models.Category) error {
+	args := m.Called(ctx, category)
 	return args.Error(0)
 }
 
-func (m *categoryRepoMock) Delete(id string) error {
-	args := m.Called(id)
+func (m *categoryRepoMock) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-type categoryCacheMock struct{ mock.Mock }
+func (m *categoryRepoMock) All(ctx context.Context) ([]models.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Category), args.Error(1)
+}
 
-func (m *categoryCacheMock) GetByID(ctx context.Context, id string) (*models.Category, error) {
+func (m *categoryRepoMock) Ancestors(ctx context.Context, id uuid.UUID) ([]models.Category, error) {
 	args := m.Called(ctx, id)
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+
+func (m *categoryRepoMock) SubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *categoryRepoMock) Children(ctx context.Context, id uuid.UUID) ([]models.Category, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]models.Category), args.Error(1)
+}
+
+func (m *categoryRepoMock) DeleteAndReparent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	args := m.Called(ctx, id, parentID)
+	return args.Error(0)
+}
+
+func (m *categoryRepoMock) LockTree(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+type categoryCacheMock struct{ mock.Mock }
+
+func (m *categoryCacheMock) Get(ctx context.Context, key string) (*models.Category, error) {
+	args := m.Called(ctx, key)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Category), args.Error(1)
 }
 
-func (m *categoryCacheMock) Set(ctx context.Context, categories *models.Category, ttl time.Duration) error {
-	args := m.Called(ctx, categories, ttl)
+func (m *categoryCacheMock) Set(ctx context.Context, key string, value *models.Category, ttl time.Duration) error {
+	args := m.Called(ctx, key, value, ttl)
 	return args.Error(0)
 }
 
-func (m *categoryCacheMock) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *categoryCacheMock) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
 	return args.Error(0)
 }
 
+func (m *categoryCacheMock) Invalidate(ctx context.Context, tags ...string) error {
+	args := m.Called(ctx, tags)
+	return args.Error(0)
+}
+
+func (m *categoryCacheMock) GetOrLoad(ctx context.Context, key string, load cache.Loader[*models.Category], ttl time.Duration) (*models.Category, error) {
+	if value, err := m.Get(ctx, key); err == nil {
+		return value, nil
+	}
+	value, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return value, m.Set(ctx, key, value, ttl)
+}
+
 func newLoggerr() *slog.Logger {
 	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
 }
@@ -78,14 +126,14 @@ func TestCategoryService_Create(t *testing.T) {
 	repo := new(categoryRepoMock)
 	cache := new(categoryCacheMock)
 	logger := newLoggerr()
-	service := NewCategoryService(repo, cache, logger)
+	service := NewCategoryService(repo, &mocks.MockTxManager{}, cache, logger)
 
 	req := &dto.CategoryCreateRequest{Name: "Books"}
 
-	repo.On("Create", mock.MatchedBy(func(c *models.Category) bool { return c.Name == req.Name })).Return(nil)
-	cache.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(c *models.Category) bool { return c.Name == req.Name })).Return(nil)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	category, err := service.Create(req)
+	category, err := service.Create(context.Background(), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, req.Name, category.Name)
@@ -98,16 +146,16 @@ func TestCategoryService_GetByID_Success(t *testing.T) {
 	repo := new(categoryRepoMock)
 	cache := new(categoryCacheMock)
 	logger := newLoggerr()
-	service := NewCategoryService(repo, cache, logger)
+	service := NewCategoryService(repo, &mocks.MockTxManager{}, cache, logger)
 
 	id := uuid.New()
 	expected := &models.Category{Base: models.Base{ID: id}, Name: "Electronics"}
 
-	cache.On("GetByID", mock.Anything, id.String()).Return(nil, errors.New("cache miss"))
-	repo.On("GetByID", id.String()).Return(expected, nil)
-	cache.On("Set", mock.Anything, expected, mock.Anything).Return(nil)
+	cache.On("Get", mock.Anything, id.String()).Return(nil, errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, id.String()).Return(expected, nil)
+	cache.On("Set", mock.Anything, id.String(), expected, mock.Anything).Return(nil)
 
-	category, err := service.GetByID(id.String())
+	category, err := service.GetByID(context.Background(), id.String())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, category)
@@ -120,14 +168,14 @@ func TestCategoryService_GetByID_NotFound(t *testing.T) {
 	repo := new(categoryRepoMock)
 	cache := new(categoryCacheMock)
 	logger := newLoggerr()
-	service := NewCategoryService(repo, cache, logger)
+	service := NewCategoryService(repo, &mocks.MockTxManager{}, cache, logger)
 
 	id := uuid.New()
 
-	cache.On("GetByID", mock.Anything, id.String()).Return(nil, errors.New("cache miss"))
-	repo.On("GetByID", id.String()).Return(nil, errors.New("not found"))
+	cache.On("Get", mock.Anything, id.String()).Return(nil, errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, id.String()).Return(nil, errors.New("not found"))
 
-	category, err := service.GetByID(id.String())
+	category, err := service.GetByID(context.Background(), id.String())
 
 	assert.Error(t, err)
 	assert.Nil(t, category)
@@ -140,14 +188,15 @@ func TestCategoryService_Delete(t *testing.T) {
 	repo := new(categoryRepoMock)
 	cache := new(categoryCacheMock)
 	logger := newLoggerr()
-	service := NewCategoryService(repo, cache, logger)
+	service := NewCategoryService(repo, &mocks.MockTxManager{}, cache, logger)
 
 	id := uuid.New()
 
-	repo.On("Delete", id.String()).Return(nil)
+	repo.On("Children", mock.Anything, id).Return([]models.Category{}, nil)
+	repo.On("Delete", mock.Anything, id.String()).Return(nil)
 	cache.On("Delete", mock.Anything, id.String()).Return(nil)
 
-	err := service.Delete(id.String())
+	err := service.Delete(context.Background(), id.String(), false)
 
 	assert.NoError(t, err)
 
@@ -159,7 +208,7 @@ func TestCategoryService_List(t *testing.T) {
 	repo := new(categoryRepoMock)
 	cache := new(categoryCacheMock)
 	logger := newLoggerr()
-	service := NewCategoryService(repo, cache, logger)
+	service := NewCategoryService(repo, &mocks.MockTxManager{}, cache, logger)
 
 	expected := []models.Category{{Base: models.Base{ID: uuid.New()}, Name: "A"}, {Base: models.Base{ID: uuid.New()}, Name: "B"}}
 
@@ -173,3 +222,87 @@ func TestCategoryService_List(t *testing.T) {
 
 	repo.AssertExpectations(t)
 }
+
+func TestCategoryService_Delete_HasChildren(t *testing.T) {
+	repo := new(categoryRepoMock)
+	cache := new(categoryCacheMock)
+	service := NewCategoryService(repo, &mocks.MockTxManager{}, cache, newLoggerr())
+
+	id := uuid.New()
+	repo.On("Children", mock.Anything, id).Return([]models.Category{{Base: models.Base{ID: uuid.New()}}}, nil)
+
+	err := service.Delete(context.Background(), id.String(), false)
+
+	assert.ErrorIs(t, err, ErrCategoryHasChildren)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestCategoryService_Delete_Reparent(t *testing.T) {
+	repo := new(categoryRepoMock)
+	cache := new(categoryCacheMock)
+	service := NewCategoryService(repo, &mocks.MockTxManager{}, cache, newLoggerr())
+
+	parentID := uuid.New()
+	id := uuid.New()
+	childID := uuid.New()
+
+	repo.On("Children", mock.Anything, id).Return([]models.Category{{Base: models.Base{ID: childID}}}, nil)
+	repo.On("GetByID", mock.Anything, id.String()).Return(&models.Category{Base: models.Base{ID: id}, ParentID: &parentID}, nil)
+	repo.On("DeleteAndReparent", mock.Anything, id, &parentID).Return(nil)
+	cache.On("Delete", mock.Anything, childID.String()).Return(nil)
+	cache.On("Delete", mock.Anything, id.String()).Return(nil)
+
+	err := service.Delete(context.Background(), id.String(), true)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestCategoryService_Update_Cycle(t *testing.T) {
+	repo := new(categoryRepoMock)
+	cache := new(categoryCacheMock)
+	service := NewCategoryService(repo, &mocks.MockTxManager{}, cache, newLoggerr())
+
+	id := uuid.New()
+	childID := uuid.New()
+	parent := childID.String()
+
+	repo.On("LockTree", mock.Anything).Return(nil)
+	repo.On("GetByID", mock.Anything, id.String()).Return(&models.Category{Base: models.Base{ID: id}, Name: "Медиа"}, nil)
+	repo.On("Ancestors", mock.Anything, childID).Return([]models.Category{
+		{Base: models.Base{ID: id}},
+		{Base: models.Base{ID: childID}, ParentID: &id},
+	}, nil)
+
+	_, err := service.Update(context.Background(), id.String(), &dto.CategoryUpdateRequest{ParentID: &parent})
+
+	assert.ErrorIs(t, err, ErrCategoryCycle)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	self := id.String()
+	_, err = service.Update(context.Background(), id.String(), &dto.CategoryUpdateRequest{ParentID: &self})
+
+	assert.ErrorIs(t, err, ErrCategoryCycle)
+}
+
+func TestCategoryService_Update_MoveToRoot(t *testing.T) {
+	repo := new(categoryRepoMock)
+	cache := new(categoryCacheMock)
+	service := NewCategoryService(repo, &mocks.MockTxManager{}, cache, newLoggerr())
+
+	id := uuid.New()
+	parentID := uuid.New()
+	root := ""
+
+	repo.On("LockTree", mock.Anything).Return(nil)
+	repo.On("GetByID", mock.Anything, id.String()).Return(&models.Category{Base: models.Base{ID: id}, ParentID: &parentID}, nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(c *models.Category) bool { return c.ParentID == nil })).Return(nil)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	category, err := service.Update(context.Background(), id.String(), &dto.CategoryUpdateRequest{ParentID: &root})
+
+	assert.NoError(t, err)
+	assert.Nil(t, category.ParentID)
+	repo.AssertExpectations(t)
+}
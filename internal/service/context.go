@@ -0,0 +1,10 @@
+package service
+
+import "context"
+
+// afterCommit отвязывает ctx от отмены запроса. Запись уже сохранена, поэтому
+// сброс кеша и побочные действия выполняются, даже если клиент отключился:
+// иначе в кеше до истечения TTL осталась бы старая запись
+func afterCommit(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/repository"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCouponNotFound      = errors.New("купон не найден")
+	ErrInvalidCoupon       = errors.New("некорректные параметры купона")
+	ErrCouponExpired       = errors.New("срок действия купона истёк или ещё не начался")
+	ErrCouponNotApplicable = errors.New("купон не действует на этот сервис")
+	ErrCouponCurrency      = errors.New("валюта купона не совпадает с валютой покупки")
+	ErrCouponExhausted     = errors.New("лимит погашений купона исчерпан")
+	ErrCouponUserLimit     = errors.New("купон уже использован максимальное число раз")
+)
+
+type CouponService interface {
+	Create(ctx context.Context, req *dto.CouponCreateRequest) (*models.Coupon, error)
+
+	List(
+		ctx context.Context,
+		limit int,
+		lastCreatedAt *time.Time,
+		lastID *uuid.UUID,
+	) ([]models.Coupon, error)
+
+	GetByID(ctx context.Context, id string) (*models.Coupon, error)
+
+	Update(ctx context.Context, id string, req *dto.CouponUpdateRequest) (*models.Coupon, error)
+
+	Delete(ctx context.Context, id string) error
+
+	// Apply проверяет купон, фиксирует погашение и возвращает снимок цены со скидкой
+	Apply(ctx context.Context, app dto.CouponApplication) (*dto.PriceSnapshot, error)
+}
+
+type couponService struct {
+	couponRepo   repository.CouponRepository
+	serviceRepo  repository.ServiceRepository
+	categoryRepo repository.CategoryRepository
+	now          func() time.Time
+	logger       *slog.Logger
+}
+
+func NewCouponService(
+	couponRepo repository.CouponRepository,
+	serviceRepo repository.ServiceRepository,
+	categoryRepo repository.CategoryRepository,
+	logger *slog.Logger,
+) CouponService {
+	return &couponService{
+		couponRepo:   couponRepo,
+		serviceRepo:  serviceRepo,
+		categoryRepo: categoryRepo,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+func (s *couponService) Create(ctx context.Context, req *dto.CouponCreateRequest) (*models.Coupon, error) {
+	coupon := &models.Coupon{
+		Code:           normalizeCouponCode(req.Code),
+		DiscountType:   req.DiscountType,
+		PercentOff:     req.PercentOff,
+		AmountOff:      req.AmountOff,
+		Currency:       req.Currency,
+		MaxRedemptions: req.MaxRedemptions,
+		PerUserLimit:   req.PerUserLimit,
+		ValidFrom:      req.ValidFrom,
+		ValidUntil:     req.ValidUntil,
+		ServiceIDs:     req.ServiceIDs,
+		CategoryIDs:    req.CategoryIDs,
+	}
+
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+
+	if err := s.couponRepo.Create(ctx, coupon); err != nil {
+		s.logger.Error("service.coupon.create: failed to create coupon", slog.Any("error", err))
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (s *couponService) List(
+	ctx context.Context,
+	limit int,
+	lastCreatedAt *time.Time,
+	lastID *uuid.UUID,
+) ([]models.Coupon, error) {
+	coupons, err := s.couponRepo.List(ctx, limit, lastCreatedAt, lastID)
+	if err != nil {
+		s.logger.Error("service.coupon.list: failed to get coupons", slog.Any("error", err))
+		return nil, err
+	}
+
+	return coupons, nil
+}
+
+func (s *couponService) GetByID(ctx context.Context, id string) (*models.Coupon, error) {
+	coupon, err := s.couponRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("service.coupon.get_by_id: failed to get coupon", slog.Any("error", err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCouponNotFound
+		}
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (s *couponService) Update(ctx context.Context, id string, req *dto.CouponUpdateRequest) (*models.Coupon, error) {
+	coupon, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PercentOff != nil {
+		coupon.PercentOff = *req.PercentOff
+	}
+	if req.AmountOff != nil {
+		coupon.AmountOff = *req.AmountOff
+	}
+	if req.Currency != nil {
+		coupon.Currency = *req.Currency
+	}
+	if req.MaxRedemptions != nil {
+		coupon.MaxRedemptions = *req.MaxRedemptions
+	}
+	if req.PerUserLimit != nil {
+		coupon.PerUserLimit = *req.PerUserLimit
+	}
+	if req.ValidFrom != nil {
+		coupon.ValidFrom = req.ValidFrom
+	}
+	if req.ValidUntil != nil {
+		coupon.ValidUntil = req.ValidUntil
+	}
+	if req.ServiceIDs != nil {
+		coupon.ServiceIDs = *req.ServiceIDs
+	}
+	if req.CategoryIDs != nil {
+		coupon.CategoryIDs = *req.CategoryIDs
+	}
+
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+
+	if err := s.couponRepo.Update(ctx, coupon); err != nil {
+		s.logger.Error("service.coupon.update: failed to update coupon", slog.Any("error", err))
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (s *couponService) Delete(ctx context.Context, id string) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.couponRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("service.coupon.delete: failed to delete coupon", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *couponService) Apply(ctx context.Context, app dto.CouponApplication) (*dto.PriceSnapshot, error) {
+	coupon, err := s.couponRepo.GetByCode(ctx, normalizeCouponCode(app.Code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCouponNotFound
+		}
+		s.logger.Error("service.coupon.apply: failed to get coupon", slog.Any("error", err))
+		return nil, err
+	}
+
+	now := s.now()
+	if coupon.ValidFrom != nil && now.Before(*coupon.ValidFrom) {
+		return nil, ErrCouponExpired
+	}
+	if coupon.ValidUntil != nil && !now.Before(*coupon.ValidUntil) {
+		return nil, ErrCouponExpired
+	}
+
+	if err := s.checkScope(ctx, coupon, app.ServiceID); err != nil {
+		return nil, err
+	}
+
+	currency := app.Currency
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	discount, err := couponDiscount(coupon, app.Amount, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	redemption := &models.Redemption{
+		CouponID:       coupon.ID,
+		UserID:         app.UserID,
+		SubscriptionID: app.SubscriptionID,
+		OrderID:        app.OrderID,
+		Discount:       discount,
+		Currency:       currency,
+	}
+
+	err = s.couponRepo.Redeem(ctx, redemption, func(total, perUser int64) error {
+		if coupon.MaxRedemptions > 0 && total >= int64(coupon.MaxRedemptions) {
+			return ErrCouponExhausted
+		}
+		if coupon.PerUserLimit > 0 && perUser >= int64(coupon.PerUserLimit) {
+			return ErrCouponUserLimit
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrCouponExhausted) || errors.Is(err, ErrCouponUserLimit) {
+			return nil, err
+		}
+		s.logger.Error("service.coupon.apply: failed to redeem coupon", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &dto.PriceSnapshot{
+		ListPrice:    app.Amount,
+		Discount:     discount,
+		Price:        app.Amount - discount,
+		CouponID:     &coupon.ID,
+		RedemptionID: &redemption.ID,
+	}, nil
+}
+
+// checkScope проверяет ограничение купона по сервисам и категориям. Купон на
+// категорию действует и на сервисы всех её подкатегорий
+func (s *couponService) checkScope(ctx context.Context, coupon *models.Coupon, serviceID uuid.UUID) error {
+	if len(coupon.ServiceIDs) == 0 && len(coupon.CategoryIDs) == 0 {
+		return nil
+	}
+
+	if coupon.ServiceIDs.Contains(serviceID) {
+		return nil
+	}
+
+	if len(coupon.CategoryIDs) > 0 {
+		svc, err := s.serviceRepo.GetByID(ctx, serviceID.String())
+		if err != nil {
+			s.logger.Error("service.coupon.apply: failed to get service", slog.Any("error", err))
+			return err
+		}
+
+		path, err := s.categoryRepo.Ancestors(ctx, svc.CategoryID)
+		if err != nil {
+			s.logger.Error("service.coupon.apply: failed to get category path", slog.Any("error", err))
+			return err
+		}
+		for _, category := range path {
+			if coupon.CategoryIDs.Contains(category.ID) {
+				return nil
+			}
+		}
+	}
+
+	return ErrCouponNotApplicable
+}
+
+// couponDiscount считает скидку; она не может превышать цену покупки
+func couponDiscount(coupon *models.Coupon, amount int, currency string) (int, error) {
+	switch coupon.DiscountType {
+	case models.DiscountPercent:
+		return amount * coupon.PercentOff / 100, nil
+	case models.DiscountFixed:
+		couponCurrency := coupon.Currency
+		if couponCurrency == "" {
+			couponCurrency = models.DefaultCurrency
+		}
+		if !strings.EqualFold(couponCurrency, currency) {
+			return 0, ErrCouponCurrency
+		}
+		return min(coupon.AmountOff, amount), nil
+	default:
+		return 0, ErrInvalidCoupon
+	}
+}
+
+func validateCoupon(coupon *models.Coupon) error {
+	switch coupon.DiscountType {
+	case models.DiscountPercent:
+		if coupon.PercentOff < 1 || coupon.PercentOff > 100 {
+			return ErrInvalidCoupon
+		}
+	case models.DiscountFixed:
+		if coupon.AmountOff <= 0 {
+			return ErrInvalidCoupon
+		}
+	default:
+		return ErrInvalidCoupon
+	}
+
+	if coupon.ValidFrom != nil && coupon.ValidUntil != nil && !coupon.ValidFrom.Before(*coupon.ValidUntil) {
+		return ErrInvalidCoupon
+	}
+
+	return nil
+}
+
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
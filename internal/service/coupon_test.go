@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// txMarker помечает контекст, открытый MockTxManager
+type txMarker struct{}
+
+func markingTxManager() *mock.MockTxManager {
+	return &mock.MockTxManager{
+		WithinTxFn: func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(context.WithValue(ctx, txMarker{}, true))
+		},
+	}
+}
+
+func newTestCouponService(coupon *models.Coupon, repo *mock.MockCouponRepository, services *mock.MockServiceRepository, categories *mock.MockCategoryRepository) *couponService {
+	if repo == nil {
+		repo = &mock.MockCouponRepository{}
+	}
+	repo.GetByCodeFn = func(ctx context.Context, code string) (*models.Coupon, error) {
+		return coupon, nil
+	}
+	if services == nil {
+		services = &mock.MockServiceRepository{}
+	}
+	if categories == nil {
+		// категории без родителей: путь состоит из самой категории
+		categories = &mock.MockCategoryRepository{
+			AncestorsFn: func(ctx context.Context, id uuid.UUID) ([]models.Category, error) {
+				return []models.Category{{Base: models.Base{ID: id}}}, nil
+			},
+		}
+	}
+
+	svc := NewCouponService(repo, services, categories, newLogger()).(*couponService)
+	svc.now = func() time.Time { return time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func TestCouponService_Apply_Percent(t *testing.T) {
+	coupon := &models.Coupon{Base: models.Base{ID: uuid.New()}, Code: "SALE", DiscountType: models.DiscountPercent, PercentOff: 15}
+	svc := newTestCouponService(coupon, nil, nil, nil)
+
+	snapshot, err := svc.Apply(context.Background(), dto.CouponApplication{Code: " sale ", UserID: uuid.New(), ServiceID: uuid.New(), Amount: 999})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 999, snapshot.ListPrice)
+	assert.Equal(t, 149, snapshot.Discount)
+	assert.Equal(t, 850, snapshot.Price)
+	assert.Equal(t, coupon.ID, *snapshot.CouponID)
+	assert.NotNil(t, snapshot.RedemptionID)
+}
+
+func TestCouponService_Apply_FixedCappedByPrice(t *testing.T) {
+	coupon := &models.Coupon{DiscountType: models.DiscountFixed, AmountOff: 500, Currency: "RUB"}
+	svc := newTestCouponService(coupon, nil, nil, nil)
+
+	snapshot, err := svc.Apply(context.Background(), dto.CouponApplication{Code: "GIFT", ServiceID: uuid.New(), Amount: 300, Currency: "RUB"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 300, snapshot.Discount)
+	assert.Equal(t, 0, snapshot.Price)
+
+	_, err = svc.Apply(context.Background(), dto.CouponApplication{Code: "GIFT", ServiceID: uuid.New(), Amount: 300, Currency: "USD"})
+	assert.ErrorIs(t, err, ErrCouponCurrency)
+}
+
+func TestCouponService_Apply_Expired(t *testing.T) {
+	until := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	coupon := &models.Coupon{DiscountType: models.DiscountPercent, PercentOff: 10, ValidUntil: &until}
+	svc := newTestCouponService(coupon, nil, nil, nil)
+
+	_, err := svc.Apply(context.Background(), dto.CouponApplication{Code: "OLD", ServiceID: uuid.New(), Amount: 100})
+
+	assert.ErrorIs(t, err, ErrCouponExpired)
+}
+
+func TestCouponService_Apply_CategoryRestriction(t *testing.T) {
+	categoryID := uuid.New()
+	coupon := &models.Coupon{DiscountType: models.DiscountPercent, PercentOff: 10, CategoryIDs: models.UUIDArray{categoryID}}
+	services := &mock.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return &models.Service{CategoryID: uuid.New()}, nil
+		},
+	}
+	svc := newTestCouponService(coupon, nil, services, nil)
+
+	_, err := svc.Apply(context.Background(), dto.CouponApplication{Code: "MUSIC", ServiceID: uuid.New(), Amount: 100})
+	assert.ErrorIs(t, err, ErrCouponNotApplicable)
+
+	services.GetByIDFn = func(ctx context.Context, id string) (*models.Service, error) {
+		return &models.Service{CategoryID: categoryID}, nil
+	}
+	_, err = svc.Apply(context.Background(), dto.CouponApplication{Code: "MUSIC", ServiceID: uuid.New(), Amount: 100})
+	assert.NoError(t, err)
+}
+
+func TestCouponService_Apply_ParentCategory(t *testing.T) {
+	parentID, childID := uuid.New(), uuid.New()
+	coupon := &models.Coupon{DiscountType: models.DiscountPercent, PercentOff: 10, CategoryIDs: models.UUIDArray{parentID}}
+	services := &mock.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return &models.Service{CategoryID: childID}, nil
+		},
+	}
+	categories := &mock.MockCategoryRepository{
+		AncestorsFn: func(ctx context.Context, id uuid.UUID) ([]models.Category, error) {
+			assert.Equal(t, childID, id)
+			return []models.Category{{Base: models.Base{ID: parentID}}, {Base: models.Base{ID: childID}}}, nil
+		},
+	}
+	svc := newTestCouponService(coupon, nil, services, categories)
+
+	// купон на родительскую категорию действует на сервис подкатегории
+	_, err := svc.Apply(context.Background(), dto.CouponApplication{Code: "MUSIC", ServiceID: uuid.New(), Amount: 100})
+	assert.NoError(t, err)
+}
+
+func TestCouponService_Apply_Limits(t *testing.T) {
+	coupon := &models.Coupon{DiscountType: models.DiscountPercent, PercentOff: 10, MaxRedemptions: 5, PerUserLimit: 1}
+
+	var total, perUser int64
+	repo := &mock.MockCouponRepository{
+		RedeemFn: func(ctx context.Context, redemption *models.Redemption, check func(total, perUser int64) error) error {
+			return check(total, perUser)
+		},
+	}
+	svc := newTestCouponService(coupon, repo, nil, nil)
+	app := dto.CouponApplication{Code: "ONCE", ServiceID: uuid.New(), Amount: 100}
+
+	total, perUser = 2, 1
+	_, err := svc.Apply(context.Background(), app)
+	assert.ErrorIs(t, err, ErrCouponUserLimit)
+
+	total, perUser = 5, 0
+	_, err = svc.Apply(context.Background(), app)
+	assert.ErrorIs(t, err, ErrCouponExhausted)
+}
+
+func TestCouponService_Create_Invalid(t *testing.T) {
+	svc := newTestCouponService(nil, nil, nil, nil)
+
+	_, err := svc.Create(context.Background(), &dto.CouponCreateRequest{Code: "BAD", DiscountType: models.DiscountFixed})
+
+	assert.ErrorIs(t, err, ErrInvalidCoupon)
+}
+
+func TestSubscriptionService_Create_RedeemsCouponInTx(t *testing.T) {
+	coupon := &models.Coupon{Base: models.Base{ID: uuid.New()}, Code: "SALE", DiscountType: models.DiscountPercent, PercentOff: 10}
+	var redeemedInTx, createdInTx bool
+	coupons := newTestCouponService(coupon, &mock.MockCouponRepository{
+		RedeemFn: func(ctx context.Context, redemption *models.Redemption, check func(total, perUser int64) error) error {
+			redeemedInTx = ctx.Value(txMarker{}) != nil
+			return nil
+		},
+	}, nil, nil)
+	subscriptions := &mock.MockSubscriptionRepository{
+		CreateFn: func(ctx context.Context, s *models.Subscription) error {
+			createdInTx = ctx.Value(txMarker{}) != nil
+			return errors.New("insert failed")
+		},
+	}
+	tx := markingTxManager()
+	svc := NewSubscriptionService(subscriptions, nil, nil, tx, coupons, &mock.MockTaxRecorder{}, nil, newLogger())
+
+	_, err := svc.Create(context.Background(), &dto.SubscriptionCreateRequest{UserID: uuid.New(), ServiceID: uuid.New(), Price: 500, CouponCode: "SALE"})
+
+	// ошибка вставки откатывает и погашение купона
+	assert.EqualError(t, err, "insert failed")
+	assert.Equal(t, 1, tx.Calls)
+	assert.True(t, redeemedInTx)
+	assert.True(t, createdInTx)
+}
+
+func TestOrderService_Create_RedeemsCouponInTx(t *testing.T) {
+	coupon := &models.Coupon{Base: models.Base{ID: uuid.New()}, Code: "SALE", DiscountType: models.DiscountPercent, PercentOff: 10}
+	var redemption *models.Redemption
+	coupons := newTestCouponService(coupon, &mock.MockCouponRepository{
+		RedeemFn: func(ctx context.Context, r *models.Redemption, check func(total, perUser int64) error) error {
+			assert.NotNil(t, ctx.Value(txMarker{}))
+			redemption = r
+			return nil
+		},
+	}, nil, nil)
+	orders := &mock.MockOrderRepository{
+		CreateFn: func(ctx context.Context, o *models.Order) error {
+			assert.NotNil(t, ctx.Value(txMarker{}))
+			return nil
+		},
+	}
+	tx := markingTxManager()
+	services := &mock.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return &models.Service{Price: 500}, nil
+		},
+	}
+	svc := NewOrderService(orders, services, tx, &mock.MockCache[*models.Order]{}, coupons, &mock.MockTaxRecorder{}, newLogger())
+
+	order, err := svc.Create(context.Background(), dto.OrderCreateRequest{UserID: uuid.New(), ServiceID: uuid.New(), CouponCode: "SALE"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tx.Calls)
+	assert.Equal(t, 450, order.Price)
+	assert.Equal(t, order.ID, *redemption.OrderID)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"log/slog"
+)
+
+// dunningPaymentService запускает и закрывает взыскание при смене статуса платежа
+type dunningPaymentService struct {
+	PaymentService
+
+	dunning DunningService
+	logger  *slog.Logger
+}
+
+func NewDunningPaymentService(inner PaymentService, dunning DunningService, logger *slog.Logger) PaymentService {
+	return &dunningPaymentService{
+		PaymentService: inner,
+		dunning:        dunning,
+		logger:         logger,
+	}
+}
+
+func (s *dunningPaymentService) Create(ctx context.Context, req *dto.PaymentCreateRequest) (models.Payment, error) {
+	payment, err := s.PaymentService.Create(ctx, req)
+	if err != nil {
+		return payment, err
+	}
+
+	s.handleStatus(ctx, &payment)
+	return payment, nil
+}
+
+func (s *dunningPaymentService) Update(ctx context.Context, id string, req *dto.PaymentUpdateRequest) (*models.Payment, error) {
+	before, err := s.PaymentService.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previous := before.PaymentStatus
+
+	payment, err := s.PaymentService.Update(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.PaymentStatus != previous {
+		s.handleStatus(ctx, payment)
+	}
+	return payment, nil
+}
+
+// handleStatus не откатывает платёж: ошибки взыскания логируются, процесс можно открыть повторно
+func (s *dunningPaymentService) handleStatus(ctx context.Context, payment *models.Payment) {
+	var err error
+
+	switch payment.PaymentStatus {
+	case models.PaymentFailed:
+		err = s.dunning.PaymentFailed(afterCommit(ctx), payment)
+	case models.PaymentSucces:
+		err = s.dunning.PaymentSucceeded(afterCommit(ctx), payment)
+	}
+
+	if err != nil {
+		s.logger.Error("service.dunning_hooks: failed to handle payment status",
+			slog.Any("payment_id", payment.ID),
+			slog.Any("error", err),
+		)
+	}
+}
@@ -0,0 +1,471 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/cache"
+	"effective-project/internal/dto"
+	"effective-project/internal/gateway"
+	"effective-project/internal/models"
+	"effective-project/internal/notification"
+	"effective-project/internal/repository"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidDunningPolicy = errors.New("дни повторных попыток должны строго возрастать")
+
+// Сколько процессов взыскания обрабатывается за один проход воркера
+const dunningBatchSize = 100
+
+// dunningClaimLease — на сколько забранный процесс скрывается от других реплик.
+// Успевшая попытка сама переносит next_retry_at, а после сбоя процесс вернётся по истечении аренды
+const dunningClaimLease = time.Hour
+
+type DunningService interface {
+	GetPolicy(ctx context.Context) (*models.DunningPolicy, error)
+
+	UpdatePolicy(ctx context.Context, req *dto.DunningPolicyRequest) (*models.DunningPolicy, error)
+
+	ListCases(ctx context.Context, status models.DunningStatus, limit int) ([]models.DunningCase, error)
+
+	// PaymentFailed открывает процесс взыскания и переводит подписку в past_due
+	PaymentFailed(ctx context.Context, payment *models.Payment) error
+
+	// PaymentSucceeded закрывает открытый процесс взыскания по подписке
+	PaymentSucceeded(ctx context.Context, payment *models.Payment) error
+
+	// ProcessDue выполняет повторные попытки, время которых подошло
+	ProcessDue(ctx context.Context) error
+}
+
+type dunningService struct {
+	dunningRepo       repository.DunningRepository
+	subscriptionRepo  repository.SubscriptionRepository
+	paymentRepo       repository.PaymentRepository
+	txManager         repository.TxManager
+	gateway           gateway.Gateway
+	notifier          notification.Notifier
+	subscriptionCache cache.Cache[*dto.SubscriptionResponse]
+	now               func() time.Time
+	logger            *slog.Logger
+}
+
+func NewDunningService(
+	dunningRepo repository.DunningRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	paymentRepo repository.PaymentRepository,
+	txManager repository.TxManager,
+	gateway gateway.Gateway,
+	notifier notification.Notifier,
+	subscriptionCache cache.Cache[*dto.SubscriptionResponse],
+	logger *slog.Logger,
+) DunningService {
+	return &dunningService{
+		dunningRepo:       dunningRepo,
+		subscriptionRepo:  subscriptionRepo,
+		paymentRepo:       paymentRepo,
+		txManager:         txManager,
+		gateway:           gateway,
+		notifier:          notifier,
+		subscriptionCache: subscriptionCache,
+		now:               time.Now,
+		logger:            logger,
+	}
+}
+
+func (s *dunningService) GetPolicy(ctx context.Context) (*models.DunningPolicy, error) {
+	policy, err := s.dunningRepo.GetPolicy(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.DunningPolicy{RetryDays: models.DefaultRetryDays}, nil
+	}
+	if err != nil {
+		s.logger.Error("service.dunning.get_policy: failed to get policy", slog.Any("error", err))
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *dunningService) UpdatePolicy(ctx context.Context, req *dto.DunningPolicyRequest) (*models.DunningPolicy, error) {
+	for i := 1; i < len(req.RetryDays); i++ {
+		if req.RetryDays[i] <= req.RetryDays[i-1] {
+			return nil, ErrInvalidDunningPolicy
+		}
+	}
+
+	policy, err := s.GetPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.RetryDays = req.RetryDays
+
+	if err := s.dunningRepo.SavePolicy(ctx, policy); err != nil {
+		s.logger.Error("service.dunning.update_policy: failed to save policy", slog.Any("error", err))
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *dunningService) ListCases(ctx context.Context, status models.DunningStatus, limit int) ([]models.DunningCase, error) {
+	cases, err := s.dunningRepo.ListCases(ctx, status, limit)
+	if err != nil {
+		s.logger.Error("service.dunning.list_cases: failed to get cases", slog.Any("error", err))
+		return nil, err
+	}
+
+	return cases, nil
+}
+
+func (s *dunningService) PaymentFailed(ctx context.Context, payment *models.Payment) error {
+	// повторные попытки создаёт сам процесс взыскания
+	if payment.RetryOfID != nil {
+		return nil
+	}
+
+	_, err := s.dunningRepo.GetOpenCase(ctx, payment.SubscriptionID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("service.dunning.payment_failed: failed to get open case", slog.Any("error", err))
+		return err
+	}
+
+	subscription, err := s.subscriptionRepo.GetModelByID(ctx, payment.SubscriptionID.String())
+	if err != nil {
+		s.logger.Error("service.dunning.payment_failed: failed to get subscription", slog.Any("error", err))
+		return err
+	}
+
+	policy, err := s.GetPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	dunningCase := &models.DunningCase{
+		SubscriptionID: payment.SubscriptionID,
+		OrderID:        payment.OrderID,
+		PaymentID:      payment.ID,
+		LastPaymentID:  payment.ID,
+		Status:         models.DunningOpen,
+		FailedAt:       now,
+	}
+
+	if len(policy.RetryDays) == 0 {
+		err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			return s.cancel(ctx, dunningCase, subscription)
+		})
+		if err != nil {
+			return err
+		}
+
+		s.forgetSubscription(ctx, subscription)
+		s.notifyCancelled(ctx, subscription, dunningCase)
+		return nil
+	}
+
+	next := now.AddDate(0, 0, policy.RetryDays[0])
+	dunningCase.NextRetryAt = &next
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.dunningRepo.CreateCase(ctx, dunningCase); err != nil {
+			return err
+		}
+
+		return s.setSubscriptionStatus(ctx, subscription, models.SubscriptionPastDue, nil)
+	})
+	// параллельный вызов успел открыть процесс между GetOpenCase и CreateCase
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("service.dunning.payment_failed: failed to open case", slog.Any("error", err))
+		return err
+	}
+
+	s.forgetSubscription(ctx, subscription)
+
+	s.notify(ctx, subscription, notification.TypePaymentFailed,
+		"Не удалось списать оплату подписки",
+		fmt.Sprintf("Повторим попытку %s", next.Format("02.01.2006")),
+		dunningCase,
+	)
+
+	return nil
+}
+
+func (s *dunningService) PaymentSucceeded(ctx context.Context, payment *models.Payment) error {
+	dunningCase, err := s.dunningRepo.GetOpenCase(ctx, payment.SubscriptionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("service.dunning.payment_succeeded: failed to get open case", slog.Any("error", err))
+		return err
+	}
+
+	subscription, err := s.subscriptionRepo.GetModelByID(ctx, payment.SubscriptionID.String())
+	if err != nil {
+		s.logger.Error("service.dunning.payment_succeeded: failed to get subscription", slog.Any("error", err))
+		return err
+	}
+
+	dunningCase.LastPaymentID = payment.ID
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return s.recover(ctx, dunningCase, subscription)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.forgetSubscription(ctx, subscription)
+	s.notifyRecovered(ctx, subscription, dunningCase)
+	return nil
+}
+
+func (s *dunningService) ProcessDue(ctx context.Context) error {
+	cases, err := s.dunningRepo.ClaimDue(ctx, s.now().UTC(), dunningClaimLease, dunningBatchSize)
+	if err != nil {
+		s.logger.Error("service.dunning.process_due: failed to claim due cases", slog.Any("error", err))
+		return err
+	}
+
+	var errs []error
+	for i := range cases {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.retry(ctx, &cases[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// retry создаёт новую попытку оплаты того же счёта и двигает процесс по расписанию.
+// Списание у провайдера идёт до транзакции, а попытка, проводки, процесс и статус
+// подписки сохраняются вместе: иначе сбой между ними оставил бы процесс со старым
+// next_retry_at, и следующий проход списал бы деньги повторно
+func (s *dunningService) retry(ctx context.Context, dunningCase *models.DunningCase) error {
+	original, err := s.paymentRepo.GetByID(ctx, dunningCase.PaymentID.String())
+	if err != nil {
+		s.logger.Error("service.dunning.retry: failed to get payment", slog.Any("error", err))
+		return err
+	}
+
+	subscription, err := s.subscriptionRepo.GetModelByID(ctx, dunningCase.SubscriptionID.String())
+	if err != nil {
+		s.logger.Error("service.dunning.retry: failed to get subscription", slog.Any("error", err))
+		return err
+	}
+
+	policy, err := s.GetPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	attempt := &models.Payment{
+		Base:           models.Base{ID: uuid.New()},
+		SubscriptionID: original.SubscriptionID,
+		OrderID:        dunningCase.OrderID,
+		Amount:         original.Amount,
+		Currency:       original.Currency,
+		Provider:       original.Provider,
+		PaidAt:         s.now().UTC(),
+		Attempt:        dunningCase.Retries + 2,
+		RetryOfID:      &original.ID,
+	}
+
+	status, err := s.gateway.Charge(ctx, attempt)
+	if err != nil {
+		s.logger.Warn("service.dunning.retry: charge failed", slog.Any("error", err))
+		status = models.PaymentFailed
+	}
+	attempt.PaymentStatus = status
+
+	var entries []models.LedgerEntry
+	if status == models.PaymentSucces {
+		entries = ChargeEntries(attempt)
+	}
+
+	// уведомление отправляется после коммита и вне контекста транзакции
+	var notifyFn func(context.Context)
+	statusChanged := false
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		locked, err := s.dunningRepo.LockCase(ctx, dunningCase.ID)
+		if err != nil {
+			s.logger.Error("service.dunning.retry: failed to lock case", slog.Any("error", err))
+			return err
+		}
+
+		if err := s.paymentRepo.CreateWithLedger(ctx, attempt, entries); err != nil {
+			s.logger.Error("service.dunning.retry: failed to save payment attempt", slog.Any("error", err))
+			return err
+		}
+
+		// попытка уже списана и сохраняется, но двигать процесс, который успел
+		// обработать другой проход, нельзя
+		if locked.Status != models.DunningOpen || locked.Retries != dunningCase.Retries {
+			s.logger.Warn("service.dunning.retry: case changed concurrently", slog.Any("case_id", dunningCase.ID))
+			return nil
+		}
+
+		dunningCase.Retries++
+		dunningCase.LastPaymentID = attempt.ID
+
+		if status == models.PaymentSucces {
+			notifyFn = func(ctx context.Context) { s.notifyRecovered(ctx, subscription, dunningCase) }
+			statusChanged = true
+			return s.recover(ctx, dunningCase, subscription)
+		}
+
+		if dunningCase.Retries >= len(policy.RetryDays) {
+			notifyFn = func(ctx context.Context) { s.notifyCancelled(ctx, subscription, dunningCase) }
+			statusChanged = true
+			return s.cancel(ctx, dunningCase, subscription)
+		}
+
+		next := dunningCase.FailedAt.AddDate(0, 0, policy.RetryDays[dunningCase.Retries])
+		dunningCase.NextRetryAt = &next
+
+		if err := s.dunningRepo.UpdateCase(ctx, dunningCase); err != nil {
+			s.logger.Error("service.dunning.retry: failed to update case", slog.Any("error", err))
+			return err
+		}
+
+		notifyFn = func(ctx context.Context) {
+			s.notify(ctx, subscription, notification.TypePaymentFailed,
+				"Повторное списание не прошло",
+				fmt.Sprintf("Попытка %d не удалась, следующая — %s", attempt.Attempt, next.Format("02.01.2006")),
+				dunningCase,
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if statusChanged {
+		s.forgetSubscription(ctx, subscription)
+	}
+	if notifyFn != nil {
+		notifyFn(ctx)
+	}
+	return nil
+}
+
+// recover закрывает процесс и возвращает подписку в active; вызывается в транзакции
+func (s *dunningService) recover(ctx context.Context, dunningCase *models.DunningCase, subscription *models.Subscription) error {
+	if err := s.close(ctx, dunningCase, models.DunningRecovered); err != nil {
+		return err
+	}
+
+	return s.setSubscriptionStatus(ctx, subscription, models.SubscriptionActive, nil)
+}
+
+// cancel закрывает процесс и отменяет подписку; вызывается в транзакции
+func (s *dunningService) cancel(ctx context.Context, dunningCase *models.DunningCase, subscription *models.Subscription) error {
+	if dunningCase.ID == uuid.Nil {
+		dunningCase.Status = models.DunningCancelled
+		now := s.now().UTC()
+		dunningCase.ClosedAt = &now
+
+		if err := s.dunningRepo.CreateCase(ctx, dunningCase); err != nil {
+			s.logger.Error("service.dunning.cancel: failed to create case", slog.Any("error", err))
+			return err
+		}
+	} else if err := s.close(ctx, dunningCase, models.DunningCancelled); err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	return s.setSubscriptionStatus(ctx, subscription, models.SubscriptionCancelled, &now)
+}
+
+func (s *dunningService) notifyRecovered(ctx context.Context, subscription *models.Subscription, dunningCase *models.DunningCase) {
+	s.notify(ctx, subscription, notification.TypePaymentRecovered,
+		"Оплата подписки прошла",
+		"Подписка снова активна",
+		dunningCase,
+	)
+}
+
+func (s *dunningService) notifyCancelled(ctx context.Context, subscription *models.Subscription, dunningCase *models.DunningCase) {
+	s.notify(ctx, subscription, notification.TypeSubscriptionCancelled,
+		"Подписка отменена",
+		"Все попытки списания оплаты не удались",
+		dunningCase,
+	)
+}
+
+func (s *dunningService) close(ctx context.Context, dunningCase *models.DunningCase, status models.DunningStatus) error {
+	now := s.now().UTC()
+	dunningCase.Status = status
+	dunningCase.NextRetryAt = nil
+	dunningCase.ClosedAt = &now
+
+	if err := s.dunningRepo.UpdateCase(ctx, dunningCase); err != nil {
+		s.logger.Error("service.dunning.close: failed to update case", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// setSubscriptionStatus меняет статус и, если передана, дату окончания подписки;
+// в базу пишутся только эти колонки
+func (s *dunningService) setSubscriptionStatus(ctx context.Context, subscription *models.Subscription, status models.SubscriptionStatus, endDate *time.Time) error {
+	subscription.Status = status
+	if endDate != nil {
+		subscription.EndDate = endDate
+	}
+
+	if err := s.subscriptionRepo.UpdateStatus(ctx, subscription.ID, status, endDate); err != nil {
+		s.logger.Error("service.dunning: failed to update subscription status", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// forgetSubscription выбрасывает подписку из кеша после коммита, иначе чтение
+// по ID отдавало бы старый статус до истечения TTL
+func (s *dunningService) forgetSubscription(ctx context.Context, subscription *models.Subscription) {
+	if err := s.subscriptionCache.Delete(afterCommit(ctx), subscription.ID.String()); err != nil {
+		s.logger.Warn("service.dunning: failed to delete subscription cache", slog.Any("error", err))
+	}
+}
+
+// notify не прерывает взыскание: ошибка доставки только логируется
+func (s *dunningService) notify(
+	ctx context.Context,
+	subscription *models.Subscription,
+	kind notification.Type,
+	title, message string,
+	dunningCase *models.DunningCase,
+) {
+	if err := s.notifier.Notify(ctx, notification.Notification{
+		UserID:  subscription.UserID,
+		Type:    kind,
+		Title:   title,
+		Message: message,
+		Data: map[string]any{
+			"subscription_id": subscription.ID,
+			"dunning_case_id": dunningCase.ID,
+			"payment_id":      dunningCase.LastPaymentID,
+			"retries":         dunningCase.Retries,
+		},
+	}); err != nil {
+		s.logger.Warn("service.dunning: failed to send notification", slog.Any("error", err))
+	}
+}
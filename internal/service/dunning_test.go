@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+	"effective-project/internal/notification"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type dunningFixture struct {
+	svc          *dunningService
+	subscription *models.Subscription
+	payment      *models.Payment
+	repo         *mock.MockSubscriptionRepository
+	dunning      *mock.MockDunningRepository
+	tx           *mock.MockTxManager
+	gateway      *mock.MockGateway
+	notifier     *mock.MockNotifier
+	created      []*models.DunningCase
+	updated      []*models.DunningCase
+	attempts     []*models.Payment
+	stored       map[uuid.UUID]models.DunningCase
+	evicted      []string
+}
+
+// store кладёт копию процесса в «базу», откуда её читает LockCase
+func (f *dunningFixture) store(c *models.DunningCase) *models.DunningCase {
+	f.stored[c.ID] = *c
+	return c
+}
+
+func newDunningFixture(now time.Time) *dunningFixture {
+	f := &dunningFixture{
+		subscription: &models.Subscription{Base: models.Base{ID: uuid.New()}, UserID: uuid.New(), Status: models.SubscriptionActive},
+		tx:           &mock.MockTxManager{},
+		gateway:      &mock.MockGateway{},
+		notifier:     &mock.MockNotifier{},
+		stored:       map[uuid.UUID]models.DunningCase{},
+	}
+	f.payment = &models.Payment{
+		Base:           models.Base{ID: uuid.New()},
+		SubscriptionID: f.subscription.ID,
+		OrderID:        uuid.New(),
+		Amount:         300,
+		Currency:       "RUB",
+		PaymentStatus:  models.PaymentFailed,
+		Provider:       "stripe",
+		Attempt:        1,
+	}
+
+	f.dunning = &mock.MockDunningRepository{
+		CreateCaseFn: func(ctx context.Context, c *models.DunningCase) error {
+			f.created = append(f.created, c)
+			return nil
+		},
+		UpdateCaseFn: func(ctx context.Context, c *models.DunningCase) error {
+			f.updated = append(f.updated, c)
+			return nil
+		},
+		LockCaseFn: func(ctx context.Context, id uuid.UUID) (*models.DunningCase, error) {
+			c, ok := f.stored[id]
+			if !ok {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return &c, nil
+		},
+	}
+	f.repo = &mock.MockSubscriptionRepository{
+		GetModelByIDFn: func(ctx context.Context, id string) (*models.Subscription, error) {
+			return f.subscription, nil
+		},
+	}
+	payments := &mock.MockPaymentRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Payment, error) {
+			return f.payment, nil
+		},
+		CreateWithLedgerFn: func(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error {
+			f.attempts = append(f.attempts, payment)
+			return nil
+		},
+	}
+
+	subscriptions := &mock.MockCache[*dto.SubscriptionResponse]{
+		DeleteFn: func(ctx context.Context, key string) error {
+			f.evicted = append(f.evicted, key)
+			return nil
+		},
+	}
+
+	f.svc = NewDunningService(f.dunning, f.repo, payments, f.tx, f.gateway, f.notifier, subscriptions, newLogger()).(*dunningService)
+	f.svc.now = func() time.Time { return now }
+	return f
+}
+
+func TestDunningService_PaymentFailed_OpensCase(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	f := newDunningFixture(now)
+
+	err := f.svc.PaymentFailed(context.Background(), f.payment)
+
+	assert.NoError(t, err)
+	assert.Len(t, f.created, 1)
+	assert.Equal(t, now.AddDate(0, 0, 1), *f.created[0].NextRetryAt)
+	assert.Equal(t, models.SubscriptionPastDue, f.subscription.Status)
+	assert.Equal(t, []string{f.subscription.ID.String()}, f.evicted)
+	assert.Equal(t, notification.TypePaymentFailed, f.notifier.Sent[0].Type)
+}
+
+func TestDunningService_PaymentFailed_SubscriptionUpdateFails(t *testing.T) {
+	f := newDunningFixture(time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC))
+	f.repo.UpdateStatusFn = func(ctx context.Context, id uuid.UUID, status models.SubscriptionStatus, endDate *time.Time) error {
+		return errors.New("update failed")
+	}
+
+	err := f.svc.PaymentFailed(context.Background(), f.payment)
+
+	// процесс и статус подписки пишутся в одной транзакции, уведомления нет
+	assert.EqualError(t, err, "update failed")
+	assert.Equal(t, 1, f.tx.Calls)
+	assert.Empty(t, f.evicted)
+	assert.Empty(t, f.notifier.Sent)
+}
+
+func TestDunningService_PaymentFailed_CaseAlreadyOpen(t *testing.T) {
+	f := newDunningFixture(time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC))
+	f.dunning.CreateCaseFn = func(ctx context.Context, c *models.DunningCase) error {
+		return gorm.ErrDuplicatedKey
+	}
+
+	err := f.svc.PaymentFailed(context.Background(), f.payment)
+
+	// процесс открыл параллельный вызов: второй ничего не меняет и не уведомляет
+	assert.NoError(t, err)
+	assert.Equal(t, models.SubscriptionActive, f.subscription.Status)
+	assert.Empty(t, f.evicted)
+	assert.Empty(t, f.notifier.Sent)
+}
+
+func TestDunningService_ProcessDue_ClaimsCases(t *testing.T) {
+	failedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	f := newDunningFixture(failedAt.AddDate(0, 0, 1))
+
+	var lease time.Duration
+	f.dunning.ClaimDueFn = func(ctx context.Context, at time.Time, l time.Duration, limit int) ([]models.DunningCase, error) {
+		lease = l
+		return []models.DunningCase{*f.store(&models.DunningCase{Base: models.Base{ID: uuid.New()}, SubscriptionID: f.subscription.ID, PaymentID: f.payment.ID, FailedAt: failedAt, Status: models.DunningOpen})}, nil
+	}
+
+	err := f.svc.ProcessDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, dunningClaimLease, lease)
+	assert.Len(t, f.attempts, 1)
+	assert.Len(t, f.updated, 1)
+}
+
+func TestDunningService_Retry_SchedulesNext(t *testing.T) {
+	failedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	f := newDunningFixture(failedAt.AddDate(0, 0, 1))
+
+	dunningCase := f.store(&models.DunningCase{Base: models.Base{ID: uuid.New()}, SubscriptionID: f.subscription.ID, OrderID: f.payment.OrderID, PaymentID: f.payment.ID, FailedAt: failedAt, Status: models.DunningOpen})
+	err := f.svc.retry(context.Background(), dunningCase)
+
+	assert.NoError(t, err)
+	assert.Len(t, f.attempts, 1)
+	assert.Equal(t, 2, f.attempts[0].Attempt)
+	assert.Equal(t, f.payment.OrderID, f.attempts[0].OrderID)
+	assert.Equal(t, f.payment.ID, *f.attempts[0].RetryOfID)
+	assert.Equal(t, 1, dunningCase.Retries)
+	assert.Equal(t, failedAt.AddDate(0, 0, 3), *dunningCase.NextRetryAt)
+}
+
+func TestDunningService_Retry_UpdateCaseFails(t *testing.T) {
+	failedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	f := newDunningFixture(failedAt.AddDate(0, 0, 1))
+	f.dunning.UpdateCaseFn = func(ctx context.Context, c *models.DunningCase) error {
+		return errors.New("update failed")
+	}
+
+	dunningCase := f.store(&models.DunningCase{Base: models.Base{ID: uuid.New()}, SubscriptionID: f.subscription.ID, PaymentID: f.payment.ID, FailedAt: failedAt, Status: models.DunningOpen})
+	err := f.svc.retry(context.Background(), dunningCase)
+
+	// попытка и процесс пишутся в одной транзакции: её откат не оставит
+	// сохранённую попытку без сдвига расписания
+	assert.EqualError(t, err, "update failed")
+	assert.Equal(t, 1, f.tx.Calls)
+	assert.Empty(t, f.evicted)
+	assert.Empty(t, f.notifier.Sent)
+}
+
+func TestDunningService_Retry_SkipsConcurrentlyClosedCase(t *testing.T) {
+	failedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	f := newDunningFixture(failedAt.AddDate(0, 0, 1))
+
+	dunningCase := &models.DunningCase{Base: models.Base{ID: uuid.New()}, SubscriptionID: f.subscription.ID, PaymentID: f.payment.ID, FailedAt: failedAt, Status: models.DunningOpen}
+	f.store(&models.DunningCase{Base: dunningCase.Base, Status: models.DunningRecovered})
+	err := f.svc.retry(context.Background(), dunningCase)
+
+	assert.NoError(t, err)
+	assert.Len(t, f.attempts, 1)
+	assert.Empty(t, f.updated)
+	assert.Equal(t, models.SubscriptionActive, f.subscription.Status)
+	assert.Empty(t, f.notifier.Sent)
+}
+
+func TestDunningService_Retry_CancelsAfterLastAttempt(t *testing.T) {
+	failedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	f := newDunningFixture(failedAt.AddDate(0, 0, 7))
+	f.subscription.Status = models.SubscriptionPastDue
+
+	dunningCase := f.store(&models.DunningCase{Base: models.Base{ID: uuid.New()}, SubscriptionID: f.subscription.ID, PaymentID: f.payment.ID, FailedAt: failedAt, Retries: 2, Status: models.DunningOpen})
+	err := f.svc.retry(context.Background(), dunningCase)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.DunningCancelled, dunningCase.Status)
+	assert.Equal(t, models.SubscriptionCancelled, f.subscription.Status)
+	assert.NotNil(t, f.subscription.EndDate)
+	assert.Equal(t, notification.TypeSubscriptionCancelled, f.notifier.Sent[len(f.notifier.Sent)-1].Type)
+}
+
+func TestDunningService_Retry_Recovers(t *testing.T) {
+	failedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	f := newDunningFixture(failedAt.AddDate(0, 0, 3))
+	f.subscription.Status = models.SubscriptionPastDue
+	f.gateway.ChargeFn = func(ctx context.Context, payment *models.Payment) (models.PaymentStatus, error) {
+		return models.PaymentSucces, nil
+	}
+
+	dunningCase := f.store(&models.DunningCase{Base: models.Base{ID: uuid.New()}, SubscriptionID: f.subscription.ID, PaymentID: f.payment.ID, FailedAt: failedAt, Retries: 1, Status: models.DunningOpen})
+	err := f.svc.retry(context.Background(), dunningCase)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.PaymentSucces, f.attempts[0].PaymentStatus)
+	assert.Equal(t, models.DunningRecovered, dunningCase.Status)
+	assert.Equal(t, models.SubscriptionActive, f.subscription.Status)
+	assert.Equal(t, []string{f.subscription.ID.String()}, f.evicted)
+}
+
+func TestDunningService_UpdatePolicy_RequiresAscendingDays(t *testing.T) {
+	f := newDunningFixture(time.Now())
+
+	_, err := f.svc.UpdatePolicy(context.Background(), &dto.DunningPolicyRequest{RetryDays: []int{3, 1}})
+
+	assert.ErrorIs(t, err, ErrInvalidDunningPolicy)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/models"
+	"effective-project/internal/repository"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxFavorites ограничивает избранное пользователя: список используется
+// как фильтр ?favorite=true
+const MaxFavorites = 200
+
+var ErrTooManyFavorites = errors.New("в избранном слишком много сервисов")
+
+type FavoriteService interface {
+	Add(ctx context.Context, userID uuid.UUID, serviceID string) error
+
+	Remove(ctx context.Context, userID uuid.UUID, serviceID string) error
+
+	// ServiceIDs возвращает id избранных сервисов, добавленные последними первыми
+	ServiceIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type favoriteService struct {
+	favoriteRepo repository.FavoriteRepository
+	serviceRepo  repository.ServiceRepository
+	logger       *slog.Logger
+}
+
+func NewFavoriteService(
+	favoriteRepo repository.FavoriteRepository,
+	serviceRepo repository.ServiceRepository,
+	logger *slog.Logger,
+) FavoriteService {
+	return &favoriteService{
+		favoriteRepo: favoriteRepo,
+		serviceRepo:  serviceRepo,
+		logger:       logger,
+	}
+}
+
+func (s *favoriteService) Add(ctx context.Context, userID uuid.UUID, serviceID string) error {
+	svc, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrServiceNotFound
+		}
+		s.logger.Error("service.favorite.add: failed to get service", slog.Any("error", err))
+		return err
+	}
+
+	count, err := s.favoriteRepo.Count(ctx, userID)
+	if err != nil {
+		s.logger.Error("service.favorite.add: failed to count favorites", slog.Any("error", err))
+		return err
+	}
+	if count >= MaxFavorites {
+		return ErrTooManyFavorites
+	}
+
+	if err := s.favoriteRepo.Add(ctx, &models.Favorite{UserID: userID, ServiceID: svc.ID}); err != nil {
+		s.logger.Error("service.favorite.add: failed to add favorite", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *favoriteService) Remove(ctx context.Context, userID uuid.UUID, serviceID string) error {
+	id, err := uuid.Parse(serviceID)
+	if err != nil {
+		return ErrServiceNotFound
+	}
+
+	if err := s.favoriteRepo.Remove(ctx, userID, id); err != nil {
+		s.logger.Error("service.favorite.remove: failed to remove favorite", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *favoriteService) ServiceIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := s.favoriteRepo.ServiceIDs(ctx, userID)
+	if err != nil {
+		s.logger.Error("service.favorite.service_ids: failed to get favorites", slog.Any("error", err))
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"effective-project/internal/cache"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+	"effective-project/internal/query"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestFavoriteService_Add(t *testing.T) {
+	userID := uuid.New()
+	serviceID := uuid.New()
+
+	var added *models.Favorite
+	favorites := &mock.MockFavoriteRepository{
+		AddFn: func(ctx context.Context, favorite *models.Favorite) error {
+			added = favorite
+			return nil
+		},
+	}
+	services := &mock.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return &models.Service{Base: models.Base{ID: serviceID}}, nil
+		},
+	}
+	svc := NewFavoriteService(favorites, services, newLogger())
+
+	err := svc.Add(context.Background(), userID, serviceID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Favorite{UserID: userID, ServiceID: serviceID}, added)
+}
+
+func TestFavoriteService_Add_Errors(t *testing.T) {
+	services := &mock.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	svc := NewFavoriteService(&mock.MockFavoriteRepository{}, services, newLogger())
+
+	err := svc.Add(context.Background(), uuid.New(), uuid.NewString())
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+
+	full := &mock.MockFavoriteRepository{
+		CountFn: func(ctx context.Context, userID uuid.UUID) (int64, error) {
+			return MaxFavorites, nil
+		},
+	}
+	services.GetByIDFn = func(ctx context.Context, id string) (*models.Service, error) {
+		return &models.Service{Base: models.Base{ID: uuid.New()}}, nil
+	}
+	svc = NewFavoriteService(full, services, newLogger())
+
+	err = svc.Add(context.Background(), uuid.New(), uuid.NewString())
+	assert.ErrorIs(t, err, ErrTooManyFavorites)
+}
+
+func TestRecentService_ListKeepsViewOrder(t *testing.T) {
+	userID := uuid.New()
+	first, second, deleted := uuid.New(), uuid.New(), uuid.New()
+
+	recent := &mock.MockRecentlyViewed{}
+	var got *query.List
+	services := &mock.MockServiceRepository{
+		ListFn: func(ctx context.Context, q *query.List) ([]models.Service, error) {
+			got = q
+			return []models.Service{
+				{Base: models.Base{ID: first}, Name: "Okko"},
+				{Base: models.Base{ID: second}, Name: "Spotify"},
+			}, nil
+		},
+	}
+	svc := NewRecentService(recent, services, newLogger()).(*recentService)
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, id := range []uuid.UUID{first, deleted, second, first} {
+		svc.now = func() time.Time { return start.Add(time.Duration(i) * time.Minute) }
+		svc.Track(context.Background(), userID, id)
+	}
+
+	items, err := svc.List(context.Background(), userID, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []query.Filter{{Field: "id", Op: query.In, Value: []any{first, second, deleted}}}, got.Filters)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "Okko", items[0].Name)
+	assert.Equal(t, start.Add(3*time.Minute), items[0].ViewedAt)
+	assert.Equal(t, "Spotify", items[1].Name)
+}
+
+func TestRecentService_TrackIgnoresStoreErrors(t *testing.T) {
+	recent := &mock.MockRecentlyViewed{Err: errors.New("redis down")}
+	svc := NewRecentService(recent, &mock.MockServiceRepository{}, newLogger())
+
+	assert.NotPanics(t, func() {
+		svc.Track(context.Background(), uuid.New(), uuid.New())
+	})
+}
+
+func TestRecentService_ListDegradesOnStoreErrors(t *testing.T) {
+	recent := &mock.MockRecentlyViewed{Err: cache.ErrCircuitOpen}
+	svc := NewRecentService(recent, &mock.MockServiceRepository{}, newLogger())
+
+	items, err := svc.List(context.Background(), uuid.New(), 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/repository"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaymentNotFound      = errors.New("платёж не найден")
+	ErrPaymentImmutable     = errors.New("сумму, валюту и статус успешного платежа менять нельзя, используйте возврат или корректировку")
+	ErrPaymentNotSucceeded  = errors.New("операция доступна только для успешного платежа")
+	ErrRefundExceedsPayment = errors.New("сумма возвратов превышает сумму платежа")
+	ErrUnbalancedEntries    = errors.New("дебет проводок не совпадает с кредитом")
+)
+
+type LedgerService interface {
+	Refund(ctx context.Context, paymentID string, req *dto.RefundRequest) ([]models.LedgerEntry, error)
+
+	Adjust(ctx context.Context, paymentID string, req *dto.AdjustmentRequest) ([]models.LedgerEntry, error)
+
+	Entries(ctx context.Context, paymentID string) ([]models.LedgerEntry, error)
+
+	// Reconcile сверяет остатки журнала с платежами
+	Reconcile(ctx context.Context) (*dto.ReconciliationReport, error)
+}
+
+type ledgerService struct {
+	ledgerRepo  repository.LedgerRepository
+	paymentRepo repository.PaymentRepository
+	now         func() time.Time
+	logger      *slog.Logger
+}
+
+func NewLedgerService(
+	ledgerRepo repository.LedgerRepository,
+	paymentRepo repository.PaymentRepository,
+	logger *slog.Logger,
+) LedgerService {
+	return &ledgerService{
+		ledgerRepo:  ledgerRepo,
+		paymentRepo: paymentRepo,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+func (s *ledgerService) Refund(ctx context.Context, paymentID string, req *dto.RefundRequest) ([]models.LedgerEntry, error) {
+	payment, err := s.succeededPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := refundEntries(payment, req.Amount, req.Reason)
+	if err := checkBalanced(entries); err != nil {
+		return nil, err
+	}
+
+	err = s.ledgerRepo.Post(ctx, payment.ID, entries, func(summary dto.PaymentLedger) error {
+		if summary.Refunded+req.Amount > summary.Charged {
+			return ErrRefundExceedsPayment
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrRefundExceedsPayment) {
+			return nil, err
+		}
+		s.logger.Error("service.ledger.refund: failed to post refund", slog.Any("error", err))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *ledgerService) Adjust(ctx context.Context, paymentID string, req *dto.AdjustmentRequest) ([]models.LedgerEntry, error) {
+	payment, err := s.succeededPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := adjustmentEntries(payment, req.Amount, req.Reason)
+	if err := checkBalanced(entries); err != nil {
+		return nil, err
+	}
+
+	err = s.ledgerRepo.Post(ctx, payment.ID, entries, func(summary dto.PaymentLedger) error {
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("service.ledger.adjust: failed to post adjustment", slog.Any("error", err))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *ledgerService) Entries(ctx context.Context, paymentID string) ([]models.LedgerEntry, error) {
+	payment, err := s.getPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.ledgerRepo.ListByPayment(ctx, payment.ID)
+	if err != nil {
+		s.logger.Error("service.ledger.entries: failed to get entries", slog.Any("error", err))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *ledgerService) Reconcile(ctx context.Context) (*dto.ReconciliationReport, error) {
+	balances, err := s.ledgerRepo.AccountBalances(ctx)
+	if err != nil {
+		s.logger.Error("service.ledger.reconcile: failed to get balances", slog.Any("error", err))
+		return nil, err
+	}
+
+	mismatches, err := s.ledgerRepo.PaymentMismatches(ctx)
+	if err != nil {
+		s.logger.Error("service.ledger.reconcile: failed to get mismatches", slog.Any("error", err))
+		return nil, err
+	}
+
+	if balances == nil {
+		balances = []dto.AccountBalance{}
+	}
+	if mismatches == nil {
+		mismatches = []dto.PaymentMismatch{}
+	}
+
+	return &dto.ReconciliationReport{
+		CheckedAt:  s.now().UTC(),
+		Balanced:   ledgerBalanced(balances) && len(mismatches) == 0,
+		Accounts:   balances,
+		Mismatches: mismatches,
+	}, nil
+}
+
+func (s *ledgerService) getPayment(ctx context.Context, id string) (*models.Payment, error) {
+	payment, err := s.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		s.logger.Error("service.ledger: failed to get payment", slog.Any("error", err))
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (s *ledgerService) succeededPayment(ctx context.Context, id string) (*models.Payment, error) {
+	payment, err := s.getPayment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.PaymentStatus != models.PaymentSucces {
+		return nil, ErrPaymentNotSucceeded
+	}
+
+	return payment, nil
+}
+
+// ChargeEntries: начисление выручки по дебиторской задолженности пользователя
+// и погашение задолженности деньгами, поступившими от провайдера.
+// Экспортирована для seed: засеянные успешные платежи проводятся так же
+func ChargeEntries(payment *models.Payment) []models.LedgerEntry {
+	tx := uuid.New()
+	return []models.LedgerEntry{
+		ledgerEntry(tx, payment, models.LedgerCharge, models.AccountUserReceivable, payment.Amount, 0, "начисление по платежу"),
+		ledgerEntry(tx, payment, models.LedgerCharge, models.AccountRevenue, 0, payment.Amount, "начисление по платежу"),
+		ledgerEntry(tx, payment, models.LedgerCharge, models.AccountProviderClearing, payment.Amount, 0, "поступление от провайдера"),
+		ledgerEntry(tx, payment, models.LedgerCharge, models.AccountUserReceivable, 0, payment.Amount, "поступление от провайдера"),
+	}
+}
+
+// refundEntries: возврат денег пользователю через провайдера
+func refundEntries(payment *models.Payment, amount int, reason string) []models.LedgerEntry {
+	tx := uuid.New()
+	return []models.LedgerEntry{
+		ledgerEntry(tx, payment, models.LedgerRefund, models.AccountRefunds, amount, 0, reason),
+		ledgerEntry(tx, payment, models.LedgerRefund, models.AccountProviderClearing, 0, amount, reason),
+	}
+}
+
+// adjustmentEntries: положительная корректировка доначисляет выручку, отрицательная — сторнирует
+func adjustmentEntries(payment *models.Payment, amount int, reason string) []models.LedgerEntry {
+	tx := uuid.New()
+	if amount >= 0 {
+		return []models.LedgerEntry{
+			ledgerEntry(tx, payment, models.LedgerAdjustment, models.AccountUserReceivable, amount, 0, reason),
+			ledgerEntry(tx, payment, models.LedgerAdjustment, models.AccountRevenue, 0, amount, reason),
+		}
+	}
+
+	return []models.LedgerEntry{
+		ledgerEntry(tx, payment, models.LedgerAdjustment, models.AccountRevenue, -amount, 0, reason),
+		ledgerEntry(tx, payment, models.LedgerAdjustment, models.AccountUserReceivable, 0, -amount, reason),
+	}
+}
+
+func ledgerEntry(
+	tx uuid.UUID,
+	payment *models.Payment,
+	kind models.LedgerEntryKind,
+	account models.LedgerAccount,
+	debit, credit int,
+	description string,
+) models.LedgerEntry {
+	return models.LedgerEntry{
+		TransactionID: tx,
+		PaymentID:     payment.ID,
+		Kind:          kind,
+		Account:       account,
+		Debit:         debit,
+		Credit:        credit,
+		Currency:      payment.Currency,
+		Description:   description,
+	}
+}
+
+// checkBalanced не даёт записать в журнал операцию, у которой дебет не равен кредиту
+func checkBalanced(entries []models.LedgerEntry) error {
+	total := 0
+	for _, e := range entries {
+		total += e.Debit - e.Credit
+	}
+
+	if total != 0 {
+		return ErrUnbalancedEntries
+	}
+
+	return nil
+}
+
+// ledgerBalanced проверяет, что по каждой валюте дебет журнала равен кредиту
+func ledgerBalanced(balances []dto.AccountBalance) bool {
+	totals := make(map[string]int)
+	for _, b := range balances {
+		totals[b.Currency] += b.Debit - b.Credit
+	}
+
+	for _, total := range totals {
+		if total != 0 {
+			return false
+		}
+	}
+
+	return true
+}
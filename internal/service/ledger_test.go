@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func succeededPayment(amount int) *models.Payment {
+	return &models.Payment{
+		Base:          models.Base{ID: uuid.New()},
+		Amount:        amount,
+		Currency:      "RUB",
+		PaymentStatus: models.PaymentSucces,
+	}
+}
+
+func TestPaymentService_Create_PostsBalancedCharge(t *testing.T) {
+	var posted []models.LedgerEntry
+	repo := &mock.MockPaymentRepository{
+		CreateWithLedgerFn: func(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error {
+			posted = entries
+			return nil
+		},
+	}
+	svc := NewPaymentService(repo, &mock.MockTxManager{}, &mock.MockCache[*models.Payment]{}, newLogger())
+
+	payment, err := svc.Create(context.Background(), &dto.PaymentCreateRequest{
+		SubscriptionID: uuid.New(),
+		OrderID:        uuid.New(),
+		Amount:         500,
+		Currency:       "RUB",
+		PaymentStatus:  models.PaymentSucces,
+		Provider:       "stripe",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, posted, 4)
+	assert.NoError(t, checkBalanced(posted))
+	for _, e := range posted {
+		assert.Equal(t, payment.ID, e.PaymentID)
+		assert.Equal(t, models.LedgerCharge, e.Kind)
+	}
+}
+
+func TestPaymentService_Update_SucceededIsImmutable(t *testing.T) {
+	payment := succeededPayment(500)
+	repo := &mock.MockPaymentRepository{
+		GetForUpdateFn: func(ctx context.Context, id string) (*models.Payment, error) {
+			return payment, nil
+		},
+		UpdateWithLedgerFn: func(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error {
+			t.Fatal("succeeded payment must not be updated")
+			return nil
+		},
+	}
+	svc := NewPaymentService(repo, &mock.MockTxManager{}, &mock.MockCache[*models.Payment]{}, newLogger())
+
+	amount := 700
+	_, err := svc.Update(context.Background(), payment.ID.String(), &dto.PaymentUpdateRequest{Amount: &amount})
+
+	assert.ErrorIs(t, err, ErrPaymentImmutable)
+}
+
+func TestPaymentService_Update_ChargesOnSuccess(t *testing.T) {
+	payment := succeededPayment(500)
+	payment.PaymentStatus = models.PaymentFailed
+
+	var posted []models.LedgerEntry
+	repo := &mock.MockPaymentRepository{
+		GetForUpdateFn: func(ctx context.Context, id string) (*models.Payment, error) {
+			return payment, nil
+		},
+		UpdateWithLedgerFn: func(ctx context.Context, payment *models.Payment, entries []models.LedgerEntry) error {
+			posted = entries
+			return nil
+		},
+	}
+	tx := &mock.MockTxManager{}
+	svc := NewPaymentService(repo, tx, &mock.MockCache[*models.Payment]{}, newLogger())
+
+	status := models.PaymentSucces
+	_, err := svc.Update(context.Background(), payment.ID.String(), &dto.PaymentUpdateRequest{PaymentStatus: &status})
+
+	assert.NoError(t, err)
+	assert.Len(t, posted, 4)
+	assert.Equal(t, 1, tx.Calls)
+}
+
+func TestLedgerService_Refund_ExceedsPayment(t *testing.T) {
+	payment := succeededPayment(500)
+	payments := &mock.MockPaymentRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Payment, error) {
+			return payment, nil
+		},
+	}
+	ledger := &mock.MockLedgerRepository{
+		PostFn: func(ctx context.Context, paymentID uuid.UUID, entries []models.LedgerEntry, check func(summary dto.PaymentLedger) error) error {
+			return check(dto.PaymentLedger{Charged: 500, Refunded: 400})
+		},
+	}
+	svc := NewLedgerService(ledger, payments, newLogger())
+
+	_, err := svc.Refund(context.Background(), payment.ID.String(), &dto.RefundRequest{Amount: 200})
+	assert.ErrorIs(t, err, ErrRefundExceedsPayment)
+
+	entries, err := svc.Refund(context.Background(), payment.ID.String(), &dto.RefundRequest{Amount: 100})
+	assert.NoError(t, err)
+	assert.NoError(t, checkBalanced(entries))
+}
+
+func TestLedgerService_Adjust_Negative(t *testing.T) {
+	payment := succeededPayment(500)
+	payments := &mock.MockPaymentRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Payment, error) {
+			return payment, nil
+		},
+	}
+	svc := NewLedgerService(&mock.MockLedgerRepository{}, payments, newLogger())
+
+	entries, err := svc.Adjust(context.Background(), payment.ID.String(), &dto.AdjustmentRequest{Amount: -50, Reason: "скидка"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AccountRevenue, entries[0].Account)
+	assert.Equal(t, 50, entries[0].Debit)
+	assert.Equal(t, models.AccountUserReceivable, entries[1].Account)
+	assert.Equal(t, 50, entries[1].Credit)
+}
+
+func TestLedgerService_Reconcile(t *testing.T) {
+	ledger := &mock.MockLedgerRepository{
+		AccountBalancesFn: func(ctx context.Context) ([]dto.AccountBalance, error) {
+			return []dto.AccountBalance{
+				{Account: models.AccountProviderClearing, Currency: "RUB", Debit: 500, Balance: 500},
+				{Account: models.AccountRevenue, Currency: "RUB", Credit: 500, Balance: -500},
+			}, nil
+		},
+		PaymentMismatchesFn: func(ctx context.Context) ([]dto.PaymentMismatch, error) {
+			return []dto.PaymentMismatch{{PaymentID: uuid.New(), Amount: 700, Charged: 500}}, nil
+		},
+	}
+	svc := NewLedgerService(ledger, &mock.MockPaymentRepository{}, newLogger())
+
+	report, err := svc.Reconcile(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, report.Balanced)
+	assert.Len(t, report.Mismatches, 1)
+	assert.True(t, ledgerBalanced(report.Accounts))
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"effective-project/internal/dto"
+	"effective-project/internal/media"
+	"effective-project/internal/storage"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrServiceNotFound = errors.New("сервис не найден")
+	ErrLogoTooLarge    = errors.New("файл логотипа слишком большой")
+)
+
+// MaxLogoSize — предельный размер загружаемого файла логотипа
+const MaxLogoSize = 2 << 20
+
+// Стороны миниатюр по убыванию: каждая следующая строится из предыдущей.
+// В logo_url сервиса попадает самая крупная
+var logoSizes = []int{256, 128, 64}
+
+type LogoService interface {
+	// Upload проверяет изображение, сохраняет миниатюры и обновляет logo_url сервиса
+	Upload(ctx context.Context, serviceID string, r io.Reader) (*dto.LogoResponse, error)
+
+	// Open открывает сохранённый медиафайл по ключу
+	Open(ctx context.Context, key string) (io.ReadCloser, *storage.BlobInfo, error)
+}
+
+type logoService struct {
+	services ServiceService
+	store    storage.BlobStore
+	baseURL  string
+	logger   *slog.Logger
+}
+
+// NewLogoService — baseURL задаёт префикс ссылок на медиа, например "/media"
+// или адрес CDN перед ним
+func NewLogoService(
+	services ServiceService,
+	store storage.BlobStore,
+	baseURL string,
+	logger *slog.Logger,
+) LogoService {
+	return &logoService{
+		services: services,
+		store:    store,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		logger:   logger,
+	}
+}
+
+func (s *logoService) Upload(ctx context.Context, serviceID string, r io.Reader) (*dto.LogoResponse, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxLogoSize+1))
+	if err != nil {
+		s.logger.Error("service.logo.upload: failed to read file", slog.Any("error", err))
+		return nil, err
+	}
+	if len(data) > MaxLogoSize {
+		return nil, ErrLogoTooLarge
+	}
+
+	img, _, err := media.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := s.services.GetByID(ctx, serviceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+
+	// ключ зависит от содержимого, поэтому файлы можно кешировать навсегда
+	sum := sha256.Sum256(data)
+	prefix := "logos/" + svc.ID.String() + "/" + hex.EncodeToString(sum[:8])
+
+	resp := &dto.LogoResponse{Thumbnails: make(map[string]string, len(logoSizes))}
+
+	for _, size := range logoSizes {
+		thumb := media.Thumbnail(img, size)
+		img = thumb
+
+		encoded, err := media.EncodePNG(thumb)
+		if err != nil {
+			s.logger.Error("service.logo.upload: failed to encode thumbnail", slog.Any("error", err))
+			return nil, err
+		}
+
+		key := prefix + "/" + strconv.Itoa(size) + ".png"
+		if err := s.store.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), "image/png"); err != nil {
+			s.logger.Error("service.logo.upload: failed to store thumbnail", slog.Any("error", err))
+			return nil, err
+		}
+
+		resp.Thumbnails[strconv.Itoa(size)] = s.baseURL + "/" + key
+	}
+
+	resp.LogoUrl = resp.Thumbnails[strconv.Itoa(logoSizes[0])]
+	previous := svc.LogoUrl
+
+	if _, err := s.services.Update(ctx, serviceID, &dto.ServiceUpdateRequest{LogoUrl: &resp.LogoUrl}); err != nil {
+		return nil, err
+	}
+
+	if previous != resp.LogoUrl {
+		s.removePrevious(ctx, previous)
+	}
+
+	return resp, nil
+}
+
+func (s *logoService) Open(ctx context.Context, key string) (io.ReadCloser, *storage.BlobInfo, error) {
+	return s.store.Get(ctx, key)
+}
+
+// removePrevious удаляет миниатюры прежнего логотипа, если он был загружен к нам,
+// а не указан внешней ссылкой
+func (s *logoService) removePrevious(ctx context.Context, logoURL string) {
+	key, ok := strings.CutPrefix(logoURL, s.baseURL+"/")
+	if !ok || !strings.HasPrefix(key, "logos/") {
+		return
+	}
+
+	prefix := key[:strings.LastIndex(key, "/")]
+	for _, size := range logoSizes {
+		if err := s.store.Delete(ctx, prefix+"/"+strconv.Itoa(size)+".png"); err != nil {
+			s.logger.Warn("service.logo.upload: failed to delete previous logo", slog.Any("error", err))
+		}
+	}
+}
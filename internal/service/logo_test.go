@@ -0,0 +1,91 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"effective-project/internal/media"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestLogoService_Upload(t *testing.T) {
+	svc := &models.Service{Base: models.Base{ID: uuid.New()}, LogoUrl: "/media/logos/old/abc/256.png"}
+	repo := &mock.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			copied := *svc
+			return &copied, nil
+		},
+		UpdateFn: func(ctx context.Context, s *models.Service) error {
+			svc.LogoUrl = s.LogoUrl
+			return nil
+		},
+	}
+	store := &mock.MockBlobStore{Objects: map[string][]byte{"logos/old/abc/256.png": {1}}}
+	logos := NewLogoService(NewServiceService(repo, &mock.MockCache[*models.Service]{}, &mock.MockCache[[]models.Service]{}, newLogger()), store, "/media", newLogger())
+
+	resp, err := logos.Upload(context.Background(), svc.ID.String(), bytes.NewReader(pngImage(t, 600, 300)))
+
+	require.NoError(t, err)
+	assert.Equal(t, resp.Thumbnails["256"], resp.LogoUrl)
+	assert.Equal(t, resp.LogoUrl, svc.LogoUrl)
+	assert.True(t, strings.HasPrefix(resp.LogoUrl, "/media/logos/"+svc.ID.String()+"/"))
+	assert.Len(t, store.Objects, 3)
+	assert.NotContains(t, store.Objects, "logos/old/abc/256.png")
+
+	key := strings.TrimPrefix(resp.Thumbnails["128"], "/media/")
+	thumb, err := png.Decode(bytes.NewReader(store.Objects[key]))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 128, 64), thumb.Bounds())
+}
+
+func TestLogoService_Upload_Rejects(t *testing.T) {
+	logos := NewLogoService(NewServiceService(&mock.MockServiceRepository{}, &mock.MockCache[*models.Service]{}, &mock.MockCache[[]models.Service]{}, newLogger()), &mock.MockBlobStore{}, "/media", newLogger())
+
+	_, err := logos.Upload(context.Background(), uuid.NewString(), strings.NewReader("<svg xmlns='http://www.w3.org/2000/svg'/>"))
+	assert.ErrorIs(t, err, media.ErrUnsupportedImage)
+
+	_, err = logos.Upload(context.Background(), uuid.NewString(), bytes.NewReader(make([]byte, MaxLogoSize+1)))
+	assert.ErrorIs(t, err, ErrLogoTooLarge)
+
+	_, err = logos.Upload(context.Background(), uuid.NewString(), bytes.NewReader(pngImage(t, media.MaxDimension+1, 1)))
+	assert.ErrorIs(t, err, media.ErrImageDimensions)
+}
+
+func TestLogoService_Upload_ServiceNotFound(t *testing.T) {
+	repo := &mock.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	store := &mock.MockBlobStore{}
+	logos := NewLogoService(NewServiceService(repo, &mock.MockCache[*models.Service]{}, &mock.MockCache[[]models.Service]{}, newLogger()), store, "/media", newLogger())
+
+	_, err := logos.Upload(context.Background(), uuid.NewString(), bytes.NewReader(pngImage(t, 10, 10)))
+
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+	assert.Empty(t, store.Objects)
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/cache"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/repository"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidPeriod = errors.New("некорректный период")
+
+const monthLayout = "2006-01"
+
+// maxPeriodMonths — сколько месяцев можно запросить за раз: ряд строится
+// помесячно в памяти, поэтому длина периода ограничена
+const maxPeriodMonths = 60
+
+type MetricsService interface {
+	RecurringRevenue(ctx context.Context, at time.Time) (*dto.RecurringRevenue, error)
+
+	RecurringRevenueSeries(ctx context.Context, f dto.MetricsFilter) ([]dto.RecurringRevenuePoint, error)
+
+	SubscriptionMovement(ctx context.Context, f dto.MetricsFilter) ([]dto.SubscriptionMovementPoint, error)
+
+	CohortRetention(ctx context.Context, f dto.MetricsFilter) ([]dto.CohortRetention, error)
+
+	ARPU(ctx context.Context, f dto.MetricsFilter) ([]dto.ARPUPoint, error)
+}
+
+// metricsCacheTTL — срок жизни посчитанной метрики в кеше
+const metricsCacheTTL = 15 * time.Minute
+
+type metricsService struct {
+	metricsRepo repository.MetricsRepository
+	store       cache.Store
+	logger      *slog.Logger
+}
+
+// NewMetricsService принимает хранилище, а не типизированный кеш:
+// метрики разных видов лежат в нём значениями разных типов
+func NewMetricsService(
+	metricsRepo repository.MetricsRepository,
+	store cache.Store,
+	logger *slog.Logger,
+) MetricsService {
+	return &metricsService{
+		metricsRepo: metricsRepo,
+		store:       store,
+		logger:      logger,
+	}
+}
+
+func (s *metricsService) RecurringRevenue(ctx context.Context, at time.Time) (*dto.RecurringRevenue, error) {
+	key := fmt.Sprintf("mrr:%s", at.Format(time.DateOnly))
+
+	return cachedMetric(ctx, s, key, func() (*dto.RecurringRevenue, error) {
+		rows, err := s.metricsRepo.SubscriptionsStartedBefore(ctx, at)
+		if err != nil {
+			s.logger.Error("service.metrics.recurring_revenue: failed to get subscriptions", slog.Any("error", err))
+			return nil, err
+		}
+
+		mrr := mrrAt(rows, at)
+
+		return &dto.RecurringRevenue{
+			At:  at,
+			MRR: mrr,
+			ARR: mrr * 12,
+		}, nil
+	})
+}
+
+func (s *metricsService) RecurringRevenueSeries(ctx context.Context, f dto.MetricsFilter) ([]dto.RecurringRevenuePoint, error) {
+	months, err := monthRange(f.From, f.To)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("mrr_series:%s:%s", f.From.Format(monthLayout), f.To.Format(monthLayout))
+
+	return cachedMetric(ctx, s, key, func() ([]dto.RecurringRevenuePoint, error) {
+		rows, err := s.metricsRepo.SubscriptionsStartedBefore(ctx, monthEnd(months[len(months)-1]))
+		if err != nil {
+			s.logger.Error("service.metrics.recurring_revenue_series: failed to get subscriptions", slog.Any("error", err))
+			return nil, err
+		}
+
+		points := make([]dto.RecurringRevenuePoint, 0, len(months))
+		for _, m := range months {
+			mrr := mrrAt(rows, monthEnd(m))
+			points = append(points, dto.RecurringRevenuePoint{
+				Month: m.Format(monthLayout),
+				MRR:   mrr,
+				ARR:   mrr * 12,
+			})
+		}
+
+		return points, nil
+	})
+}
+
+func (s *metricsService) SubscriptionMovement(ctx context.Context, f dto.MetricsFilter) ([]dto.SubscriptionMovementPoint, error) {
+	months, err := monthRange(f.From, f.To)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("movement:%s:%s", f.From.Format(monthLayout), f.To.Format(monthLayout))
+
+	return cachedMetric(ctx, s, key, func() ([]dto.SubscriptionMovementPoint, error) {
+		rows, err := s.metricsRepo.SubscriptionsStartedBefore(ctx, monthEnd(months[len(months)-1]))
+		if err != nil {
+			s.logger.Error("service.metrics.subscription_movement: failed to get subscriptions", slog.Any("error", err))
+			return nil, err
+		}
+
+		points := make([]dto.SubscriptionMovementPoint, 0, len(months))
+		for _, m := range months {
+			next := m.AddDate(0, 1, 0)
+
+			var point dto.SubscriptionMovementPoint
+			point.Month = m.Format(monthLayout)
+
+			activeAtStart := 0
+			for _, row := range rows {
+				if !row.StartDate.Before(m) && row.StartDate.Before(next) {
+					point.New++
+				}
+				if !countsAsActive(row) {
+					continue
+				}
+				if row.EndDate != nil && !row.EndDate.Before(m) && row.EndDate.Before(next) {
+					point.Churned++
+				}
+				if row.StartDate.Before(m) && (row.EndDate == nil || !row.EndDate.Before(m)) {
+					activeAtStart++
+				}
+				if isActiveAt(row, monthEnd(m)) {
+					point.Active++
+				}
+			}
+
+			if activeAtStart > 0 {
+				point.ChurnRate = roundRatio(float64(point.Churned) / float64(activeAtStart))
+			}
+
+			points = append(points, point)
+		}
+
+		return points, nil
+	})
+}
+
+func (s *metricsService) CohortRetention(ctx context.Context, f dto.MetricsFilter) ([]dto.CohortRetention, error) {
+	months, err := monthRange(f.From, f.To)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("retention:%s:%s", f.From.Format(monthLayout), f.To.Format(monthLayout))
+
+	return cachedMetric(ctx, s, key, func() ([]dto.CohortRetention, error) {
+		last := months[len(months)-1]
+
+		rows, err := s.metricsRepo.SubscriptionsStartedBefore(ctx, monthEnd(last))
+		if err != nil {
+			s.logger.Error("service.metrics.cohort_retention: failed to get subscriptions", slog.Any("error", err))
+			return nil, err
+		}
+
+		// когорта пользователя — месяц его первой подписки
+		firstStart := make(map[uuid.UUID]time.Time)
+		byUser := make(map[uuid.UUID][]dto.MetricsSubscriptionRow)
+		for _, row := range rows {
+			if first, ok := firstStart[row.UserID]; !ok || row.StartDate.Before(first) {
+				firstStart[row.UserID] = row.StartDate
+			}
+			byUser[row.UserID] = append(byUser[row.UserID], row)
+		}
+
+		cohorts := make(map[time.Time][]uuid.UUID)
+		for userID, start := range firstStart {
+			cohort := startOfMonth(start)
+			cohorts[cohort] = append(cohorts[cohort], userID)
+		}
+
+		result := make([]dto.CohortRetention, 0, len(months))
+		for _, cohort := range months {
+			users := cohorts[cohort]
+			if len(users) == 0 {
+				continue
+			}
+
+			retention := make([]float64, 0)
+			for m := cohort; !m.After(last); m = m.AddDate(0, 1, 0) {
+				retained := 0
+				for _, userID := range users {
+					if activeDuringMonth(byUser[userID], m) {
+						retained++
+					}
+				}
+				retention = append(retention, roundRatio(float64(retained)/float64(len(users))))
+			}
+
+			result = append(result, dto.CohortRetention{
+				Cohort:    cohort.Format(monthLayout),
+				Size:      len(users),
+				Retention: retention,
+			})
+		}
+
+		return result, nil
+	})
+}
+
+func (s *metricsService) ARPU(ctx context.Context, f dto.MetricsFilter) ([]dto.ARPUPoint, error) {
+	months, err := monthRange(f.From, f.To)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("arpu:%s:%s:%s", f.Currency, f.From.Format(monthLayout), f.To.Format(monthLayout))
+
+	return cachedMetric(ctx, s, key, func() ([]dto.ARPUPoint, error) {
+		rows, err := s.metricsRepo.RevenueByMonth(ctx, dto.MetricsFilter{
+			From:     months[0],
+			To:       months[len(months)-1],
+			Currency: f.Currency,
+		})
+		if err != nil {
+			s.logger.Error("service.metrics.arpu: failed to get revenue", slog.Any("error", err))
+			return nil, err
+		}
+
+		byMonth := make(map[string]dto.RevenueRow, len(rows))
+		for _, row := range rows {
+			byMonth[row.Month.Format(monthLayout)] = row
+		}
+
+		points := make([]dto.ARPUPoint, 0, len(months))
+		for _, m := range months {
+			month := m.Format(monthLayout)
+			row := byMonth[month]
+
+			point := dto.ARPUPoint{
+				Month:       month,
+				Revenue:     row.Revenue,
+				PayingUsers: row.PayingUsers,
+			}
+			if row.PayingUsers > 0 {
+				point.ARPU = math.Round(float64(row.Revenue)/float64(row.PayingUsers)*100) / 100
+			}
+
+			points = append(points, point)
+		}
+
+		return points, nil
+	})
+}
+
+// cachedMetric отдаёт метрику из кеша, а при промахе считает и сохраняет её
+func cachedMetric[T any](ctx context.Context, s *metricsService, key string, compute func() (T, error)) (T, error) {
+	metrics := cache.New[T](s.store, cache.PrefixMetrics, cache.Options{Jitter: 0.1, Logger: s.logger})
+
+	return metrics.GetOrLoad(ctx, key, func(ctx context.Context) (T, error) {
+		return compute()
+	}, metricsCacheTTL)
+}
+
+// mrrAt приводит годовые подписки к месяцу делением на 12; подписка на паузе
+// выручку не приносит
+func mrrAt(rows []dto.MetricsSubscriptionRow, at time.Time) int {
+	monthly, yearly := 0, 0
+	for _, row := range rows {
+		if !isActiveAt(row, at) || isPausedAt(row, at) {
+			continue
+		}
+		if row.Interval == models.IntervalYear {
+			yearly += row.Price
+		} else {
+			monthly += row.Price
+		}
+	}
+	return monthly + yearly/12
+}
+
+func isPausedAt(row dto.MetricsSubscriptionRow, at time.Time) bool {
+	return row.PausedUntil != nil && at.Before(*row.PausedUntil)
+}
+
+// countsAsActive отсекает подписки, которые не платят: past_due не входит ни
+// в выручку, ни в базу оттока, а отменённая учитывается только до своей end_date
+func countsAsActive(row dto.MetricsSubscriptionRow) bool {
+	switch row.Status {
+	case models.SubscriptionPastDue:
+		return false
+	case models.SubscriptionCancelled:
+		return row.EndDate != nil
+	}
+	return true
+}
+
+func isActiveAt(row dto.MetricsSubscriptionRow, at time.Time) bool {
+	if !countsAsActive(row) || row.StartDate.After(at) {
+		return false
+	}
+	return row.EndDate == nil || !row.EndDate.Before(at)
+}
+
+func activeDuringMonth(rows []dto.MetricsSubscriptionRow, month time.Time) bool {
+	next := month.AddDate(0, 1, 0)
+	for _, row := range rows {
+		if countsAsActive(row) && row.StartDate.Before(next) && (row.EndDate == nil || !row.EndDate.Before(month)) {
+			return true
+		}
+	}
+	return false
+}
+
+func monthRange(from, to time.Time) ([]time.Time, error) {
+	from = startOfMonth(from)
+	to = startOfMonth(to)
+
+	if from.IsZero() || to.IsZero() || from.After(to) {
+		return nil, ErrInvalidPeriod
+	}
+	if to.After(from.AddDate(0, maxPeriodMonths-1, 0)) {
+		return nil, ErrInvalidPeriod
+	}
+
+	var months []time.Time
+	for m := from; !m.After(to); m = m.AddDate(0, 1, 0) {
+		months = append(months, m)
+	}
+
+	return months, nil
+}
+
+func startOfMonth(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthEnd(month time.Time) time.Time {
+	return month.AddDate(0, 1, 0).Add(-time.Nanosecond)
+}
+
+func roundRatio(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
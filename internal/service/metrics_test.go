@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-project/internal/cache"
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func metricsRows(userA, userB uuid.UUID) []dto.MetricsSubscriptionRow {
+	end := time.Date(2025, time.February, 15, 0, 0, 0, 0, time.UTC)
+
+	return []dto.MetricsSubscriptionRow{
+		{UserID: userA, StartDate: month(2025, time.January), Price: 400},
+		{UserID: userB, StartDate: month(2025, time.January), EndDate: &end, Price: 200},
+		{UserID: userB, StartDate: month(2025, time.March), Price: 300},
+	}
+}
+
+func TestMetricsService_RecurringRevenue(t *testing.T) {
+	repo := &mock.MockMetricsRepository{
+		SubscriptionsStartedBeforeFn: func(ctx context.Context, to time.Time) ([]dto.MetricsSubscriptionRow, error) {
+			return metricsRows(uuid.New(), uuid.New()), nil
+		},
+	}
+	svc := NewMetricsService(repo, &mock.MockStore{}, newLogger())
+
+	revenue, err := svc.RecurringRevenue(context.Background(), time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 600, revenue.MRR)
+	assert.Equal(t, 7200, revenue.ARR)
+}
+
+func TestMetricsService_RecurringRevenue_YearlyAndPaused(t *testing.T) {
+	pausedUntil := month(2025, time.April)
+	repo := &mock.MockMetricsRepository{
+		SubscriptionsStartedBeforeFn: func(ctx context.Context, to time.Time) ([]dto.MetricsSubscriptionRow, error) {
+			return []dto.MetricsSubscriptionRow{
+				{UserID: uuid.New(), StartDate: month(2025, time.January), Price: 400, Interval: models.IntervalMonth},
+				{UserID: uuid.New(), StartDate: month(2025, time.January), Price: 1200, Interval: models.IntervalYear},
+				{UserID: uuid.New(), StartDate: month(2025, time.January), Price: 300, Interval: models.IntervalMonth, PausedUntil: &pausedUntil},
+			}, nil
+		},
+	}
+	svc := NewMetricsService(repo, &mock.MockStore{}, newLogger())
+
+	revenue, err := svc.RecurringRevenue(context.Background(), time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 500, revenue.MRR)
+	assert.Equal(t, 6000, revenue.ARR)
+}
+
+func TestMetricsService_ExcludesNonActiveStatuses(t *testing.T) {
+	cancelledAt := time.Date(2025, time.February, 20, 0, 0, 0, 0, time.UTC)
+	repo := &mock.MockMetricsRepository{
+		SubscriptionsStartedBeforeFn: func(ctx context.Context, to time.Time) ([]dto.MetricsSubscriptionRow, error) {
+			return []dto.MetricsSubscriptionRow{
+				{UserID: uuid.New(), StartDate: month(2025, time.January), Price: 400, Status: models.SubscriptionActive},
+				{UserID: uuid.New(), StartDate: month(2025, time.January), Price: 200, Status: models.SubscriptionPastDue},
+				{UserID: uuid.New(), StartDate: month(2025, time.January), EndDate: &cancelledAt, Price: 100, Status: models.SubscriptionCancelled},
+			}, nil
+		},
+	}
+	svc := NewMetricsService(repo, &mock.MockStore{}, newLogger())
+
+	revenue, err := svc.RecurringRevenue(context.Background(), time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 500, revenue.MRR)
+	assert.Equal(t, 6000, revenue.ARR)
+
+	points, err := svc.SubscriptionMovement(context.Background(), dto.MetricsFilter{
+		From: month(2025, time.February),
+		To:   month(2025, time.February),
+	})
+
+	// past_due не входит в базу оттока, отменённая уходит в отток в месяц end_date
+	assert.NoError(t, err)
+	assert.Equal(t, dto.SubscriptionMovementPoint{Month: "2025-02", Churned: 1, Active: 1, ChurnRate: 0.5}, points[0])
+}
+
+func TestMetricsService_SubscriptionMovement(t *testing.T) {
+	repo := &mock.MockMetricsRepository{
+		SubscriptionsStartedBeforeFn: func(ctx context.Context, to time.Time) ([]dto.MetricsSubscriptionRow, error) {
+			return metricsRows(uuid.New(), uuid.New()), nil
+		},
+	}
+	svc := NewMetricsService(repo, &mock.MockStore{}, newLogger())
+
+	points, err := svc.SubscriptionMovement(context.Background(), dto.MetricsFilter{
+		From: month(2025, time.January),
+		To:   month(2025, time.March),
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, points, 3)
+
+	assert.Equal(t, dto.SubscriptionMovementPoint{Month: "2025-01", New: 2, Active: 2}, points[0])
+	assert.Equal(t, dto.SubscriptionMovementPoint{Month: "2025-02", Churned: 1, Active: 1, ChurnRate: 0.5}, points[1])
+	assert.Equal(t, dto.SubscriptionMovementPoint{Month: "2025-03", New: 1, Active: 2}, points[2])
+}
+
+func TestMetricsService_CohortRetention(t *testing.T) {
+	userA, userB := uuid.New(), uuid.New()
+	repo := &mock.MockMetricsRepository{
+		SubscriptionsStartedBeforeFn: func(ctx context.Context, to time.Time) ([]dto.MetricsSubscriptionRow, error) {
+			end := time.Date(2025, time.January, 20, 0, 0, 0, 0, time.UTC)
+			return []dto.MetricsSubscriptionRow{
+				{UserID: userA, StartDate: month(2025, time.January), Price: 400},
+				{UserID: userB, StartDate: month(2025, time.January), EndDate: &end, Price: 200},
+			}, nil
+		},
+	}
+	svc := NewMetricsService(repo, &mock.MockStore{}, newLogger())
+
+	cohorts, err := svc.CohortRetention(context.Background(), dto.MetricsFilter{
+		From: month(2025, time.January),
+		To:   month(2025, time.March),
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, cohorts, 1)
+	assert.Equal(t, "2025-01", cohorts[0].Cohort)
+	assert.Equal(t, 2, cohorts[0].Size)
+	assert.Equal(t, []float64{1, 0.5, 0.5}, cohorts[0].Retention)
+}
+
+func TestMetricsService_ARPU_FillsEmptyMonths(t *testing.T) {
+	repo := &mock.MockMetricsRepository{
+		RevenueByMonthFn: func(ctx context.Context, f dto.MetricsFilter) ([]dto.RevenueRow, error) {
+			assert.Equal(t, "RUB", f.Currency)
+			return []dto.RevenueRow{
+				{Month: month(2025, time.February), Revenue: 1000, PayingUsers: 3},
+			}, nil
+		},
+	}
+	svc := NewMetricsService(repo, &mock.MockStore{}, newLogger())
+
+	points, err := svc.ARPU(context.Background(), dto.MetricsFilter{
+		From:     month(2025, time.January),
+		To:       month(2025, time.February),
+		Currency: "RUB",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.ARPUPoint{
+		{Month: "2025-01"},
+		{Month: "2025-02", Revenue: 1000, PayingUsers: 3, ARPU: 333.33},
+	}, points)
+}
+
+func TestMetricsService_CacheHit(t *testing.T) {
+	repoCalled := false
+	repo := &mock.MockMetricsRepository{
+		SubscriptionsStartedBeforeFn: func(ctx context.Context, to time.Time) ([]dto.MetricsSubscriptionRow, error) {
+			repoCalled = true
+			return nil, nil
+		},
+	}
+	store := &mock.MockStore{}
+	cached := cache.New[*dto.RecurringRevenue](store, cache.PrefixMetrics, cache.Options{})
+	assert.NoError(t, cached.Set(context.Background(), "mrr:2025-02-10", &dto.RecurringRevenue{MRR: 100, ARR: 1200}, time.Hour))
+	svc := NewMetricsService(repo, store, newLogger())
+
+	revenue, err := svc.RecurringRevenue(context.Background(), time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100, revenue.MRR)
+	assert.False(t, repoCalled)
+}
+
+func TestMetricsService_InvalidPeriod(t *testing.T) {
+	svc := NewMetricsService(&mock.MockMetricsRepository{}, &mock.MockStore{}, newLogger())
+
+	_, err := svc.RecurringRevenueSeries(context.Background(), dto.MetricsFilter{
+		From: month(2025, time.March),
+		To:   month(2025, time.January),
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestMetricsService_PeriodTooLong(t *testing.T) {
+	svc := NewMetricsService(&mock.MockMetricsRepository{}, &mock.MockStore{}, newLogger())
+
+	_, err := svc.RecurringRevenueSeries(context.Background(), dto.MetricsFilter{
+		From: month(2020, time.January),
+		To:   month(2025, time.January),
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"effective-project/internal/cache"
@@ -9,88 +10,141 @@ import (
 	"effective-project/internal/models"
 	"effective-project/internal/repository"
 	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type OrderService interface {
-	Create(req dto.OrderCreateRequest) (*models.Order, error)
+	Create(ctx context.Context, req dto.OrderCreateRequest) (*models.Order, error)
 
-	GetByID(id string) (*models.Order, error)
+	GetByID(ctx context.Context, id string) (*models.Order, error)
 
-	Update(id string, req dto.OrderUpdateRequest) (*models.Order, error)
+	Update(ctx context.Context, id string, req dto.OrderUpdateRequest) (*models.Order, error)
 }
 
+// orderCacheTTL — срок жизни заказа в кеше
+const orderCacheTTL = 5 * time.Minute
+
 type orderService struct {
-	orderRepo  repository.OrderRepository
-	orderCache cache.OrderCache
-	logger     *slog.Logger
+	orderRepo   repository.OrderRepository
+	serviceRepo repository.ServiceRepository
+	txManager   repository.TxManager
+	orderCache  cache.Cache[*models.Order]
+	coupons     CouponService
+	taxes       taxRecorder
+	logger      *slog.Logger
 }
 
-func NewOrderService(orderRepo repository.OrderRepository, orderCache cache.OrderCache, logger *slog.Logger) OrderService {
+func NewOrderService(orderRepo repository.OrderRepository, serviceRepo repository.ServiceRepository, txManager repository.TxManager, orderCache cache.Cache[*models.Order], coupons CouponService, taxes taxRecorder, logger *slog.Logger) OrderService {
 	return &orderService{
-		orderRepo:  orderRepo,
-		orderCache: orderCache,
-		logger:     logger,
+		orderRepo:   orderRepo,
+		serviceRepo: serviceRepo,
+		txManager:   txManager,
+		orderCache:  orderCache,
+		coupons:     coupons,
+		taxes:       taxes,
+		logger:      logger,
 	}
 }
 
-func (s *orderService) Create(req dto.OrderCreateRequest) (*models.Order, error) {
+func (s *orderService) Create(ctx context.Context, req dto.OrderCreateRequest) (*models.Order, error) {
 	op := "service.order.create"
-	ctx := context.Background()
 
 	s.logger.Debug("service call", slog.String("op", op))
 
+	// цена берётся из сервиса: клиент не может назначить её сам
+	svc, err := s.serviceRepo.GetByID(ctx, req.ServiceID.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		s.logger.Error("service.order.create: failed to get service", slog.String("op", op), slog.Any("error", err))
+		return nil, err
+	}
+
 	order := &models.Order{
 		UserID:    req.UserID,
 		ServiceID: req.ServiceID,
 		IsPaid:    req.IsPaid,
+		ListPrice: svc.Price,
+		Price:     svc.Price,
 	}
 
-	if err := s.orderRepo.Create(order); err != nil {
-		s.logger.Error("service.order.create: failed to create order", slog.String("op", op), slog.Any("error", err))
+	// погашение купона, заказ и налог сохраняются вместе: ошибка любого шага
+	// откатывает всё оформление
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if req.CouponCode != "" {
+			// ID нужен заранее, чтобы погашение купона ссылалось на заказ
+			order.ID = uuid.New()
+
+			snapshot, err := s.coupons.Apply(ctx, dto.CouponApplication{
+				Code:      req.CouponCode,
+				UserID:    req.UserID,
+				ServiceID: req.ServiceID,
+				Amount:    svc.Price,
+				Currency:  models.DefaultCurrency,
+				OrderID:   &order.ID,
+			})
+			if err != nil {
+				s.logger.Warn("service.order.create: failed to apply coupon", slog.String("op", op), slog.Any("error", err))
+				return err
+			}
+
+			order.Price = snapshot.Price
+			order.Discount = snapshot.Discount
+			order.CouponID = snapshot.CouponID
+		}
+
+		if err := s.orderRepo.Create(ctx, order); err != nil {
+			s.logger.Error("service.order.create: failed to create order", slog.String("op", op), slog.Any("error", err))
+			return err
+		}
+
+		if err := s.taxes.Record(ctx, dto.TaxableItem{
+			UserID:  order.UserID,
+			Amount:  order.Price,
+			OrderID: &order.ID,
+		}); err != nil {
+			s.logger.Error("service.order.create: failed to record tax", slog.String("op", op), slog.Any("error", err))
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	if err := s.orderCache.Set(ctx, order, time.Minute*5); err != nil {
+	if err := s.orderCache.Set(afterCommit(ctx), order.ID.String(), order, orderCacheTTL); err != nil {
 		s.logger.Warn("service.order.create: failed to set order in cache", slog.String("op", op), slog.Any("error", err))
 	}
 
 	return order, nil
 }
 
-func (s *orderService) GetByID(id string) (*models.Order, error) {
+func (s *orderService) GetByID(ctx context.Context, id string) (*models.Order, error) {
 	op := "service.order.get_by_id"
-	ctx := context.Background()
 
 	s.logger.Debug("service call", slog.String("op", op), slog.Any("id", id))
 
-	order, err := s.orderCache.GetByID(ctx, id)
-	if err != nil {
-		s.logger.Warn("service.order.get_by_id: failed to get order from cache", slog.String("op", op), slog.Any("error", err))
-	}
-	if order != nil {
-		return order, nil
-	}
-
-	order, err = s.orderRepo.GetByID(id)
+	order, err := s.orderCache.GetOrLoad(ctx, id, func(ctx context.Context) (*models.Order, error) {
+		return s.orderRepo.GetByID(ctx, id)
+	}, orderCacheTTL)
 	if err != nil {
 		s.logger.Error("service.order.get_by_id: failed to get order", slog.String("op", op), slog.Any("error", err))
 		return nil, err
 	}
 
-	if err := s.orderCache.Set(ctx, order, time.Minute*5); err != nil {
-		s.logger.Warn("service.order.get_by_id: failed to set order in cache", slog.String("op", op), slog.Any("error", err))
-	}
-
 	return order, nil
 }
 
-func (s *orderService) Update(id string, req dto.OrderUpdateRequest) (*models.Order, error) {
+func (s *orderService) Update(ctx context.Context, id string, req dto.OrderUpdateRequest) (*models.Order, error) {
 	op := "service.order.update"
-	ctx := context.Background()
 
 	s.logger.Debug("service call", slog.String("op", op))
 
-	order, err := s.orderRepo.GetByID(id)
+	order, err := s.orderRepo.GetByID(ctx, id)
 	if err != nil {
 		s.logger.Error("service.order.update: failed to get order", slog.String("op", op), slog.Any("error", err))
 		return nil, err
@@ -100,12 +154,12 @@ func (s *orderService) Update(id string, req dto.OrderUpdateRequest) (*models.Or
 		order.IsPaid = *req.IsPaid
 	}
 
-	if err := s.orderRepo.Update(order); err != nil {
+	if err := s.orderRepo.Update(ctx, order); err != nil {
 		s.logger.Error("service.order.update: failed to update order", slog.String("op", op), slog.Any("error", err))
 		return nil, err
 	}
 
-	if err := s.orderCache.Set(ctx, order, time.Minute*5); err != nil {
+	if err := s.orderCache.Set(afterCommit(ctx), order.ID.String(), order, orderCacheTTL); err != nil {
 		s.logger.Warn("service.order.update: failed to set order in cache", slog.String("op", op), slog.Any("error", err))
 	}
 
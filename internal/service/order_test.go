@@ -8,56 +8,75 @@ import (
 	"testing"
 	"time"
 
+	"effective-project/internal/cache"
 	"effective-project/internal/dto"
+	mocks "effective-project/internal/mock"
 	"effective-project/internal/models"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 // ---- Mocks ----
 
 type orderRepoMock struct{ mock.Mock }
 
-func (m *orderRepoMock) Create(order *models.Order) error {
-	args := m.Called(order)
+func (m *orderRepoMock) Create(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
 	return args.Error(0)
 }
 
-func (m *orderRepoMock) GetByID(id string) (*models.Order, error) {
-	args := m.Called(id)
+func (m *orderRepoMock) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Order), args.Error(1)
 }
 
-func (m *orderRepoMock) Update(order *models.Order) error {
-	args := m.Called(order)
+func (m *orderRepoMock) Update(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
 	return args.Error(0)
 }
 
 type orderCacheMock struct{ mock.Mock }
 
-func (m *orderCacheMock) GetByID(ctx context.Context, id string) (*models.Order, error) {
-	args := m.Called(ctx, id)
+func (m *orderCacheMock) Get(ctx context.Context, key string) (*models.Order, error) {
+	args := m.Called(ctx, key)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Order), args.Error(1)
 }
 
-func (m *orderCacheMock) Set(ctx context.Context, order *models.Order, ttl time.Duration) error {
-	args := m.Called(ctx, order, ttl)
+func (m *orderCacheMock) Set(ctx context.Context, key string, value *models.Order, ttl time.Duration) error {
+	args := m.Called(ctx, key, value, ttl)
 	return args.Error(0)
 }
 
-func (m *orderCacheMock) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *orderCacheMock) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *orderCacheMock) Invalidate(ctx context.Context, tags ...string) error {
+	args := m.Called(ctx, tags)
 	return args.Error(0)
 }
 
+func (m *orderCacheMock) GetOrLoad(ctx context.Context, key string, load cache.Loader[*models.Order], ttl time.Duration) (*models.Order, error) {
+	if value, err := m.Get(ctx, key); err == nil {
+		return value, nil
+	}
+	value, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return value, m.Set(ctx, key, value, ttl)
+}
+
 // ---- Tests ----
 
 func newLogger() *slog.Logger {
@@ -68,16 +87,22 @@ func TestOrderService_Create(t *testing.T) {
 	repo := new(orderRepoMock)
 	cache := new(orderCacheMock)
 	logger := newLogger()
-	service := NewOrderService(repo, cache, logger)
+	services := &mocks.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return &models.Service{Price: 990}, nil
+		},
+	}
+	service := NewOrderService(repo, services, &mocks.MockTxManager{}, cache, nil, &mocks.MockTaxRecorder{}, logger)
 
 	req := dto.OrderCreateRequest{UserID: uuid.New(), ServiceID: uuid.New(), IsPaid: false}
 
-	repo.On("Create", mock.MatchedBy(func(o *models.Order) bool {
-		return o.UserID == req.UserID && o.ServiceID == req.ServiceID && o.IsPaid == req.IsPaid
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.UserID == req.UserID && o.ServiceID == req.ServiceID && o.IsPaid == req.IsPaid &&
+			o.ListPrice == 990 && o.Price == 990
 	})).Return(nil)
-	cache.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	order, err := service.Create(req)
+	order, err := service.Create(context.Background(), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, req.UserID, order.UserID)
@@ -88,20 +113,64 @@ func TestOrderService_Create(t *testing.T) {
 	cache.AssertExpectations(t)
 }
 
+func TestOrderService_Create_TaxFails(t *testing.T) {
+	repo := new(orderRepoMock)
+	cache := new(orderCacheMock)
+	services := &mocks.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return &models.Service{Price: 990}, nil
+		},
+	}
+	tx := &mocks.MockTxManager{}
+	taxes := &mocks.MockTaxRecorder{
+		RecordFn: func(ctx context.Context, item dto.TaxableItem) error {
+			return errors.New("tax failed")
+		},
+	}
+	service := NewOrderService(repo, services, tx, cache, nil, taxes, newLogger())
+
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	order, err := service.Create(context.Background(), dto.OrderCreateRequest{UserID: uuid.New(), ServiceID: uuid.New()})
+
+	// налог пишется в транзакции заказа: его ошибка откатывает заказ и не попадает в кеш
+	assert.EqualError(t, err, "tax failed")
+	assert.Nil(t, order)
+	assert.Equal(t, 1, tx.Calls)
+	cache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrderService_Create_ServiceNotFound(t *testing.T) {
+	repo := new(orderRepoMock)
+	cache := new(orderCacheMock)
+	services := &mocks.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	service := NewOrderService(repo, services, &mocks.MockTxManager{}, cache, nil, &mocks.MockTaxRecorder{}, newLogger())
+
+	order, err := service.Create(context.Background(), dto.OrderCreateRequest{UserID: uuid.New(), ServiceID: uuid.New()})
+
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+	assert.Nil(t, order)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestOrderService_GetByID_Success(t *testing.T) {
 	repo := new(orderRepoMock)
 	cache := new(orderCacheMock)
 	logger := newLogger()
-	service := NewOrderService(repo, cache, logger)
+	service := NewOrderService(repo, nil, nil, cache, nil, &mocks.MockTaxRecorder{}, logger)
 
 	id := uuid.New()
 	expected := &models.Order{Base: models.Base{ID: id}, UserID: uuid.New(), ServiceID: uuid.New(), IsPaid: true}
 
-	cache.On("GetByID", mock.Anything, id.String()).Return(nil, errors.New("cache miss"))
-	repo.On("GetByID", id.String()).Return(expected, nil)
-	cache.On("Set", mock.Anything, expected, mock.Anything).Return(nil)
+	cache.On("Get", mock.Anything, id.String()).Return(nil, errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, id.String()).Return(expected, nil)
+	cache.On("Set", mock.Anything, id.String(), expected, mock.Anything).Return(nil)
 
-	order, err := service.GetByID(id.String())
+	order, err := service.GetByID(context.Background(), id.String())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expected, order)
@@ -114,14 +183,14 @@ func TestOrderService_GetByID_NotFound(t *testing.T) {
 	repo := new(orderRepoMock)
 	cache := new(orderCacheMock)
 	logger := newLogger()
-	service := NewOrderService(repo, cache, logger)
+	service := NewOrderService(repo, nil, nil, cache, nil, &mocks.MockTaxRecorder{}, logger)
 
 	id := uuid.New()
 
-	cache.On("GetByID", mock.Anything, id.String()).Return(nil, errors.New("cache miss"))
-	repo.On("GetByID", id.String()).Return(nil, errors.New("not found"))
+	cache.On("Get", mock.Anything, id.String()).Return(nil, errors.New("cache miss"))
+	repo.On("GetByID", mock.Anything, id.String()).Return(nil, errors.New("not found"))
 
-	order, err := service.GetByID(id.String())
+	order, err := service.GetByID(context.Background(), id.String())
 
 	assert.Error(t, err)
 	assert.Nil(t, order)
@@ -134,17 +203,17 @@ func TestOrderService_Update(t *testing.T) {
 	repo := new(orderRepoMock)
 	cache := new(orderCacheMock)
 	logger := newLogger()
-	service := NewOrderService(repo, cache, logger)
+	service := NewOrderService(repo, nil, nil, cache, nil, &mocks.MockTaxRecorder{}, logger)
 
 	id := uuid.New()
 	existing := &models.Order{Base: models.Base{ID: id}, UserID: uuid.New(), ServiceID: uuid.New(), IsPaid: false}
 
-	repo.On("GetByID", id.String()).Return(existing, nil)
-	repo.On("Update", mock.MatchedBy(func(o *models.Order) bool { return o.IsPaid && o.Base.ID == id })).Return(nil)
-	cache.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	repo.On("GetByID", mock.Anything, id.String()).Return(existing, nil)
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(o *models.Order) bool { return o.IsPaid && o.Base.ID == id })).Return(nil)
+	cache.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	isPaid := true
-	updated, err := service.Update(id.String(), dto.OrderUpdateRequest{IsPaid: &isPaid})
+	updated, err := service.Update(context.Background(), id.String(), dto.OrderUpdateRequest{IsPaid: &isPaid})
 
 	assert.NoError(t, err)
 	assert.True(t, updated.IsPaid)
@@ -5,6 +5,7 @@ import (
 	"effective-project/internal/cache"
 	"effective-project/internal/dto"
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"effective-project/internal/repository"
 	"log/slog"
 	"time"
@@ -13,41 +14,42 @@ import (
 )
 
 type PaymentService interface {
-	Create(req *dto.PaymentCreateRequest) (models.Payment, error)
+	Create(ctx context.Context, req *dto.PaymentCreateRequest) (models.Payment, error)
 
-	List(
-		ctx context.Context,
-		limit int,
-		lastCreatedAt *time.Time,
-		lastID *uuid.UUID,
-	) ([]models.Payment, error)
+	List(ctx context.Context, q *query.List) ([]models.Payment, error)
 
-	GetByID(id string) (*models.Payment, error)
+	GetByID(ctx context.Context, id string) (*models.Payment, error)
 
-	Update(id string, req *dto.PaymentUpdateRequest) (*models.Payment, error)
+	Update(ctx context.Context, id string, req *dto.PaymentUpdateRequest) (*models.Payment, error)
 
-	Delete(id string) error
+	Delete(ctx context.Context, id string) error
 }
 
+// paymentCacheTTL — срок жизни платежа в кеше
+const paymentCacheTTL = 10 * time.Minute
+
 type paymentService struct {
 	paymentRepo  repository.PaymentRepository
-	paymentCache cache.PaymentCache
+	txManager    repository.TxManager
+	paymentCache cache.Cache[*models.Payment]
 	logger       *slog.Logger
 }
 
 func NewPaymentService(
 	paymentRepo repository.PaymentRepository,
-	paymentCache cache.PaymentCache,
+	txManager repository.TxManager,
+	paymentCache cache.Cache[*models.Payment],
 	logger *slog.Logger,
 ) PaymentService {
 	return &paymentService{
 		paymentRepo:  paymentRepo,
+		txManager:    txManager,
 		paymentCache: paymentCache,
 		logger:       logger,
 	}
 }
 
-func (s *paymentService) Create(req *dto.PaymentCreateRequest) (models.Payment, error) {
+func (s *paymentService) Create(ctx context.Context, req *dto.PaymentCreateRequest) (models.Payment, error) {
 	payment := models.Payment{
 		SubscriptionID: req.SubscriptionID,
 		OrderID:        req.OrderID,
@@ -56,9 +58,18 @@ func (s *paymentService) Create(req *dto.PaymentCreateRequest) (models.Payment,
 		PaidAt:         req.PaidAt,
 		PaymentStatus:  req.PaymentStatus,
 		Provider:       req.Provider,
+		Attempt:        1,
+	}
+
+	// ID нужен заранее, чтобы проводки ссылались на платёж
+	payment.ID = uuid.New()
+
+	var entries []models.LedgerEntry
+	if payment.PaymentStatus == models.PaymentSucces {
+		entries = ChargeEntries(&payment)
 	}
 
-	if err := s.paymentRepo.Create(&payment); err != nil {
+	if err := s.paymentRepo.CreateWithLedger(ctx, &payment, entries); err != nil {
 		s.logger.Error("service.payment.create: failed to create payment", slog.Any("error", err))
 		return models.Payment{}, err
 	}
@@ -66,13 +77,8 @@ func (s *paymentService) Create(req *dto.PaymentCreateRequest) (models.Payment,
 	return payment, nil
 }
 
-func (s *paymentService) List(
-	ctx context.Context,
-	limit int,
-	lastCreatedAt *time.Time,
-	lastID *uuid.UUID,
-) ([]models.Payment, error) {
-	payments, err := s.paymentRepo.List(ctx, limit, lastCreatedAt, lastID)
+func (s *paymentService) List(ctx context.Context, q *query.List) ([]models.Payment, error) {
+	payments, err := s.paymentRepo.List(ctx, q)
 	if err != nil {
 		s.logger.Error("service.payment.get_all: failed to get payments", slog.Any("error", err))
 		return nil, err
@@ -81,71 +87,102 @@ func (s *paymentService) List(
 	return payments, nil
 }
 
-func (s *paymentService) GetByID(id string) (*models.Payment, error) {
-	ctx := context.Background()
-
-	payment, err := s.paymentCache.GetByID(ctx, id)
-	if err == nil {
-		return payment, nil
-	}
-
-	payment, err = s.paymentRepo.GetByID(id)
+func (s *paymentService) GetByID(ctx context.Context, id string) (*models.Payment, error) {
+	payment, err := s.paymentCache.GetOrLoad(ctx, id, func(ctx context.Context) (*models.Payment, error) {
+		return s.paymentRepo.GetByID(ctx, id)
+	}, paymentCacheTTL)
 	if err != nil {
 		s.logger.Error("service.payment.get_by_id: failed to get payment", slog.Any("error", err))
 		return nil, err
 	}
 
-	if err := s.paymentCache.Set(ctx, payment, time.Minute*10); err != nil {
-		s.logger.Warn("service.payment.get_by_id: failed to set cache", slog.Any("error", err))
-	}
-
 	return payment, nil
 }
 
-func (s *paymentService) Update(id string, req *dto.PaymentUpdateRequest) (*models.Payment, error) {
-	payment, err := s.paymentRepo.GetByID(id)
+func (s *paymentService) Update(ctx context.Context, id string, req *dto.PaymentUpdateRequest) (*models.Payment, error) {
+	var payment *models.Payment
+
+	// статус перечитывается под блокировкой: иначе два параллельных перевода
+	// в succeeded оба увидят неуспешный платёж и дважды проведут списание
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		payment, err = s.paymentRepo.GetForUpdate(ctx, id)
+		if err != nil {
+			s.logger.Error("service.payment.update: failed to get payment", slog.Any("error", err))
+			return err
+		}
+
+		succeeded := payment.PaymentStatus == models.PaymentSucces
+
+		// успешный платёж уже отражён в журнале: изменить его можно только возвратом или корректировкой
+		if succeeded && paymentLedgerChanged(payment, req) {
+			return ErrPaymentImmutable
+		}
+
+		if req.Amount != nil {
+			payment.Amount = *req.Amount
+		}
+		if req.Currency != nil {
+			payment.Currency = *req.Currency
+		}
+		if req.PaidAt != nil {
+			payment.PaidAt = *req.PaidAt
+		}
+		if req.PaymentStatus != nil {
+			payment.PaymentStatus = *req.PaymentStatus
+		}
+		if req.Provider != nil {
+			payment.Provider = *req.Provider
+		}
+
+		var entries []models.LedgerEntry
+		if !succeeded && payment.PaymentStatus == models.PaymentSucces {
+			entries = ChargeEntries(payment)
+		}
+
+		if err := s.paymentRepo.UpdateWithLedger(ctx, payment, entries); err != nil {
+			s.logger.Error("service.payment.update: failed to update payment", slog.Any("error", err))
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		s.logger.Error("service.payment.update: failed to get payment", slog.Any("error", err))
-		return nil, err
-	}
-
-	if req.Amount != nil {
-		payment.Amount = *req.Amount
-	}
-	if req.Currency != nil {
-		payment.Currency = *req.Currency
-	}
-	if req.PaidAt != nil {
-		payment.PaidAt = *req.PaidAt
-	}
-	if req.PaymentStatus != nil {
-		payment.PaymentStatus = *req.PaymentStatus
-	}
-	if req.Provider != nil {
-		payment.Provider = *req.Provider
-	}
-
-	if err := s.paymentRepo.Update(payment); err != nil {
-		s.logger.Error("service.payment.update: failed to update payment", slog.Any("error", err))
 		return nil, err
 	}
 
-	if err := s.paymentCache.Set(context.Background(), payment, time.Minute*10); err != nil {
+	if err := s.paymentCache.Set(afterCommit(ctx), id, payment, paymentCacheTTL); err != nil {
 		s.logger.Warn("service.payment.update: failed to update cache", slog.Any("error", err))
 	}
 
 	return payment, nil
 }
 
-func (s *paymentService) Delete(id string) error {
-	if err := s.paymentRepo.Delete(id); err != nil {
+func (s *paymentService) Delete(ctx context.Context, id string) error {
+	payment, err := s.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("service.payment.delete: failed to get payment", slog.Any("error", err))
+		return err
+	}
+
+	if payment.PaymentStatus == models.PaymentSucces {
+		return ErrPaymentImmutable
+	}
+
+	if err := s.paymentRepo.Delete(ctx, id); err != nil {
 		s.logger.Error("service.payment.delete: failed to delete payment", slog.Any("error", err))
 		return err
 	}
 
-	if err := s.paymentCache.Delete(context.Background(), id); err != nil {
+	if err := s.paymentCache.Delete(afterCommit(ctx), id); err != nil {
 		s.logger.Warn("service.payment.delete: failed to delete cache", slog.Any("error", err))
 	}
 
 	return nil
 }
+
+func paymentLedgerChanged(payment *models.Payment, req *dto.PaymentUpdateRequest) bool {
+	return (req.Amount != nil && *req.Amount != payment.Amount) ||
+		(req.Currency != nil && *req.Currency != payment.Currency) ||
+		(req.PaymentStatus != nil && *req.PaymentStatus != payment.PaymentStatus)
+}
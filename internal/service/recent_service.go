@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/cache"
+	"effective-project/internal/dto"
+	"effective-project/internal/query"
+	"effective-project/internal/repository"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecentlyViewedLimit — сколько последних просмотров хранится для пользователя
+const RecentlyViewedLimit = 50
+
+type RecentService interface {
+	// Track отмечает просмотр сервиса; ошибки хранилища только логируются,
+	// чтобы не мешать выдаче самого сервиса
+	Track(ctx context.Context, userID, serviceID uuid.UUID)
+
+	// List возвращает недавно просмотренные сервисы, последние первыми.
+	// Если хранилище просмотров недоступно, список пуст
+	List(ctx context.Context, userID uuid.UUID, limit int) ([]dto.ViewedService, error)
+
+	Clear(ctx context.Context, userID uuid.UUID) error
+}
+
+type recentService struct {
+	recent      cache.RecentlyViewed
+	serviceRepo repository.ServiceRepository
+	now         func() time.Time
+	logger      *slog.Logger
+}
+
+func NewRecentService(
+	recent cache.RecentlyViewed,
+	serviceRepo repository.ServiceRepository,
+	logger *slog.Logger,
+) RecentService {
+	return &recentService{
+		recent:      recent,
+		serviceRepo: serviceRepo,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+func (s *recentService) Track(ctx context.Context, userID, serviceID uuid.UUID) {
+	if err := s.recent.Add(ctx, userID, serviceID, s.now()); err != nil {
+		s.logger.Warn("service.recent.track: failed to save view", slog.Any("error", err))
+	}
+}
+
+func (s *recentService) List(ctx context.Context, userID uuid.UUID, limit int) ([]dto.ViewedService, error) {
+	// без хранилища история просто пуста: это не повод отвечать ошибкой
+	views, err := s.recent.List(ctx, userID, limit)
+	if err != nil {
+		s.logger.Warn("service.recent.list: failed to get views", slog.Any("error", err))
+		views = nil
+	}
+
+	result := make([]dto.ViewedService, 0, len(views))
+	if len(views) == 0 {
+		return result, nil
+	}
+
+	ids := make([]any, 0, len(views))
+	for _, v := range views {
+		ids = append(ids, v.ServiceID)
+	}
+
+	services, err := s.serviceRepo.List(ctx, query.New(query.Services, len(ids),
+		query.Filter{Field: "id", Op: query.In, Value: ids},
+	))
+	if err != nil {
+		s.logger.Error("service.recent.list: failed to get services", slog.Any("error", err))
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]int, len(services))
+	for i, svc := range services {
+		byID[svc.ID] = i
+	}
+
+	// удалённые сервисы пропускаются, порядок — по времени просмотра
+	for _, v := range views {
+		if i, ok := byID[v.ServiceID]; ok {
+			result = append(result, dto.ViewedService{Service: services[i], ViewedAt: v.ViewedAt})
+		}
+	}
+
+	return result, nil
+}
+
+func (s *recentService) Clear(ctx context.Context, userID uuid.UUID) error {
+	if err := s.recent.Clear(ctx, userID); err != nil {
+		s.logger.Error("service.recent.clear: failed to clear views", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
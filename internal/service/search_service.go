@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/repository"
+	"errors"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidSearchQuery = errors.New("поисковый запрос должен содержать от 2 до 100 символов")
+	ErrInvalidSearchType  = errors.New("тип поиска должен быть category или service")
+)
+
+const (
+	searchMinQueryLen = 2
+	searchMaxQueryLen = 100
+)
+
+type SearchService interface {
+	// Search ищет категории и сервисы по названию и сайту с учётом опечаток
+	Search(ctx context.Context, q dto.SearchQuery) ([]dto.SearchHit, error)
+}
+
+type searchService struct {
+	searchRepo repository.SearchRepository
+	logger     *slog.Logger
+}
+
+func NewSearchService(searchRepo repository.SearchRepository, logger *slog.Logger) SearchService {
+	return &searchService{
+		searchRepo: searchRepo,
+		logger:     logger,
+	}
+}
+
+func (s *searchService) Search(ctx context.Context, q dto.SearchQuery) ([]dto.SearchHit, error) {
+	q.Query = strings.Join(strings.Fields(q.Query), " ")
+
+	length := utf8.RuneCountInString(q.Query)
+	if length < searchMinQueryLen || length > searchMaxQueryLen {
+		return nil, ErrInvalidSearchQuery
+	}
+
+	if q.Type != "" && q.Type != dto.SearchTypeCategory && q.Type != dto.SearchTypeService {
+		return nil, ErrInvalidSearchType
+	}
+
+	hits, err := s.searchRepo.Search(ctx, q, prefixTsQuery(q.Query))
+	if err != nil {
+		s.logger.Error("service.search.search: failed to search", slog.Any("error", err))
+		return nil, err
+	}
+
+	if hits == nil {
+		hits = []dto.SearchHit{}
+	}
+
+	return hits, nil
+}
+
+// prefixTsQuery собирает tsquery из слов запроса: каждое слово ищется по
+// префиксу, чтобы недописанное название тоже находилось. Служебные символы
+// tsquery отбрасываются, поэтому пользовательский ввод не ломает запрос
+func prefixTsQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		terms = append(terms, w+":*")
+	}
+
+	return strings.Join(terms, " & ")
+}
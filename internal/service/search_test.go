@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchService_Search_BuildsPrefixQuery(t *testing.T) {
+	var (
+		gotQuery dto.SearchQuery
+		gotTs    string
+	)
+	repo := &mock.MockSearchRepository{
+		SearchFn: func(ctx context.Context, q dto.SearchQuery, tsQuery string) ([]dto.SearchHit, error) {
+			gotQuery, gotTs = q, tsQuery
+			return nil, nil
+		},
+	}
+	svc := NewSearchService(repo, newLogger())
+
+	hits, err := svc.Search(context.Background(), dto.SearchQuery{Query: "  Яндекс   Music!:* ", Limit: 20})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, hits)
+	assert.Empty(t, hits)
+	assert.Equal(t, "Яндекс Music!:*", gotQuery.Query)
+	assert.Equal(t, "яндекс:* & music:*", gotTs)
+}
+
+func TestSearchService_Search_InvalidQuery(t *testing.T) {
+	svc := NewSearchService(&mock.MockSearchRepository{}, newLogger())
+
+	_, err := svc.Search(context.Background(), dto.SearchQuery{Query: " a "})
+	assert.ErrorIs(t, err, ErrInvalidSearchQuery)
+
+	_, err = svc.Search(context.Background(), dto.SearchQuery{Query: "music", Type: "user"})
+	assert.ErrorIs(t, err, ErrInvalidSearchType)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/cache"
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+	"effective-project/internal/query"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// serviceStore — сервисы в памяти, список отдаётся в порядке добавления
+type serviceStore struct {
+	ids   []string
+	items map[string]models.Service
+	lists int
+}
+
+func (s *serviceStore) repo() *mock.MockServiceRepository {
+	return &mock.MockServiceRepository{
+		CreateFn: func(ctx context.Context, svc *models.Service) error {
+			svc.ID = uuid.New()
+			s.ids = append(s.ids, svc.ID.String())
+			s.items[svc.ID.String()] = *svc
+			return nil
+		},
+		ListFn: func(ctx context.Context, q *query.List) ([]models.Service, error) {
+			s.lists++
+			page := []models.Service{}
+			for _, id := range s.ids {
+				if svc, ok := s.items[id]; ok {
+					page = append(page, svc)
+				}
+			}
+			return page, nil
+		},
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			if svc, ok := s.items[id]; ok {
+				return &svc, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		UpdateFn: func(ctx context.Context, svc *models.Service) error {
+			s.items[svc.ID.String()] = *svc
+			return nil
+		},
+		DeleteFn: func(ctx context.Context, id string) error {
+			delete(s.items, id)
+			return nil
+		},
+	}
+}
+
+func newTaggedServiceService(services *serviceStore) ServiceService {
+	store := cache.NewLRUStore(100, 0)
+	opts := cache.Options{NotFound: gorm.ErrRecordNotFound, NegativeTTL: time.Minute}
+
+	return NewServiceService(
+		services.repo(),
+		cache.NewTagged(store, "service:", ServiceTags, opts),
+		cache.NewTagged(store, "services:page:", ServicePageTags, opts),
+		newLogger(),
+	)
+}
+
+func serviceNames(services []models.Service) []string {
+	result := make([]string, 0, len(services))
+	for _, svc := range services {
+		result = append(result, svc.Name)
+	}
+	return result
+}
+
+func TestServiceService_ListPagesAreCached(t *testing.T) {
+	services := &serviceStore{items: map[string]models.Service{}}
+	svc := newTaggedServiceService(services)
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, &dto.ServiceCreateRequest{Name: "Netflix"})
+	require.NoError(t, err)
+
+	for range 3 {
+		page, err := svc.List(ctx, query.New(query.Services, 20))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Netflix"}, serviceNames(page))
+	}
+	assert.Equal(t, 1, services.lists)
+}
+
+func TestServiceService_MutationsLeaveNoStalePages(t *testing.T) {
+	services := &serviceStore{items: map[string]models.Service{}}
+	svc := newTaggedServiceService(services)
+	ctx := context.Background()
+	list := func() []string {
+		page, err := svc.List(ctx, query.New(query.Services, 20))
+		require.NoError(t, err)
+		return serviceNames(page)
+	}
+
+	netflix, err := svc.Create(ctx, &dto.ServiceCreateRequest{Name: "Netflix"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Netflix"}, list())
+
+	_, err = svc.Create(ctx, &dto.ServiceCreateRequest{Name: "Spotify"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Netflix", "Spotify"}, list())
+
+	id := netflix.ID.String()
+	_, err = svc.GetByID(ctx, id)
+	require.NoError(t, err)
+
+	name := "Netflix Premium"
+	_, err = svc.Update(ctx, id, &dto.ServiceUpdateRequest{Name: &name})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Netflix Premium", "Spotify"}, list())
+	got, err := svc.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Netflix Premium", got.Name)
+
+	require.NoError(t, svc.Delete(ctx, id))
+
+	assert.Equal(t, []string{"Spotify"}, list())
+	_, err = svc.GetByID(ctx, id)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestServiceService_Update_InvalidatesAfterClientGone(t *testing.T) {
+	services := &serviceStore{items: map[string]models.Service{}}
+	repo := services.repo()
+	created := &models.Service{Name: "Okko"}
+	require.NoError(t, repo.Create(context.Background(), created))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	update := repo.UpdateFn
+	repo.UpdateFn = func(ctx context.Context, svc *models.Service) error {
+		// клиент отключился, пока запись сохранялась
+		defer cancel()
+		return update(ctx, svc)
+	}
+
+	var invalidated []error
+	items := &mock.MockCache[*models.Service]{
+		InvalidateFn: func(ctx context.Context, tags ...string) error {
+			invalidated = append(invalidated, ctx.Err())
+			return nil
+		},
+	}
+	svc := NewServiceService(repo, items, &mock.MockCache[[]models.Service]{}, newLogger())
+
+	name := "Okko HD"
+	_, err := svc.Update(ctx, created.ID.String(), &dto.ServiceUpdateRequest{Name: &name})
+
+	require.NoError(t, err)
+	assert.Equal(t, []error{nil}, invalidated)
+	assert.Equal(t, "Okko HD", services.items[created.ID.String()].Name)
+}
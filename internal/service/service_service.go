@@ -5,72 +5,99 @@ import (
 	"effective-project/internal/cache"
 	"effective-project/internal/dto"
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"effective-project/internal/repository"
-	"fmt"
 	"log/slog"
 	"time"
-
-	"github.com/google/uuid"
 )
 
 type ServiceService interface {
-	Create(req *dto.ServiceCreateRequest) (*models.Service, error)
+	Create(ctx context.Context, req *dto.ServiceCreateRequest) (*models.Service, error)
+
+	List(ctx context.Context, q *query.List) ([]models.Service, error)
+
+	GetByID(ctx context.Context, id string) (*models.Service, error)
 
-	List(
-		ctx context.Context,
-		limit int,
-		lastCreatedAt *time.Time,
-		lastID *uuid.UUID) ([]models.Service, error)
+	Update(ctx context.Context, id string, service *dto.ServiceUpdateRequest) (*models.Service, error)
+
+	Delete(ctx context.Context, id string) error
+}
 
-	GetByID(id string) (*models.Service, error)
+// serviceCacheTTL — срок жизни сервиса в кеше
+const serviceCacheTTL = 5 * time.Minute
 
-	Update(id string, service *dto.ServiceUpdateRequest) (*models.Service, error)
+// servicesTag помечает все страницы списка сервисов: новый или изменённый
+// сервис может попасть на любую из них
+const servicesTag = cache.TagServices
+
+// serviceTag — тег записей кеша, в которых есть сервис: его самого и страниц с ним
+func serviceTag(id string) string {
+	return cache.TagService + id
+}
+
+// ServiceTags привязывает сервис в кеше к его тегу
+func ServiceTags(key string, service *models.Service) []string {
+	return []string{serviceTag(service.ID.String())}
+}
 
-	Delete(id string) error
+// ServicePageTags привязывает страницу списка к общему тегу и к тегу каждого сервиса на ней
+func ServicePageTags(key string, page []models.Service) []string {
+	tags := make([]string, 0, len(page)+1)
+	tags = append(tags, servicesTag)
+	for _, service := range page {
+		tags = append(tags, serviceTag(service.ID.String()))
+	}
+	return tags
 }
 
 type serviceService struct {
 	serviceRepo repository.ServiceRepository
-	cache       cache.Cache
+	cache       cache.Cache[*models.Service]
+	listCache   cache.Cache[[]models.Service]
 	logger      *slog.Logger
 }
 
 func NewServiceService(
 	serviceRepo repository.ServiceRepository,
-	cache cache.Cache,
+	cache cache.Cache[*models.Service],
+	listCache cache.Cache[[]models.Service],
 	logger *slog.Logger,
 ) ServiceService {
 	return &serviceService{
 		serviceRepo: serviceRepo,
 		cache:       cache,
+		listCache:   listCache,
 		logger:      logger,
 	}
 }
 
-func (s *serviceService) Create(req *dto.ServiceCreateRequest) (*models.Service, error) {
+func (s *serviceService) Create(ctx context.Context, req *dto.ServiceCreateRequest) (*models.Service, error) {
 	service := &models.Service{
-		Name:       req.Name,
-		CategoryID: req.CategoryID,
-		LogoUrl:    req.LogoUrl,
-		Website:    req.Website,
+		Name:        req.Name,
+		Description: req.Description,
+		CategoryID:  req.CategoryID,
+		Price:       req.Price,
+		LogoUrl:     req.LogoUrl,
+		Website:     req.Website,
 	}
 
-	if err := s.serviceRepo.Create(service); err != nil {
+	if err := s.serviceRepo.Create(ctx, service); err != nil {
 		s.logger.Error("service.service.create: failed to create service", slog.Any("error", err))
 		return nil, err
 	}
 
-	_ = s.cache.Set(context.Background(), fmt.Sprintf("service:%s", service.ID.String()), service)
+	if err := s.cache.Invalidate(afterCommit(ctx), servicesTag); err != nil {
+		s.logger.Warn("service.service.create: failed to invalidate list cache", slog.Any("error", err))
+	}
+	_ = s.cache.Set(afterCommit(ctx), service.ID.String(), service, serviceCacheTTL)
 
 	return service, nil
 }
 
-func (s *serviceService) List(
-	ctx context.Context,
-	limit int,
-	lastCreatedAt *time.Time,
-	lastID *uuid.UUID) ([]models.Service, error) {
-	services, err := s.serviceRepo.List(ctx, limit, lastCreatedAt, lastID)
+func (s *serviceService) List(ctx context.Context, q *query.List) ([]models.Service, error) {
+	services, err := s.listCache.GetOrLoad(ctx, q.Key(), func(ctx context.Context) ([]models.Service, error) {
+		return s.serviceRepo.List(ctx, q)
+	}, serviceCacheTTL)
 	if err != nil {
 		s.logger.Error("service.service.get_all: failed to get services", slog.Any("error", err))
 		return nil, err
@@ -79,27 +106,20 @@ func (s *serviceService) List(
 	return services, nil
 }
 
-func (s *serviceService) GetByID(id string) (*models.Service, error) {
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("service:%s", id)
-
-	var cached models.Service
-	if ok, err := s.cache.Get(ctx, cacheKey, &cached); err == nil && ok {
-		return &cached, nil
-	}
-
-	service, err := s.serviceRepo.GetByID(id)
+func (s *serviceService) GetByID(ctx context.Context, id string) (*models.Service, error) {
+	service, err := s.cache.GetOrLoad(ctx, id, func(ctx context.Context) (*models.Service, error) {
+		return s.serviceRepo.GetByID(ctx, id)
+	}, serviceCacheTTL)
 	if err != nil {
 		s.logger.Error("service.service.get_by_id: failed to get service", slog.Any("error", err))
 		return nil, err
 	}
 
-	_ = s.cache.Set(ctx, cacheKey, service)
 	return service, nil
 }
 
-func (s *serviceService) Update(id string, req *dto.ServiceUpdateRequest) (*models.Service, error) {
-	service, err := s.serviceRepo.GetByID(id)
+func (s *serviceService) Update(ctx context.Context, id string, req *dto.ServiceUpdateRequest) (*models.Service, error) {
+	service, err := s.serviceRepo.GetByID(ctx, id)
 	if err != nil {
 		s.logger.Error("service.service.update: failed to get service", slog.Any("error", err))
 		return nil, err
@@ -108,6 +128,12 @@ func (s *serviceService) Update(id string, req *dto.ServiceUpdateRequest) (*mode
 	if req.Name != nil {
 		service.Name = *req.Name
 	}
+	if req.Description != nil {
+		service.Description = *req.Description
+	}
+	if req.Price != nil {
+		service.Price = *req.Price
+	}
 	if req.LogoUrl != nil {
 		service.LogoUrl = *req.LogoUrl
 	}
@@ -115,23 +141,28 @@ func (s *serviceService) Update(id string, req *dto.ServiceUpdateRequest) (*mode
 		service.Website = *req.Website
 	}
 
-	if err := s.serviceRepo.Update(service); err != nil {
+	if err := s.serviceRepo.Update(ctx, service); err != nil {
 		s.logger.Error("service.service.update: failed to update service", slog.Any("error", err))
 		return nil, err
 	}
 
-	_ = s.cache.Delete(context.Background(), fmt.Sprintf("service:%s", id))
+	// изменённый сервис может сменить место в сортировке, поэтому сбрасываются все страницы
+	if err := s.cache.Invalidate(afterCommit(ctx), serviceTag(id), servicesTag); err != nil {
+		s.logger.Warn("service.service.update: failed to invalidate cache", slog.Any("error", err))
+	}
 
 	return service, nil
 }
 
-func (s *serviceService) Delete(id string) error {
-	if err := s.serviceRepo.Delete(id); err != nil {
+func (s *serviceService) Delete(ctx context.Context, id string) error {
+	if err := s.serviceRepo.Delete(ctx, id); err != nil {
 		s.logger.Error("service.service.delete: failed to delete service", slog.Any("error", err))
 		return err
 	}
 
-	_ = s.cache.Delete(context.Background(), fmt.Sprintf("service:%s", id))
+	if err := s.cache.Invalidate(afterCommit(ctx), serviceTag(id)); err != nil {
+		s.logger.Warn("service.service.delete: failed to invalidate cache", slog.Any("error", err))
+	}
 
 	return nil
 }
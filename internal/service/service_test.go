@@ -5,10 +5,11 @@ import (
 	"effective-project/internal/dto"
 	"effective-project/internal/mock"
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	service "effective-project/internal/service"
 	"errors"
+	"log/slog"
 	"testing"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -17,21 +18,21 @@ import (
 func TestUserService_Create(t *testing.T) {
 	called := false
 	repo := &mock.MockUserRepository{
-		CreateFn: func(u *models.User) error {
+		CreateFn: func(ctx context.Context, u *models.User) error {
 			called = true
 			return nil
 		},
 	}
 
-	cache := &mock.MockUserCache{}
-	svc := service.NewUserService(repo, cache, nil)
+	cache := &mock.MockCache[*models.User]{}
+	svc := service.NewUserService(repo, cache, &mock.MockCache[*models.User]{}, slog.New(slog.DiscardHandler))
 
 	req := &dto.UserCreateRequest{
 		Email:    "test@example.com",
 		Password: "pass",
 	}
 
-	created, err := svc.Create(req)
+	created, err := svc.Create(context.Background(), req)
 
 	assert.NoError(t, err)
 	assert.True(t, called)
@@ -42,14 +43,14 @@ func TestUserService_GetByID_Success(t *testing.T) {
 	user := &models.User{Base: models.Base{ID: uuid.New()}, Email: "a@mail.com"}
 
 	repo := &mock.MockUserRepository{
-		GetByIDFn: func(id string) (*models.User, error) {
+		GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
 			return user, nil
 		},
 	}
-	cache := &mock.MockUserCache{}
-	svc := service.NewUserService(repo, cache, nil)
+	cache := &mock.MockCache[*models.User]{}
+	svc := service.NewUserService(repo, cache, &mock.MockCache[*models.User]{}, slog.New(slog.DiscardHandler))
 
-	got, err := svc.GetByID(user.ID.String())
+	got, err := svc.GetByID(context.Background(), user.ID.String())
 
 	assert.NoError(t, err)
 	assert.Equal(t, user, got)
@@ -57,13 +58,13 @@ func TestUserService_GetByID_Success(t *testing.T) {
 
 func TestUserService_GetByID_NotFound(t *testing.T) {
 	repo := &mock.MockUserRepository{
-		GetByIDFn: func(id string) (*models.User, error) {
+		GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
 			return nil, errors.New("not found")
 		},
 	}
-	svc := service.NewUserService(repo, nil, nil)
+	svc := service.NewUserService(repo, &mock.MockCache[*models.User]{}, &mock.MockCache[*models.User]{}, slog.New(slog.DiscardHandler))
 
-	got, err := svc.GetByID("1")
+	got, err := svc.GetByID(context.Background(), "1")
 	assert.Error(t, err)
 	assert.Nil(t, got)
 }
@@ -71,25 +72,25 @@ func TestUserService_GetByID_NotFound(t *testing.T) {
 func TestUserService_Delete(t *testing.T) {
 	deleted := false
 	repo := &mock.MockUserRepository{
-		GetByIDFn: func(id string) (*models.User, error) {
+		GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
 			return &models.User{Base: models.Base{ID: uuid.New()}}, nil
 		},
-		DeleteFn: func(id string) error {
+		DeleteFn: func(ctx context.Context, id string) error {
 			deleted = true
 			return nil
 		},
 	}
 
 	cacheDeleted := false
-	cache := &mock.MockUserCache{
-		DeleteByIDFn: func(ctx context.Context, id string) error {
+	cache := &mock.MockCache[*models.User]{
+		InvalidateFn: func(ctx context.Context, tags ...string) error {
 			cacheDeleted = true
 			return nil
 		},
 	}
 
-	svc := service.NewUserService(repo, cache, nil)
-	err := svc.Delete("1")
+	svc := service.NewUserService(repo, cache, &mock.MockCache[*models.User]{}, slog.New(slog.DiscardHandler))
+	err := svc.Delete(context.Background(), "1")
 
 	assert.NoError(t, err)
 	assert.True(t, deleted)
@@ -98,13 +99,13 @@ func TestUserService_Delete(t *testing.T) {
 
 func TestUserService_List(t *testing.T) {
 	repo := &mock.MockUserRepository{
-		ListFn: func(ctx context.Context, limit int, lastCreatedAt *time.Time, lastID *uuid.UUID) ([]models.User, error) {
+		ListFn: func(ctx context.Context, q *query.List) ([]models.User, error) {
 			return []models.User{{Base: models.Base{ID: uuid.New()}}, {Base: models.Base{ID: uuid.New()}}}, nil
 		},
 	}
 
-	svc := service.NewUserService(repo, nil, nil)
-	list, err := svc.List(context.Background(), 10, nil, nil)
+	svc := service.NewUserService(repo, &mock.MockCache[*models.User]{}, &mock.MockCache[*models.User]{}, slog.New(slog.DiscardHandler))
+	list, err := svc.List(context.Background(), &query.List{Limit: 10})
 
 	assert.NoError(t, err)
 	assert.Len(t, list, 2)
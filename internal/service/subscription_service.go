@@ -5,6 +5,7 @@ import (
 	"effective-project/internal/cache"
 	"effective-project/internal/dto"
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"effective-project/internal/repository"
 	"log/slog"
 	"time"
@@ -13,19 +14,15 @@ import (
 )
 
 type SubscriptionService interface {
-	Create(req *dto.SubscriptionCreateRequest) (*models.Subscription, error)
+	Create(ctx context.Context, req *dto.SubscriptionCreateRequest) (*models.Subscription, error)
 
-	List(
-		ctx context.Context,
-		limit int,
-		lastCreatedAt *time.Time,
-		lastID *uuid.UUID) ([]dto.SubscriptionResponse, error)
+	List(ctx context.Context, q *query.List) ([]dto.SubscriptionResponse, error)
 
-	GetByID(id string) (*dto.SubscriptionResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.SubscriptionResponse, error)
 
-	Update(id string, req *dto.SubscriptionUpdateRequest) (*models.Subscription, error)
+	Update(ctx context.Context, id string, req *dto.SubscriptionUpdateRequest) (*models.Subscription, error)
 
-	Delete(id string) error
+	Delete(ctx context.Context, id string) error
 
 	CalculateTotal(
 		ctx context.Context,
@@ -33,12 +30,18 @@ type SubscriptionService interface {
 	) (int, error)
 }
 
+// subscriptionCacheTTL — срок жизни подписки в кеше
+const subscriptionCacheTTL = 10 * time.Minute
+
 type subscriptionService struct {
 	subscriptionRepo repository.SubscriptionRepository
 	serviceRepo      repository.ServiceRepository
 	paymentRepo      repository.PaymentRepository
+	txManager        repository.TxManager
+	coupons          CouponService
+	taxes            taxRecorder
 
-	subscriptionCache cache.SubscriptionCache
+	subscriptionCache cache.Cache[*dto.SubscriptionResponse]
 	logger            *slog.Logger
 }
 
@@ -46,19 +49,29 @@ func NewSubscriptionService(
 	subscriptionRepo repository.SubscriptionRepository,
 	serviceRepo repository.ServiceRepository,
 	paymentRepo repository.PaymentRepository,
-	subscriptionCache cache.SubscriptionCache,
+	txManager repository.TxManager,
+	coupons CouponService,
+	taxes taxRecorder,
+	subscriptionCache cache.Cache[*dto.SubscriptionResponse],
 	logger *slog.Logger,
 ) SubscriptionService {
 	return &subscriptionService{
 		subscriptionRepo:  subscriptionRepo,
 		serviceRepo:       serviceRepo,
 		paymentRepo:       paymentRepo,
+		txManager:         txManager,
+		coupons:           coupons,
+		taxes:             taxes,
 		subscriptionCache: subscriptionCache,
 		logger:            logger,
 	}
 }
 
-func (s *subscriptionService) Create(req *dto.SubscriptionCreateRequest) (*models.Subscription, error) {
+func (s *subscriptionService) Create(ctx context.Context, req *dto.SubscriptionCreateRequest) (*models.Subscription, error) {
+	interval := req.Interval
+	if interval == "" {
+		interval = models.IntervalMonth
+	}
 
 	var subscription = &models.Subscription{
 		UserID:    req.UserID,
@@ -66,23 +79,62 @@ func (s *subscriptionService) Create(req *dto.SubscriptionCreateRequest) (*model
 		StartDate: req.StartDate,
 		EndDate:   req.EndDate,
 		Price:     req.Price,
-	}
+		ListPrice: req.Price,
+		Interval:  interval,
+		Status:    models.SubscriptionActive,
+	}
+
+	// погашение купона, подписка и налог сохраняются вместе: ошибка любого шага
+	// откатывает всё оформление
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if req.CouponCode != "" {
+			// ID нужен заранее, чтобы погашение купона ссылалось на подписку
+			subscription.ID = uuid.New()
+
+			snapshot, err := s.coupons.Apply(ctx, dto.CouponApplication{
+				Code:           req.CouponCode,
+				UserID:         req.UserID,
+				ServiceID:      req.ServiceID,
+				Amount:         req.Price,
+				Currency:       models.DefaultCurrency,
+				SubscriptionID: &subscription.ID,
+			})
+			if err != nil {
+				s.logger.Warn("service.subscription.create: failed to apply coupon", slog.Any("error", err))
+				return err
+			}
+
+			subscription.Price = snapshot.Price
+			subscription.Discount = snapshot.Discount
+			subscription.CouponID = snapshot.CouponID
+		}
+
+		if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
+			s.logger.Error("service.subscription.create: failed to create subscription", slog.Any("error", err))
+			return err
+		}
 
-	if err := s.subscriptionRepo.Create(subscription); err != nil {
-		s.logger.Error("service.subscription.create: failed to create subscription", slog.Any("error", err))
+		if err := s.taxes.Record(ctx, dto.TaxableItem{
+			UserID:         subscription.UserID,
+			Amount:         subscription.Price,
+			SubscriptionID: &subscription.ID,
+		}); err != nil {
+			s.logger.Error("service.subscription.create: failed to record tax", slog.Any("error", err))
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return subscription, nil
 }
 
-func (s *subscriptionService) List(
-	ctx context.Context,
-	limit int,
-	lastCreatedAt *time.Time,
-	lastID *uuid.UUID) ([]dto.SubscriptionResponse, error) {
+func (s *subscriptionService) List(ctx context.Context, q *query.List) ([]dto.SubscriptionResponse, error) {
 
-	subscriptions, err := s.subscriptionRepo.List(ctx, limit, lastCreatedAt, lastID)
+	subscriptions, err := s.subscriptionRepo.List(ctx, q)
 	if err != nil {
 		s.logger.Error("service.subscription.get_all: failed to get subscriptions", slog.Any("error", err))
 		return nil, err
@@ -91,23 +143,10 @@ func (s *subscriptionService) List(
 	return subscriptions, nil
 }
 
-func (s *subscriptionService) GetByID(id string) (*dto.SubscriptionResponse, error) {
-	ctx := context.Background()
-
-	// 1. Пытаемся взять из кеша (модель)
-	if sub, err := s.subscriptionCache.GetByID(ctx, id); err == nil {
-		return &dto.SubscriptionResponse{
-			ID:        sub.ID,
-			ServiceID: sub.ServiceID,
-			StartDate: sub.StartDate,
-			EndDate:   sub.EndDate,
-			Price:     sub.Price,
-			CreatedAt: sub.CreatedAt,
-		}, nil
-	}
-
-	// 2. Берём из репозитория (DTO)
-	subscription, err := s.subscriptionRepo.GetByID(id)
+func (s *subscriptionService) GetByID(ctx context.Context, id string) (*dto.SubscriptionResponse, error) {
+	subscription, err := s.subscriptionCache.GetOrLoad(ctx, id, func(ctx context.Context) (*dto.SubscriptionResponse, error) {
+		return s.subscriptionRepo.GetByID(ctx, id)
+	}, subscriptionCacheTTL)
 	if err != nil {
 		s.logger.Error(
 			"service.subscription.get_by_id: failed to get subscription",
@@ -116,22 +155,11 @@ func (s *subscriptionService) GetByID(id string) (*dto.SubscriptionResponse, err
 		return nil, err
 	}
 
-	// 3. Кладём в кеш модель
-	model, err := s.subscriptionRepo.GetModelByID(id)
-	if err == nil {
-		if err := s.subscriptionCache.Set(ctx, model, time.Minute*10); err != nil {
-			s.logger.Warn(
-				"service.subscription.get_by_id: failed to set cache",
-				slog.Any("error", err),
-			)
-		}
-	}
-
 	return subscription, nil
 }
 
-func (s *subscriptionService) Update(id string, req *dto.SubscriptionUpdateRequest) (*models.Subscription, error) {
-	subscription, err := s.subscriptionRepo.GetModelByID(id)
+func (s *subscriptionService) Update(ctx context.Context, id string, req *dto.SubscriptionUpdateRequest) (*models.Subscription, error) {
+	subscription, err := s.subscriptionRepo.GetModelByID(ctx, id)
 	if err != nil {
 		s.logger.Error("service.subscription.update: failed to get subscription", slog.Any("error", err))
 		return nil, err
@@ -146,25 +174,33 @@ func (s *subscriptionService) Update(id string, req *dto.SubscriptionUpdateReque
 	if req.Price != nil {
 		subscription.Price = *req.Price
 	}
+	if req.Interval != nil {
+		subscription.Interval = *req.Interval
+	}
+	if req.PausedUntil != nil {
+		subscription.PausedUntil = req.PausedUntil
+	}
+	if req.Resume {
+		subscription.PausedUntil = nil
+	}
 
-	if err := s.subscriptionRepo.Update(subscription); err != nil {
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
 		s.logger.Error("service.subscription.update: failed to update subscription", slog.Any("error", err))
 		return nil, err
 	}
 
-	ctx := context.Background()
-	_ = s.subscriptionCache.DeleteByID(ctx, id)
+	_ = s.subscriptionCache.Delete(afterCommit(ctx), id)
 
 	return subscription, nil
 }
 
-func (s *subscriptionService) Delete(id string) error {
-	if err := s.subscriptionRepo.Delete(id); err != nil {
+func (s *subscriptionService) Delete(ctx context.Context, id string) error {
+	if err := s.subscriptionRepo.Delete(ctx, id); err != nil {
 		s.logger.Error("service.subscription.delete: failed to cancel subscription", slog.Any("error", err))
 		return err
 	}
 
-	_ = s.subscriptionCache.DeleteByID(context.Background(), id)
+	_ = s.subscriptionCache.Delete(afterCommit(ctx), id)
 
 	return nil
 }
@@ -181,43 +217,20 @@ func (s *subscriptionService) CalculateTotal(
 
 	total := 0
 	for _, row := range rows {
-		months := countMonths(
-			row.StartDate,
-			row.EndDate,
-			f.From,
-			f.To,
-		)
-		total += months * row.Price
+		total += countCharges(row, f.From, f.To) * row.Price
 	}
 
 	return total, nil
 }
 
-func countMonths(
-	subStart time.Time,
-	subEnd *time.Time,
-	periodFrom time.Time,
-	periodTo time.Time,
-) int {
-
-	start := maxDate(subStart, periodFrom)
-
-	end := periodTo
-	if subEnd != nil && subEnd.Before(end) {
-		end = *subEnd
-	}
-
-	if start.After(end) {
-		return 0
-	}
-
-	return (end.Year()-start.Year())*12 +
-		int(end.Month()-start.Month()) + 1
-}
-
-func maxDate(a, b time.Time) time.Time {
-	if a.After(b) {
-		return a
-	}
-	return b
+// countCharges считает списания подписки за месяцы с from по to включительно
+// по тому же расписанию, что и календарь: годовая подписка списывается раз
+// в год, а до paused_until списаний нет
+func countCharges(row dto.SubscriptionRow, from, to time.Time) int {
+	return len(chargeDates(dto.UpcomingSubscriptionRow{
+		StartDate:   row.StartDate,
+		EndDate:     row.EndDate,
+		PausedUntil: row.PausedUntil,
+		Interval:    row.Interval,
+	}, startOfMonth(from), startOfMonth(to).AddDate(0, 1, 0)))
 }
@@ -5,8 +5,10 @@ import (
 	"effective-project/internal/dto"
 	"effective-project/internal/mock"
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	service "effective-project/internal/service"
 	"errors"
+	"log/slog"
 	"testing"
 	"time"
 
@@ -25,13 +27,13 @@ func timePtr(t time.Time) *time.Time {
 func TestSubscriptionService_Create(t *testing.T) {
 	called := false
 	repo := &mock.MockSubscriptionRepository{
-		CreateFn: func(s *models.Subscription) error {
+		CreateFn: func(ctx context.Context, s *models.Subscription) error {
 			called = true
 			return nil
 		},
 	}
 
-	svc := service.NewSubscriptionService(repo, nil, nil, nil, nil)
+	svc := service.NewSubscriptionService(repo, nil, nil, &mock.MockTxManager{}, nil, &mock.MockTaxRecorder{}, &mock.MockCache[*dto.SubscriptionResponse]{}, slog.New(slog.DiscardHandler))
 
 	req := &dto.SubscriptionCreateRequest{
 		UserID:    uuid.New(),
@@ -41,7 +43,7 @@ func TestSubscriptionService_Create(t *testing.T) {
 		Price:     100,
 	}
 
-	created, err := svc.Create(req)
+	created, err := svc.Create(context.Background(), req)
 
 	assert.NoError(t, err)
 	assert.True(t, called)
@@ -50,7 +52,7 @@ func TestSubscriptionService_Create(t *testing.T) {
 
 func TestSubscriptionService_GetByID_Success(t *testing.T) {
 	repo := &mock.MockSubscriptionRepository{
-		GetByIDFn: func(id string) (*dto.SubscriptionResponse, error) {
+		GetByIDFn: func(ctx context.Context, id string) (*dto.SubscriptionResponse, error) {
 			return &dto.SubscriptionResponse{
 				ID:        uuid.New(),
 				ServiceID: uuid.New(),
@@ -60,15 +62,15 @@ func TestSubscriptionService_GetByID_Success(t *testing.T) {
 				CreatedAt: time.Now(),
 			}, nil
 		},
-		GetModelByIDFn: func(id string) (*models.Subscription, error) {
+		GetModelByIDFn: func(ctx context.Context, id string) (*models.Subscription, error) {
 			return &models.Subscription{Base: models.Base{ID: uuid.New()}, ServiceID: uuid.New()}, nil
 		},
 	}
 
-	svc := service.NewSubscriptionService(repo, nil, nil, nil, nil)
+	svc := service.NewSubscriptionService(repo, nil, nil, &mock.MockTxManager{}, nil, &mock.MockTaxRecorder{}, &mock.MockCache[*dto.SubscriptionResponse]{}, slog.New(slog.DiscardHandler))
 
 	id := uuid.New()
-	sub, err := svc.GetByID(id.String())
+	sub, err := svc.GetByID(context.Background(), id.String())
 
 	assert.NoError(t, err)
 	assert.NotNil(t, sub)
@@ -76,15 +78,15 @@ func TestSubscriptionService_GetByID_Success(t *testing.T) {
 
 func TestSubscriptionService_GetByID_NotFound(t *testing.T) {
 	repo := &mock.MockSubscriptionRepository{
-		GetByIDFn: func(id string) (*dto.SubscriptionResponse, error) {
+		GetByIDFn: func(ctx context.Context, id string) (*dto.SubscriptionResponse, error) {
 			return nil, errors.New("not found")
 		},
 	}
 
-	svc := service.NewSubscriptionService(repo, nil, nil, nil, nil)
+	svc := service.NewSubscriptionService(repo, nil, nil, &mock.MockTxManager{}, nil, &mock.MockTaxRecorder{}, &mock.MockCache[*dto.SubscriptionResponse]{}, slog.New(slog.DiscardHandler))
 
 	id := uuid.New()
-	sub, err := svc.GetByID(id.String())
+	sub, err := svc.GetByID(context.Background(), id.String())
 
 	assert.Error(t, err)
 	assert.Nil(t, sub)
@@ -93,16 +95,16 @@ func TestSubscriptionService_GetByID_NotFound(t *testing.T) {
 func TestSubscriptionService_Delete(t *testing.T) {
 	called := false
 	repo := &mock.MockSubscriptionRepository{
-		DeleteFn: func(id string) error {
+		DeleteFn: func(ctx context.Context, id string) error {
 			called = true
 			return nil
 		},
 	}
 
-	svc := service.NewSubscriptionService(repo, nil, nil, nil, nil)
+	svc := service.NewSubscriptionService(repo, nil, nil, &mock.MockTxManager{}, nil, &mock.MockTaxRecorder{}, &mock.MockCache[*dto.SubscriptionResponse]{}, slog.New(slog.DiscardHandler))
 
 	id := uuid.New()
-	err := svc.Delete(id.String())
+	err := svc.Delete(context.Background(), id.String())
 
 	assert.NoError(t, err)
 	assert.True(t, called)
@@ -110,15 +112,40 @@ func TestSubscriptionService_Delete(t *testing.T) {
 
 func TestSubscriptionService_List(t *testing.T) {
 	repo := &mock.MockSubscriptionRepository{
-		ListFn: func(ctx context.Context, limit int, lastCreatedAt *time.Time, lastID *uuid.UUID) ([]dto.SubscriptionResponse, error) {
+		ListFn: func(ctx context.Context, q *query.List) ([]dto.SubscriptionResponse, error) {
 			return []dto.SubscriptionResponse{{ID: uuid.New()}, {ID: uuid.New()}}, nil
 		},
 	}
 
-	svc := service.NewSubscriptionService(repo, nil, nil, nil, nil)
+	svc := service.NewSubscriptionService(repo, nil, nil, &mock.MockTxManager{}, nil, &mock.MockTaxRecorder{}, &mock.MockCache[*dto.SubscriptionResponse]{}, slog.New(slog.DiscardHandler))
 
-	list, err := svc.List(context.Background(), 10, nil, nil)
+	list, err := svc.List(context.Background(), &query.List{Limit: 10})
 
 	assert.NoError(t, err)
 	assert.Len(t, list, 2)
 }
+
+func TestSubscriptionService_CalculateTotal_YearlyAndPaused(t *testing.T) {
+	repo := &mock.MockSubscriptionRepository{
+		FindForTotalFn: func(ctx context.Context, f dto.TotalFilter) ([]dto.SubscriptionRow, error) {
+			return []dto.SubscriptionRow{
+				// январь-июнь: шесть списаний
+				{StartDate: time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC), Price: 100, Interval: models.IntervalMonth},
+				// годовая: одно списание в марте
+				{StartDate: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), Price: 1200, Interval: models.IntervalYear},
+				// на паузе до апреля: апрель, май, июнь
+				{StartDate: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), Price: 10, Interval: models.IntervalMonth, PausedUntil: timePtr(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC))},
+			}, nil
+		},
+	}
+
+	svc := service.NewSubscriptionService(repo, nil, nil, &mock.MockTxManager{}, nil, &mock.MockTaxRecorder{}, &mock.MockCache[*dto.SubscriptionResponse]{}, slog.New(slog.DiscardHandler))
+
+	total, err := svc.CalculateTotal(context.Background(), dto.TotalFilter{
+		From: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6*100+1200+3*10, total)
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/repository"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTaxRuleNotFound  = errors.New("налоговое правило не найдено")
+	ErrInvalidTaxRule   = errors.New("некорректные параметры налогового правила")
+	ErrInvalidTaxPeriod = errors.New("период отчёта должен быть month, quarter или year")
+	ErrNoBillingAddress = errors.New("у пользователя не указан платёжный адрес")
+)
+
+// Периоды группировки налогового отчёта
+var taxReportPeriods = map[string]bool{"month": true, "quarter": true, "year": true}
+
+// taxRecorder — часть TaxService, через которую подписки и заказы начисляют
+// налог в транзакции оформления
+type taxRecorder interface {
+	Record(ctx context.Context, item dto.TaxableItem) error
+}
+
+type TaxService interface {
+	CreateRule(ctx context.Context, req *dto.TaxRuleCreateRequest) (*models.TaxRule, error)
+
+	ListRules(ctx context.Context, country string) ([]models.TaxRule, error)
+
+	GetRule(ctx context.Context, id string) (*models.TaxRule, error)
+
+	UpdateRule(ctx context.Context, id string, req *dto.TaxRuleUpdateRequest) (*models.TaxRule, error)
+
+	DeleteRule(ctx context.Context, id string) error
+
+	// Calculate считает налоги для суммы покупки по платёжному адресу
+	Calculate(ctx context.Context, address models.BillingAddress, amount int, at time.Time) (*dto.TaxCalculation, error)
+
+	// Quote считает налоги по платёжному адресу пользователя
+	Quote(ctx context.Context, userID uuid.UUID, amount int) (*dto.TaxCalculation, error)
+
+	// Record сохраняет строки налога для оформленного заказа или подписки
+	Record(ctx context.Context, item dto.TaxableItem) error
+
+	Report(ctx context.Context, f dto.TaxReportFilter) ([]dto.TaxReportRow, error)
+}
+
+type taxService struct {
+	taxRepo  repository.TaxRepository
+	userRepo repository.UserRepository
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+func NewTaxService(
+	taxRepo repository.TaxRepository,
+	userRepo repository.UserRepository,
+	logger *slog.Logger,
+) TaxService {
+	return &taxService{
+		taxRepo:  taxRepo,
+		userRepo: userRepo,
+		now:      time.Now,
+		logger:   logger,
+	}
+}
+
+func (s *taxService) CreateRule(ctx context.Context, req *dto.TaxRuleCreateRequest) (*models.TaxRule, error) {
+	rule := &models.TaxRule{
+		Country:       strings.ToUpper(req.Country),
+		Region:        req.Region,
+		Name:          req.Name,
+		Rate:          req.Rate,
+		Inclusive:     req.Inclusive,
+		EffectiveFrom: req.EffectiveFrom,
+		EffectiveTo:   req.EffectiveTo,
+	}
+
+	if err := validateTaxRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.taxRepo.CreateRule(ctx, rule); err != nil {
+		s.logger.Error("service.tax.create_rule: failed to create rule", slog.Any("error", err))
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *taxService) ListRules(ctx context.Context, country string) ([]models.TaxRule, error) {
+	rules, err := s.taxRepo.ListRules(ctx, strings.ToUpper(country))
+	if err != nil {
+		s.logger.Error("service.tax.list_rules: failed to get rules", slog.Any("error", err))
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (s *taxService) GetRule(ctx context.Context, id string) (*models.TaxRule, error) {
+	rule, err := s.taxRepo.GetRuleByID(ctx, id)
+	if err != nil {
+		s.logger.Error("service.tax.get_rule: failed to get rule", slog.Any("error", err))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaxRuleNotFound
+		}
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *taxService) UpdateRule(ctx context.Context, id string, req *dto.TaxRuleUpdateRequest) (*models.TaxRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.Rate != nil {
+		rule.Rate = *req.Rate
+	}
+	if req.Inclusive != nil {
+		rule.Inclusive = *req.Inclusive
+	}
+	if req.EffectiveFrom != nil {
+		rule.EffectiveFrom = *req.EffectiveFrom
+	}
+	if req.EffectiveTo != nil {
+		rule.EffectiveTo = req.EffectiveTo
+	}
+
+	if err := validateTaxRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.taxRepo.UpdateRule(ctx, rule); err != nil {
+		s.logger.Error("service.tax.update_rule: failed to update rule", slog.Any("error", err))
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *taxService) DeleteRule(ctx context.Context, id string) error {
+	if _, err := s.GetRule(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.taxRepo.DeleteRule(ctx, id); err != nil {
+		s.logger.Error("service.tax.delete_rule: failed to delete rule", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *taxService) Calculate(
+	ctx context.Context,
+	address models.BillingAddress,
+	amount int,
+	at time.Time,
+) (*dto.TaxCalculation, error) {
+	country := strings.ToUpper(address.Country)
+
+	calc := &dto.TaxCalculation{
+		Country:  country,
+		Region:   address.Region,
+		Currency: models.DefaultCurrency,
+		Net:      amount,
+		Gross:    amount,
+		Lines:    []dto.TaxItem{},
+	}
+
+	if country == "" {
+		return calc, nil
+	}
+
+	rules, err := s.taxRepo.EffectiveRules(ctx, country, address.Region, at)
+	if err != nil {
+		s.logger.Error("service.tax.calculate: failed to get rules", slog.Any("error", err))
+		return nil, err
+	}
+
+	calc.Net, calc.Lines = calculateTax(rules, amount)
+	for _, line := range calc.Lines {
+		calc.Tax += line.Amount
+	}
+	calc.Gross = calc.Net + calc.Tax
+
+	return calc, nil
+}
+
+func (s *taxService) Quote(ctx context.Context, userID uuid.UUID, amount int) (*dto.TaxCalculation, error) {
+	user, err := s.userRepo.GetByID(ctx, userID.String())
+	if err != nil {
+		s.logger.Error("service.tax.quote: failed to get user", slog.Any("error", err))
+		return nil, err
+	}
+
+	if user.BillingAddress.Country == "" {
+		return nil, ErrNoBillingAddress
+	}
+
+	return s.Calculate(ctx, user.BillingAddress, amount, s.now())
+}
+
+func (s *taxService) Record(ctx context.Context, item dto.TaxableItem) error {
+	user, err := s.userRepo.GetByID(ctx, item.UserID.String())
+	if err != nil {
+		s.logger.Error("service.tax.record: failed to get user", slog.Any("error", err))
+		return err
+	}
+
+	// без платёжного адреса ставку определить нельзя — налог не начисляется
+	if user.BillingAddress.Country == "" {
+		return nil
+	}
+
+	calc, err := s.Calculate(ctx, user.BillingAddress, item.Amount, s.now())
+	if err != nil {
+		return err
+	}
+
+	lines := make([]models.TaxLine, 0, len(calc.Lines))
+	for _, tax := range calc.Lines {
+		lines = append(lines, models.TaxLine{
+			UserID:         item.UserID,
+			OrderID:        item.OrderID,
+			SubscriptionID: item.SubscriptionID,
+			TaxRuleID:      tax.TaxRuleID,
+			Country:        calc.Country,
+			Region:         calc.Region,
+			Name:           tax.Name,
+			Rate:           tax.Rate,
+			Inclusive:      tax.Inclusive,
+			Taxable:        tax.Taxable,
+			Amount:         tax.Amount,
+			Currency:       calc.Currency,
+		})
+	}
+
+	if err := s.taxRepo.CreateLines(ctx, lines); err != nil {
+		s.logger.Error("service.tax.record: failed to save tax lines", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *taxService) Report(ctx context.Context, f dto.TaxReportFilter) ([]dto.TaxReportRow, error) {
+	if f.Period == "" {
+		f.Period = "month"
+	}
+	if !taxReportPeriods[f.Period] {
+		return nil, ErrInvalidTaxPeriod
+	}
+
+	from, to := startOfMonth(f.From), startOfMonth(f.To)
+	if from.IsZero() || to.IsZero() || from.After(to) {
+		return nil, ErrInvalidPeriod
+	}
+
+	f.From = from
+	f.To = to.AddDate(0, 1, 0)
+	f.Country = strings.ToUpper(f.Country)
+
+	rows, err := s.taxRepo.Report(ctx, f)
+	if err != nil {
+		s.logger.Error("service.tax.report: failed to build report", slog.Any("error", err))
+		return nil, err
+	}
+
+	if rows == nil {
+		rows = []dto.TaxReportRow{}
+	}
+
+	return rows, nil
+}
+
+// calculateTax раскладывает сумму на базу и налоги. Включённые в цену налоги
+// выделяются из суммы, остальные начисляются сверху на ту же базу
+func calculateTax(rules []models.TaxRule, amount int) (int, []dto.TaxItem) {
+	inclusiveRate := 0.0
+	for _, rule := range rules {
+		if rule.Inclusive {
+			inclusiveRate += rule.Rate
+		}
+	}
+
+	base := float64(amount) * 100 / (100 + inclusiveRate)
+
+	items := make([]dto.TaxItem, 0, len(rules))
+	net := amount
+	for _, rule := range rules {
+		if !rule.Inclusive {
+			continue
+		}
+		tax := int(math.Round(base * rule.Rate / 100))
+		net -= tax
+		items = append(items, taxItem(rule, tax))
+	}
+
+	for _, rule := range rules {
+		if rule.Inclusive {
+			continue
+		}
+		items = append(items, taxItem(rule, int(math.Round(float64(net)*rule.Rate/100))))
+	}
+
+	for i := range items {
+		items[i].Taxable = net
+	}
+
+	return net, items
+}
+
+func taxItem(rule models.TaxRule, amount int) dto.TaxItem {
+	return dto.TaxItem{
+		TaxRuleID: rule.ID,
+		Name:      rule.Name,
+		Rate:      rule.Rate,
+		Inclusive: rule.Inclusive,
+		Amount:    amount,
+	}
+}
+
+func validateTaxRule(rule *models.TaxRule) error {
+	if rule.Rate <= 0 || rule.Rate > 100 {
+		return ErrInvalidTaxRule
+	}
+	if rule.EffectiveTo != nil && !rule.EffectiveFrom.Before(*rule.EffectiveTo) {
+		return ErrInvalidTaxRule
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateTax_Inclusive(t *testing.T) {
+	rules := []models.TaxRule{{Name: "НДС", Rate: 20, Inclusive: true}}
+
+	net, lines := calculateTax(rules, 1200)
+
+	assert.Equal(t, 1000, net)
+	assert.Len(t, lines, 1)
+	assert.Equal(t, 200, lines[0].Amount)
+	assert.Equal(t, 1000, lines[0].Taxable)
+}
+
+func TestCalculateTax_ExclusiveStacked(t *testing.T) {
+	rules := []models.TaxRule{
+		{Name: "State", Rate: 6},
+		{Name: "County", Rate: 1.25},
+	}
+
+	net, lines := calculateTax(rules, 1000)
+
+	assert.Equal(t, 1000, net)
+	assert.Equal(t, 60, lines[0].Amount)
+	assert.Equal(t, 13, lines[1].Amount)
+}
+
+func TestTaxService_Record(t *testing.T) {
+	userID := uuid.New()
+	orderID := uuid.New()
+	ruleID := uuid.New()
+
+	var saved []models.TaxLine
+	repo := &mock.MockTaxRepository{
+		EffectiveRulesFn: func(ctx context.Context, country, region string, at time.Time) ([]models.TaxRule, error) {
+			assert.Equal(t, "DE", country)
+			assert.Equal(t, "Berlin", region)
+			return []models.TaxRule{{Base: models.Base{ID: ruleID}, Country: "DE", Name: "USt", Rate: 19, Inclusive: true}}, nil
+		},
+		CreateLinesFn: func(ctx context.Context, lines []models.TaxLine) error {
+			saved = lines
+			return nil
+		},
+	}
+	users := &mock.MockUserRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
+			return &models.User{BillingAddress: models.BillingAddress{Country: "de", Region: "Berlin"}}, nil
+		},
+	}
+	svc := NewTaxService(repo, users, newLogger())
+
+	err := svc.Record(context.Background(), dto.TaxableItem{UserID: userID, Amount: 119, OrderID: &orderID})
+
+	assert.NoError(t, err)
+	assert.Len(t, saved, 1)
+	assert.Equal(t, ruleID, saved[0].TaxRuleID)
+	assert.Equal(t, orderID, *saved[0].OrderID)
+	assert.Equal(t, "DE", saved[0].Country)
+	assert.Equal(t, 100, saved[0].Taxable)
+	assert.Equal(t, 19, saved[0].Amount)
+}
+
+func TestTaxService_Record_NoBillingAddress(t *testing.T) {
+	repo := &mock.MockTaxRepository{
+		CreateLinesFn: func(ctx context.Context, lines []models.TaxLine) error {
+			t.Fatal("tax lines must not be saved without billing address")
+			return nil
+		},
+	}
+	users := &mock.MockUserRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
+			return &models.User{}, nil
+		},
+	}
+	svc := NewTaxService(repo, users, newLogger())
+
+	err := svc.Record(context.Background(), dto.TaxableItem{UserID: uuid.New(), Amount: 100})
+
+	assert.NoError(t, err)
+}
+
+func TestTaxService_Report_InvalidPeriod(t *testing.T) {
+	svc := NewTaxService(&mock.MockTaxRepository{}, &mock.MockUserRepository{}, newLogger())
+
+	_, err := svc.Report(context.Background(), dto.TaxReportFilter{
+		From:   month(2025, time.January),
+		To:     month(2025, time.March),
+		Period: "week",
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidTaxPeriod)
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"effective-project/internal/dto"
+	"effective-project/internal/i18n"
+	"effective-project/internal/models"
+	"effective-project/internal/repository"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnsupportedLocale   = errors.New("язык не поддерживается")
+	ErrDefaultLocale       = errors.New("текст на языке по умолчанию редактируется в самой записи")
+	ErrTranslationNotFound = errors.New("перевод не найден")
+)
+
+type TranslationService interface {
+	Locales() i18n.Locales
+
+	// Localize* подставляют переводы на язык locale вместо текста на языке
+	// по умолчанию; записи без перевода остаются как есть
+	LocalizeCategories(ctx context.Context, locale string, categories []models.Category) error
+	LocalizeServices(ctx context.Context, locale string, services []models.Service) error
+	LocalizeTree(ctx context.Context, locale string, nodes []*dto.CategoryNode) error
+	LocalizePath(ctx context.Context, locale string, path []dto.CategoryRef) error
+
+	CategoryTranslations(ctx context.Context, categoryID string) ([]models.CategoryTranslation, error)
+	SetCategoryTranslation(
+		ctx context.Context,
+		categoryID, locale string,
+		req *dto.CategoryTranslationRequest,
+	) (*models.CategoryTranslation, error)
+	DeleteCategoryTranslation(ctx context.Context, categoryID, locale string) error
+
+	ServiceTranslations(ctx context.Context, serviceID string) ([]models.ServiceTranslation, error)
+	SetServiceTranslation(
+		ctx context.Context,
+		serviceID, locale string,
+		req *dto.ServiceTranslationRequest,
+	) (*models.ServiceTranslation, error)
+	DeleteServiceTranslation(ctx context.Context, serviceID, locale string) error
+}
+
+type translationService struct {
+	translationRepo repository.TranslationRepository
+	categoryRepo    repository.CategoryRepository
+	serviceRepo     repository.ServiceRepository
+	locales         i18n.Locales
+	logger          *slog.Logger
+}
+
+func NewTranslationService(
+	translationRepo repository.TranslationRepository,
+	categoryRepo repository.CategoryRepository,
+	serviceRepo repository.ServiceRepository,
+	locales i18n.Locales,
+	logger *slog.Logger,
+) TranslationService {
+	return &translationService{
+		translationRepo: translationRepo,
+		categoryRepo:    categoryRepo,
+		serviceRepo:     serviceRepo,
+		locales:         locales,
+		logger:          logger,
+	}
+}
+
+func (s *translationService) Locales() i18n.Locales {
+	return s.locales
+}
+
+func (s *translationService) LocalizeCategories(ctx context.Context, locale string, categories []models.Category) error {
+	ids := make([]uuid.UUID, 0, len(categories))
+	for _, category := range categories {
+		ids = append(ids, category.ID)
+	}
+
+	names, err := s.categoryNames(ctx, locale, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range categories {
+		if name, ok := names[categories[i].ID]; ok {
+			categories[i].Name = name
+		}
+	}
+
+	return nil
+}
+
+func (s *translationService) LocalizeServices(ctx context.Context, locale string, services []models.Service) error {
+	if locale == s.locales.Default || len(services) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(services))
+	for _, svc := range services {
+		ids = append(ids, svc.ID)
+	}
+
+	rows, err := s.translationRepo.ServiceTranslations(ctx, locale, ids)
+	if err != nil {
+		s.logger.Error("service.translation.localize_services: failed to get translations", slog.Any("error", err))
+		return err
+	}
+
+	translations := make(map[uuid.UUID]models.ServiceTranslation, len(rows))
+	for _, row := range rows {
+		translations[row.ServiceID] = row
+	}
+
+	for i := range services {
+		t, ok := translations[services[i].ID]
+		if !ok {
+			continue
+		}
+		services[i].Name = t.Name
+		if t.Description != "" {
+			services[i].Description = t.Description
+		}
+	}
+
+	return nil
+}
+
+func (s *translationService) LocalizeTree(ctx context.Context, locale string, nodes []*dto.CategoryNode) error {
+	var ids []uuid.UUID
+	walkTree(nodes, func(node *dto.CategoryNode) {
+		ids = append(ids, node.ID)
+	})
+
+	names, err := s.categoryNames(ctx, locale, ids)
+	if err != nil {
+		return err
+	}
+
+	walkTree(nodes, func(node *dto.CategoryNode) {
+		if name, ok := names[node.ID]; ok {
+			node.Name = name
+		}
+	})
+
+	return nil
+}
+
+func (s *translationService) LocalizePath(ctx context.Context, locale string, path []dto.CategoryRef) error {
+	ids := make([]uuid.UUID, 0, len(path))
+	for _, ref := range path {
+		ids = append(ids, ref.ID)
+	}
+
+	names, err := s.categoryNames(ctx, locale, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range path {
+		if name, ok := names[path[i].ID]; ok {
+			path[i].Name = name
+		}
+	}
+
+	return nil
+}
+
+func (s *translationService) CategoryTranslations(ctx context.Context, categoryID string) ([]models.CategoryTranslation, error) {
+	category, err := s.category(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := s.translationRepo.ListCategory(ctx, category.ID)
+	if err != nil {
+		s.logger.Error("service.translation.category_translations: failed to get translations", slog.Any("error", err))
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+func (s *translationService) SetCategoryTranslation(
+	ctx context.Context,
+	categoryID, locale string,
+	req *dto.CategoryTranslationRequest,
+) (*models.CategoryTranslation, error) {
+	locale, err := s.editableLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.category(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &models.CategoryTranslation{
+		CategoryID: category.ID,
+		Locale:     locale,
+		Name:       req.Name,
+	}
+
+	if err := s.translationRepo.UpsertCategory(ctx, t); err != nil {
+		s.logger.Error("service.translation.set_category_translation: failed to save translation", slog.Any("error", err))
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (s *translationService) DeleteCategoryTranslation(ctx context.Context, categoryID, locale string) error {
+	locale, err := s.editableLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	category, err := s.category(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.translationRepo.DeleteCategory(ctx, category.ID, locale)
+	if err != nil {
+		s.logger.Error("service.translation.delete_category_translation: failed to delete translation", slog.Any("error", err))
+		return err
+	}
+	if !deleted {
+		return ErrTranslationNotFound
+	}
+
+	return nil
+}
+
+func (s *translationService) ServiceTranslations(ctx context.Context, serviceID string) ([]models.ServiceTranslation, error) {
+	svc, err := s.service(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := s.translationRepo.ListService(ctx, svc.ID)
+	if err != nil {
+		s.logger.Error("service.translation.service_translations: failed to get translations", slog.Any("error", err))
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+func (s *translationService) SetServiceTranslation(
+	ctx context.Context,
+	serviceID, locale string,
+	req *dto.ServiceTranslationRequest,
+) (*models.ServiceTranslation, error) {
+	locale, err := s.editableLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := s.service(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &models.ServiceTranslation{
+		ServiceID:   svc.ID,
+		Locale:      locale,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+
+	if err := s.translationRepo.UpsertService(ctx, t); err != nil {
+		s.logger.Error("service.translation.set_service_translation: failed to save translation", slog.Any("error", err))
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (s *translationService) DeleteServiceTranslation(ctx context.Context, serviceID, locale string) error {
+	locale, err := s.editableLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	svc, err := s.service(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.translationRepo.DeleteService(ctx, svc.ID, locale)
+	if err != nil {
+		s.logger.Error("service.translation.delete_service_translation: failed to delete translation", slog.Any("error", err))
+		return err
+	}
+	if !deleted {
+		return ErrTranslationNotFound
+	}
+
+	return nil
+}
+
+// categoryNames возвращает переведённые названия категорий; для языка
+// по умолчанию переводов нет и запрос не выполняется
+func (s *translationService) categoryNames(
+	ctx context.Context,
+	locale string,
+	ids []uuid.UUID,
+) (map[uuid.UUID]string, error) {
+	if locale == s.locales.Default || len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.translationRepo.CategoryTranslations(ctx, locale, ids)
+	if err != nil {
+		s.logger.Error("service.translation.category_names: failed to get translations", slog.Any("error", err))
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		names[row.CategoryID] = row.Name
+	}
+
+	return names, nil
+}
+
+// editableLocale проверяет, что для языка можно хранить перевод
+func (s *translationService) editableLocale(locale string) (string, error) {
+	locale = i18n.Normalize(locale)
+	if !s.locales.Supports(locale) {
+		return "", ErrUnsupportedLocale
+	}
+	if locale == s.locales.Default {
+		return "", ErrDefaultLocale
+	}
+	return locale, nil
+}
+
+func (s *translationService) category(ctx context.Context, id string) (*models.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		s.logger.Error("service.translation: failed to get category", slog.Any("error", err))
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *translationService) service(ctx context.Context, id string) (*models.Service, error) {
+	svc, err := s.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		s.logger.Error("service.translation: failed to get service", slog.Any("error", err))
+		return nil, err
+	}
+	return svc, nil
+}
+
+func walkTree(nodes []*dto.CategoryNode, fn func(node *dto.CategoryNode)) {
+	for _, node := range nodes {
+		fn(node)
+		walkTree(node.Children, fn)
+	}
+}
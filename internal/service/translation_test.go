@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/i18n"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var testLocales = i18n.Locales{Default: "ru", Supported: []string{"ru", "en"}}
+
+func TestTranslationService_LocalizeServices_FallsBack(t *testing.T) {
+	spotify := models.Service{Base: models.Base{ID: uuid.New()}, Name: "Спотифай", Description: "Музыка онлайн"}
+	okko := models.Service{Base: models.Base{ID: uuid.New()}, Name: "Окко", Description: "Кино"}
+
+	var gotLocale string
+	repo := &mock.MockTranslationRepository{
+		ServiceTranslationsFn: func(ctx context.Context, locale string, ids []uuid.UUID) ([]models.ServiceTranslation, error) {
+			gotLocale = locale
+			return []models.ServiceTranslation{{ServiceID: spotify.ID, Locale: "en", Name: "Spotify"}}, nil
+		},
+	}
+	svc := NewTranslationService(repo, &mock.MockCategoryRepository{}, &mock.MockServiceRepository{}, testLocales, newLogger())
+
+	services := []models.Service{spotify, okko}
+	err := svc.LocalizeServices(context.Background(), "en", services)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "en", gotLocale)
+	assert.Equal(t, "Spotify", services[0].Name)
+	assert.Equal(t, "Музыка онлайн", services[0].Description)
+	assert.Equal(t, "Окко", services[1].Name)
+}
+
+func TestTranslationService_DefaultLocaleSkipsLookup(t *testing.T) {
+	repo := &mock.MockTranslationRepository{
+		CategoryTranslationsFn: func(ctx context.Context, locale string, ids []uuid.UUID) ([]models.CategoryTranslation, error) {
+			t.Fatal("translations must not be loaded for the default locale")
+			return nil, nil
+		},
+	}
+	svc := NewTranslationService(repo, &mock.MockCategoryRepository{}, &mock.MockServiceRepository{}, testLocales, newLogger())
+
+	categories := []models.Category{{Base: models.Base{ID: uuid.New()}, Name: "Музыка"}}
+	err := svc.LocalizeCategories(context.Background(), "ru", categories)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Музыка", categories[0].Name)
+}
+
+func TestTranslationService_LocalizeTree(t *testing.T) {
+	media := uuid.New()
+	music := uuid.New()
+	repo := &mock.MockTranslationRepository{
+		CategoryTranslationsFn: func(ctx context.Context, locale string, ids []uuid.UUID) ([]models.CategoryTranslation, error) {
+			assert.ElementsMatch(t, []uuid.UUID{media, music}, ids)
+			return []models.CategoryTranslation{{CategoryID: music, Locale: "en", Name: "Music"}}, nil
+		},
+	}
+	svc := NewTranslationService(repo, &mock.MockCategoryRepository{}, &mock.MockServiceRepository{}, testLocales, newLogger())
+
+	tree := []*dto.CategoryNode{{ID: media, Name: "Медиа", Children: []*dto.CategoryNode{{ID: music, Name: "Музыка"}}}}
+	err := svc.LocalizeTree(context.Background(), "en", tree)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Медиа", tree[0].Name)
+	assert.Equal(t, "Music", tree[0].Children[0].Name)
+}
+
+func TestTranslationService_SetCategoryTranslation(t *testing.T) {
+	categoryID := uuid.New()
+	categories := &mock.MockCategoryRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Category, error) {
+			return &models.Category{Base: models.Base{ID: categoryID}}, nil
+		},
+	}
+
+	var saved *models.CategoryTranslation
+	repo := &mock.MockTranslationRepository{
+		UpsertCategoryFn: func(ctx context.Context, t *models.CategoryTranslation) error {
+			saved = t
+			return nil
+		},
+	}
+	svc := NewTranslationService(repo, categories, &mock.MockServiceRepository{}, testLocales, newLogger())
+
+	_, err := svc.SetCategoryTranslation(context.Background(), categoryID.String(), "EN", &dto.CategoryTranslationRequest{Name: "Music"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.CategoryTranslation{CategoryID: categoryID, Locale: "en", Name: "Music"}, saved)
+
+	_, err = svc.SetCategoryTranslation(context.Background(), categoryID.String(), "ru", &dto.CategoryTranslationRequest{Name: "Музыка"})
+	assert.ErrorIs(t, err, ErrDefaultLocale)
+
+	_, err = svc.SetCategoryTranslation(context.Background(), categoryID.String(), "de", &dto.CategoryTranslationRequest{Name: "Musik"})
+	assert.ErrorIs(t, err, ErrUnsupportedLocale)
+}
+
+func TestTranslationService_DeleteServiceTranslation_NotFound(t *testing.T) {
+	services := &mock.MockServiceRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.Service, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	svc := NewTranslationService(&mock.MockTranslationRepository{}, &mock.MockCategoryRepository{}, services, testLocales, newLogger())
+
+	err := svc.DeleteServiceTranslation(context.Background(), uuid.NewString(), "en")
+
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"effective-project/internal/dto"
+	"effective-project/internal/models"
+	"effective-project/internal/repository"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultUpcomingDays = 30
+	maxUpcomingDays     = 366
+)
+
+type UpcomingService interface {
+	Upcoming(ctx context.Context, userID uuid.UUID, days int) (*dto.UpcomingChargesResponse, error)
+
+	Calendar(ctx context.Context, userID uuid.UUID, days int) ([]byte, error)
+
+	CalendarToken(userID uuid.UUID) string
+
+	VerifyCalendarToken(userID uuid.UUID, token string) bool
+}
+
+type upcomingService struct {
+	subscriptionRepo repository.SubscriptionRepository
+	secret           []byte
+	now              func() time.Time
+	logger           *slog.Logger
+}
+
+func NewUpcomingService(
+	subscriptionRepo repository.SubscriptionRepository,
+	secret string,
+	logger *slog.Logger,
+) UpcomingService {
+	return &upcomingService{
+		subscriptionRepo: subscriptionRepo,
+		secret:           []byte(secret),
+		now:              time.Now,
+		logger:           logger,
+	}
+}
+
+func (s *upcomingService) Upcoming(ctx context.Context, userID uuid.UUID, days int) (*dto.UpcomingChargesResponse, error) {
+	if days <= 0 {
+		days = defaultUpcomingDays
+	}
+	if days > maxUpcomingDays {
+		days = maxUpcomingDays
+	}
+
+	from := s.now().UTC().Truncate(24 * time.Hour)
+	to := from.AddDate(0, 0, days)
+
+	rows, err := s.subscriptionRepo.ListActiveByUser(ctx, userID, from)
+	if err != nil {
+		s.logger.Error("service.upcoming.upcoming: failed to get subscriptions", slog.Any("error", err))
+		return nil, err
+	}
+
+	items := make([]dto.UpcomingCharge, 0)
+	total := 0
+	for _, row := range rows {
+		for _, date := range chargeDates(row, from, to) {
+			items = append(items, dto.UpcomingCharge{
+				Date:           date,
+				SubscriptionID: row.ID,
+				ServiceID:      row.ServiceID,
+				ServiceName:    row.ServiceName,
+				Amount:         row.Price,
+			})
+			total += row.Price
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Date.Before(items[j].Date)
+	})
+
+	return &dto.UpcomingChargesResponse{
+		From:  from,
+		To:    to,
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+func (s *upcomingService) Calendar(ctx context.Context, userID uuid.UUID, days int) ([]byte, error) {
+	if days <= 0 {
+		days = maxUpcomingDays
+	}
+
+	upcoming, err := s.Upcoming(ctx, userID, days)
+	if err != nil {
+		return nil, err
+	}
+
+	stamp := s.now().UTC().Format("20060102T150405Z")
+
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//subhub//upcoming charges//RU\r\n")
+	b.WriteString("CALSCALE:GREGORIAN\r\n")
+	b.WriteString("METHOD:PUBLISH\r\n")
+	b.WriteString("X-WR-CALNAME:Списания по подпискам\r\n")
+
+	for _, item := range upcoming.Items {
+		date := item.Date.Format("20060102")
+
+		b.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(&b, "UID:%s-%s@subhub\r\n", item.SubscriptionID, date)
+		fmt.Fprintf(&b, "DTSTAMP:%s\r\n", stamp)
+		fmt.Fprintf(&b, "DTSTART;VALUE=DATE:%s\r\n", date)
+		fmt.Fprintf(&b, "DTEND;VALUE=DATE:%s\r\n", item.Date.AddDate(0, 0, 1).Format("20060102"))
+		fmt.Fprintf(&b, "SUMMARY:%s\r\n", escapeICS(fmt.Sprintf("%s — %d", item.ServiceName, item.Amount)))
+		fmt.Fprintf(&b, "DESCRIPTION:%s\r\n", escapeICS(fmt.Sprintf("Списание по подписке %s", item.SubscriptionID)))
+		b.WriteString("TRANSP:TRANSPARENT\r\n")
+		b.WriteString("END:VEVENT\r\n")
+	}
+
+	b.WriteString("END:VCALENDAR\r\n")
+
+	return []byte(b.String()), nil
+}
+
+// CalendarToken подписывает ссылку на календарь, чтобы календарные приложения
+// могли забирать её без заголовка Authorization
+func (s *upcomingService) CalendarToken(userID uuid.UUID) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("calendar:" + userID.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *upcomingService) VerifyCalendarToken(userID uuid.UUID, token string) bool {
+	return hmac.Equal([]byte(s.CalendarToken(userID)), []byte(token))
+}
+
+// chargeDates возвращает даты списаний подписки в полуинтервале [from, to)
+func chargeDates(row dto.UpcomingSubscriptionRow, from, to time.Time) []time.Time {
+	var dates []time.Time
+
+	for i := 0; ; i++ {
+		date := addInterval(row.StartDate, row.Interval, i)
+
+		if !date.Before(to) {
+			break
+		}
+		if row.EndDate != nil && date.After(*row.EndDate) {
+			break
+		}
+		if date.Before(from) {
+			continue
+		}
+		if row.PausedUntil != nil && date.Before(*row.PausedUntil) {
+			continue
+		}
+
+		dates = append(dates, date)
+	}
+
+	return dates
+}
+
+// addInterval сдвигает дату на n периодов, прижимая день к концу месяца (31.01 -> 28.02)
+func addInterval(start time.Time, interval models.BillingInterval, n int) time.Time {
+	months := n
+	if interval == models.IntervalYear {
+		months = n * 12
+	}
+
+	first := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	day := start.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+func escapeICS(v string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(v)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"effective-project/internal/dto"
+	"effective-project/internal/mock"
+	"effective-project/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestUpcomingService(rows []dto.UpcomingSubscriptionRow, now time.Time) *upcomingService {
+	repo := &mock.MockSubscriptionRepository{
+		ListActiveByUserFn: func(ctx context.Context, userID uuid.UUID, at time.Time) ([]dto.UpcomingSubscriptionRow, error) {
+			return rows, nil
+		},
+	}
+
+	svc := NewUpcomingService(repo, "secret", newLogger()).(*upcomingService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestUpcomingService_Upcoming(t *testing.T) {
+	pausedUntil := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.February, 20, 0, 0, 0, 0, time.UTC)
+
+	rows := []dto.UpcomingSubscriptionRow{
+		{ID: uuid.New(), ServiceName: "Music", StartDate: time.Date(2024, time.October, 31, 0, 0, 0, 0, time.UTC), Price: 300, Interval: models.IntervalMonth},
+		{ID: uuid.New(), ServiceName: "Cinema", StartDate: time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC), Price: 2000, Interval: models.IntervalYear},
+		{ID: uuid.New(), ServiceName: "Paused", StartDate: time.Date(2025, time.January, 5, 0, 0, 0, 0, time.UTC), Price: 100, Interval: models.IntervalMonth, PausedUntil: &pausedUntil},
+		{ID: uuid.New(), ServiceName: "Ending", StartDate: time.Date(2025, time.January, 25, 0, 0, 0, 0, time.UTC), EndDate: &endDate, Price: 50, Interval: models.IntervalMonth},
+	}
+
+	svc := newTestUpcomingService(rows, time.Date(2025, time.February, 1, 12, 0, 0, 0, time.UTC))
+
+	upcoming, err := svc.Upcoming(context.Background(), uuid.New(), 30)
+
+	assert.NoError(t, err)
+	assert.Len(t, upcoming.Items, 2)
+
+	assert.Equal(t, "Cinema", upcoming.Items[0].ServiceName)
+	assert.Equal(t, time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC), upcoming.Items[0].Date)
+
+	// 31 октября -> последний день февраля
+	assert.Equal(t, "Music", upcoming.Items[1].ServiceName)
+	assert.Equal(t, time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC), upcoming.Items[1].Date)
+
+	assert.Equal(t, 2300, upcoming.Total)
+}
+
+func TestUpcomingService_Calendar(t *testing.T) {
+	rows := []dto.UpcomingSubscriptionRow{
+		{ID: uuid.New(), ServiceName: "Music, Premium", StartDate: time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC), Price: 300, Interval: models.IntervalMonth},
+	}
+
+	svc := newTestUpcomingService(rows, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC))
+
+	calendar, err := svc.Calendar(context.Background(), uuid.New(), 60)
+
+	assert.NoError(t, err)
+
+	ics := string(calendar)
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20250215")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20250315")
+	assert.Contains(t, ics, `SUMMARY:Music\, Premium — 300`)
+}
+
+func TestUpcomingService_CalendarToken(t *testing.T) {
+	svc := newTestUpcomingService(nil, time.Now())
+
+	userID := uuid.New()
+	token := svc.CalendarToken(userID)
+
+	assert.True(t, svc.VerifyCalendarToken(userID, token))
+	assert.False(t, svc.VerifyCalendarToken(uuid.New(), token))
+	assert.False(t, svc.VerifyCalendarToken(userID, "bogus"))
+}
@@ -5,56 +5,70 @@ import (
 	cache "effective-project/internal/cache"
 	"effective-project/internal/dto"
 	"effective-project/internal/models"
+	"effective-project/internal/query"
 	"effective-project/internal/repository"
 	"errors"
 	"log/slog"
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type UserService interface {
-	Create(req *dto.UserCreateRequest) (*models.User, error)
+	Create(ctx context.Context, req *dto.UserCreateRequest) (*models.User, error)
 
-	List(
-		ctx context.Context,
-		limit int,
-		lastCreatedAt *time.Time,
-		lastID *uuid.UUID) ([]models.User, error)
+	List(ctx context.Context, q *query.List) ([]models.User, error)
 
-	GetByID(id string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
 
-	GetByEmail(email string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
 
-	Update(id string, user *dto.UserUpdateRequest) (*models.User, error)
+	Update(ctx context.Context, id string, user *dto.UserUpdateRequest) (*models.User, error)
 
-	Delete(id string) error
+	Delete(ctx context.Context, id string) error
 
-	ChangePassword(userID string, oldPassword, newPassword string) error
+	ChangePassword(ctx context.Context, userID string, oldPassword, newPassword string) error
+}
+
+// userCacheTTL — срок жизни пользователя в кеше
+const userCacheTTL = 10 * time.Minute
+
+// userTag — тег всех записей кеша о пользователе: по id и по email
+func userTag(id string) string {
+	return cache.TagUser + id
+}
+
+// UserTags привязывает записи кешей пользователя к его тегу
+func UserTags(key string, user *models.User) []string {
+	return []string{userTag(user.ID.String())}
 }
 
 type userService struct {
-	repo   repository.UserRepository
-	cache  cache.UserCache
-	logger *slog.Logger
+	repo       repository.UserRepository
+	cache      cache.Cache[*models.User]
+	emailCache cache.Cache[*models.User]
+	logger     *slog.Logger
 }
 
+// NewUserService принимает кеши пользователей по id и по email. Обе записи
+// помечаются тегом пользователя, и изменение удаляет их вместе
 func NewUserService(
 	repo repository.UserRepository,
-	cache cache.UserCache,
+	cache cache.Cache[*models.User],
+	emailCache cache.Cache[*models.User],
 	logger *slog.Logger,
 ) UserService {
 	return &userService{
-		repo:   repo,
-		cache:  cache,
-		logger: logger,
+		repo:       repo,
+		cache:      cache,
+		emailCache: emailCache,
+		logger:     logger,
 	}
 }
 
-func (s *userService) Create(req *dto.UserCreateRequest) (*models.User, error) {
+func (s *userService) Create(ctx context.Context, req *dto.UserCreateRequest) (*models.User, error) {
 	hashed, err := hashPassword(req.Password)
 	if err != nil {
 		s.logger.Error("service.user.create: failed to hash password", slog.Any("error", err))
@@ -74,20 +88,21 @@ func (s *userService) Create(req *dto.UserCreateRequest) (*models.User, error) {
 		Roles:     role,
 	}
 
-	if err := s.repo.Create(user); err != nil {
+	if err := s.repo.Create(ctx, user); err != nil {
 		s.logger.Error("service.user.create: failed to create user:", slog.Any("error", err))
 		return nil, err
 	}
 
+	// по этому email мог быть закеширован промах
+	if err := s.emailCache.Delete(afterCommit(ctx), user.Email); err != nil {
+		s.logger.Warn("service.user.create: failed to delete cache by email", slog.Any("error", err))
+	}
+
 	return user, nil
 }
 
-func (s *userService) List(
-	ctx context.Context,
-	limit int,
-	lastCreatedAt *time.Time,
-	lastID *uuid.UUID) ([]models.User, error) {
-	users, err := s.repo.List(ctx, limit, lastCreatedAt, lastID)
+func (s *userService) List(ctx context.Context, q *query.List) ([]models.User, error) {
+	users, err := s.repo.List(ctx, q)
 	if err != nil {
 		s.logger.Error("service.user.get_all: failed to get users:", slog.Any("error", err))
 		return nil, err
@@ -96,36 +111,28 @@ func (s *userService) List(
 	return users, nil
 }
 
-func (s *userService) GetByID(id string) (*models.User, error) {
-	ctx := context.Background()
-
-	if user, err := s.cache.GetByID(ctx, id); err == nil {
-		s.logger.Debug("user cache hit", "user_id", id)
-		return user, nil
-	}
-
-	s.logger.Debug("user cache miss", "user_id", id)
-
-	user, err := s.repo.GetByID(id)
+func (s *userService) GetByID(ctx context.Context, id string) (*models.User, error) {
+	user, err := s.cache.GetOrLoad(ctx, id, func(ctx context.Context) (*models.User, error) {
+		s.logger.Debug("user cache miss", "user_id", id)
+		return s.repo.GetByID(ctx, id)
+	}, userCacheTTL)
 	if err != nil {
 		s.logger.Error("service.user.get_by_id: failed to get user:", slog.Any("error", err))
 		return nil, err
 	}
 
-	if err := s.cache.Set(ctx, user, time.Minute*10); err != nil {
-		s.logger.Warn("service.user.get_by_id: failed to set cache", slog.Any("error", err))
-	}
-
 	return user, nil
 }
 
-func (s *userService) Update(id string, req *dto.UserUpdateRequest) (*models.User, error) {
-	user, err := s.repo.GetByID(id)
+func (s *userService) Update(ctx context.Context, id string, req *dto.UserUpdateRequest) (*models.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		s.logger.Error("service.user.update: failed to get user:", slog.Any("error", err))
 		return nil, err
 	}
 
+	oldEmail := user.Email
+
 	if req.FirstName != nil {
 		user.FirstName = *req.FirstName
 	}
@@ -135,37 +142,41 @@ func (s *userService) Update(id string, req *dto.UserUpdateRequest) (*models.Use
 	if req.Email != nil {
 		user.Email = *req.Email
 	}
+	if req.BillingAddress != nil {
+		user.BillingAddress = *req.BillingAddress
+		user.BillingAddress.Country = strings.ToUpper(user.BillingAddress.Country)
+	}
 
-	if err := s.repo.Update(user); err != nil {
+	if err := s.repo.Update(ctx, user); err != nil {
 		s.logger.Error("service.user.update: failed to update user:", slog.Any("error", err))
 		return nil, err
 	}
 
-	userID := user.ID.String()
-
-	ctx := context.Background()
+	// тег удаляет запись по id и по старому email
+	if err := s.cache.Invalidate(afterCommit(ctx), userTag(user.ID.String())); err != nil {
+		s.logger.Warn("service.user.update: failed to invalidate cache", slog.Any("error", err))
+	}
 
-	if err := s.cache.DeleteByID(ctx, userID); err != nil {
-		s.logger.Warn("service.user.update: failed to delete cache by id", slog.Any("error", err))
+	if user.Email != oldEmail {
+		if err := s.emailCache.Delete(afterCommit(ctx), user.Email); err != nil {
+			s.logger.Warn("service.user.update: failed to delete cache by email", slog.Any("error", err))
+		}
 	}
 
 	return user, nil
 }
 
-func (s *userService) Delete(id string) error {
-	user, _ := s.repo.GetByID(id)
+func (s *userService) Delete(ctx context.Context, id string) error {
+	user, _ := s.repo.GetByID(ctx, id)
 
-	if err := s.repo.Delete(id); err != nil {
+	if err := s.repo.Delete(ctx, id); err != nil {
 		s.logger.Error("service.user.delete: failed to delete user:", slog.Any("error", err))
 		return err
 	}
 
 	if user != nil {
-		userID := user.ID.String()
-		ctx := context.Background()
-
-		if err := s.cache.DeleteByID(ctx, userID); err != nil {
-			s.logger.Warn("service.user.delete: failed to delete cache by id", slog.Any("error", err))
+		if err := s.cache.Invalidate(afterCommit(ctx), userTag(user.ID.String())); err != nil {
+			s.logger.Warn("service.user.delete: failed to invalidate cache", slog.Any("error", err))
 		}
 	}
 
@@ -177,12 +188,13 @@ func checkPassword(hash, plain string) error {
 }
 
 func (s *userService) ChangePassword(
+	ctx context.Context,
 	userID string,
 	oldPassword,
 	newPassword string,
 ) error {
 	s.logger.Debug("ChangePassword called", "user_id", userID)
-	user, err := s.repo.GetByID(userID)
+	user, err := s.repo.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			s.logger.Warn("user not found for ChangePassword", "user_id", userID)
@@ -210,16 +222,12 @@ func (s *userService) ChangePassword(
 
 	user.Password = hashed
 
-	if err := s.repo.Update(user); err != nil {
+	if err := s.repo.Update(ctx, user); err != nil {
 		s.logger.Error("failed to update user password in repo", "error", err, "user_id", userID)
 		return err
 	}
 
-	id := user.ID.String()
-
-	ctx := context.Background()
-
-	_ = s.cache.DeleteByID(ctx, id)
+	_ = s.cache.Invalidate(afterCommit(ctx), userTag(user.ID.String()))
 	s.logger.Info("password changed", "user_id", userID)
 	return nil
 }
@@ -234,8 +242,10 @@ func hashPassword(plain string) (string, error) {
 	return string(hash), nil
 }
 
-func (s *userService) GetByEmail(email string) (*models.User, error) {
-	user, err := s.repo.GetByEmail(email)
+func (s *userService) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := s.emailCache.GetOrLoad(ctx, email, func(ctx context.Context) (*models.User, error) {
+		return s.repo.GetByEmail(ctx, email)
+	}, userCacheTTL)
 	if err != nil {
 		s.logger.Error("failed to get user by email in repo", "error", err, "email", email)
 		return nil, err
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -57,16 +58,16 @@ func TestUserService_Create(t *testing.T) {
 			var savedUser *models.User
 
 			repo := &mock.MockUserRepository{
-				CreateFn: func(u *models.User) error {
+				CreateFn: func(ctx context.Context, u *models.User) error {
 					savedUser = u
 					return tt.repoErr
 				},
 			}
-			cache := &mock.MockUserCache{}
+			cache := &mock.MockCache[*models.User]{}
 
 			logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
-			svc := NewUserService(repo, cache, logger)
-			_, err := svc.Create(tt.req)
+			svc := NewUserService(repo, cache, &mock.MockCache[*models.User]{}, logger)
+			_, err := svc.Create(context.Background(), tt.req)
 
 			if tt.wantErr && err == nil {
 				t.Fatalf("expected error")
@@ -117,25 +118,25 @@ func TestUserService_GetByID(t *testing.T) {
 			cacheSetCalled := false
 
 			repo := &mock.MockUserRepository{
-				GetByIDFn: func(id string) (*models.User, error) {
+				GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
 					repoCalled = true
 					return tt.repoResult, tt.repoErr
 				},
 			}
 
-			cacheMock := &mock.MockUserCache{
-				GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
+			cacheMock := &mock.MockCache[*models.User]{
+				GetFn: func(ctx context.Context, id string) (*models.User, error) {
 					return tt.cacheResult, tt.cacheErr
 				},
-				SetFn: func(ctx context.Context, u *models.User, ttl time.Duration) error {
+				SetFn: func(ctx context.Context, key string, u *models.User, ttl time.Duration) error {
 					cacheSetCalled = true
 					return nil
 				},
 			}
 
 			logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
-			svc := NewUserService(repo, cacheMock, logger)
-			_, _ = svc.GetByID("1")
+			svc := NewUserService(repo, cacheMock, &mock.MockCache[*models.User]{}, logger)
+			_, _ = svc.GetByID(context.Background(), "1")
 
 			if tt.expectRepoHit && !repoCalled {
 				t.Fatalf("expected repo.GetByID to be called")
@@ -151,32 +152,32 @@ func TestUserService_Update(t *testing.T) {
 	user := &models.User{Base: models.Base{ID: uuid.New()}, Email: "old@mail.com"}
 
 	repo := &mock.MockUserRepository{
-		GetByIDFn: func(id string) (*models.User, error) {
+		GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
 			return user, nil
 		},
-		UpdateFn: func(u *models.User) error {
+		UpdateFn: func(ctx context.Context, u *models.User) error {
 			return nil
 		},
 	}
 
 	cacheDeleted := false
-	cacheMock := &mock.MockUserCache{
-		DeleteByIDFn: func(ctx context.Context, id string) error {
-			cacheDeleted = true
+	cacheMock := &mock.MockCache[*models.User]{
+		InvalidateFn: func(ctx context.Context, tags ...string) error {
+			cacheDeleted = len(tags) == 1 && tags[0] == "user:"+user.ID.String()
 			return nil
 		},
 	}
 
 	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	svc := NewUserService(repo, cacheMock, logger)
+	svc := NewUserService(repo, cacheMock, &mock.MockCache[*models.User]{}, logger)
 	newEmail := "new@mail.com"
-	_, err := svc.Update(user.ID.String(), &dto.UserUpdateRequest{Email: &newEmail})
+	_, err := svc.Update(context.Background(), user.ID.String(), &dto.UserUpdateRequest{Email: &newEmail})
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if !cacheDeleted {
-		t.Fatalf("expected cache.DeleteByID to be called")
+		t.Fatalf("expected user tag to be invalidated")
 	}
 }
 
@@ -184,30 +185,30 @@ func TestUserService_Delete(t *testing.T) {
 	cacheDeleted := false
 
 	repo := &mock.MockUserRepository{
-		GetByIDFn: func(id string) (*models.User, error) {
+		GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
 			return &models.User{Base: models.Base{ID: uuid.New()}}, nil
 		},
-		DeleteFn: func(id string) error {
+		DeleteFn: func(ctx context.Context, id string) error {
 			return nil
 		},
 	}
 
-	cache := &mock.MockUserCache{
-		DeleteByIDFn: func(ctx context.Context, id string) error {
+	cache := &mock.MockCache[*models.User]{
+		InvalidateFn: func(ctx context.Context, tags ...string) error {
 			cacheDeleted = true
 			return nil
 		},
 	}
 
 	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	svc := NewUserService(repo, cache, logger)
-	err := svc.Delete("1")
+	svc := NewUserService(repo, cache, &mock.MockCache[*models.User]{}, logger)
+	err := svc.Delete(context.Background(), "1")
 
 	if err != nil {
 		t.Fatalf("unexpected error")
 	}
 	if !cacheDeleted {
-		t.Fatalf("expected cache.DeleteByID")
+		t.Fatalf("expected user tag to be invalidated")
 	}
 }
 
@@ -252,27 +253,27 @@ func TestUserService_ChangePassword(t *testing.T) {
 			cacheDeleted := false
 
 			repo := &mock.MockUserRepository{
-				GetByIDFn: func(id string) (*models.User, error) {
+				GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
 					if tt.user == nil {
 						return nil, gorm.ErrRecordNotFound
 					}
 					return tt.user, nil
 				},
-				UpdateFn: func(u *models.User) error {
+				UpdateFn: func(ctx context.Context, u *models.User) error {
 					return nil
 				},
 			}
 
-			cacheMock := &mock.MockUserCache{
-				DeleteByIDFn: func(ctx context.Context, id string) error {
+			cacheMock := &mock.MockCache[*models.User]{
+				InvalidateFn: func(ctx context.Context, tags ...string) error {
 					cacheDeleted = true
 					return nil
 				},
 			}
 
 			logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
-			svc := NewUserService(repo, cacheMock, logger)
-			err := svc.ChangePassword("1", tt.oldPass, tt.newPass)
+			svc := NewUserService(repo, cacheMock, &mock.MockCache[*models.User]{}, logger)
+			err := svc.ChangePassword(context.Background(), "1", tt.oldPass, tt.newPass)
 
 			if tt.wantErr != "" {
 				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
@@ -285,8 +286,101 @@ func TestUserService_ChangePassword(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 			if !cacheDeleted {
-				t.Fatalf("expected cache.DeleteByID")
+				t.Fatalf("expected user tag to be invalidated")
 			}
 		})
 	}
 }
+
+// userStore — пользователи в памяти с поиском по id и email, как в БД
+type userStore map[string]models.User
+
+func (s userStore) repo() *mock.MockUserRepository {
+	return &mock.MockUserRepository{
+		GetByIDFn: func(ctx context.Context, id string) (*models.User, error) {
+			if u, ok := s[id]; ok {
+				return &u, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		GetByEmailFn: func(ctx context.Context, email string) (*models.User, error) {
+			for _, u := range s {
+				if u.Email == email {
+					return &u, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		UpdateFn: func(ctx context.Context, u *models.User) error {
+			s[u.ID.String()] = *u
+			return nil
+		},
+		DeleteFn: func(ctx context.Context, id string) error {
+			delete(s, id)
+			return nil
+		},
+	}
+}
+
+func newTaggedUserService(users userStore) UserService {
+	store := cache.NewLRUStore(100, 0)
+	opts := cache.Options{NotFound: gorm.ErrRecordNotFound, NegativeTTL: time.Minute}
+
+	return NewUserService(
+		users.repo(),
+		cache.NewTagged(store, "user:id:", UserTags, opts),
+		cache.NewTagged(store, "user:email:", UserTags, opts),
+		newLogger(),
+	)
+}
+
+func TestUserService_UpdateLeavesNoStaleEntries(t *testing.T) {
+	user := models.User{Base: models.Base{ID: uuid.New()}, FirstName: "Анна", Email: "old@mail.com"}
+	id := user.ID.String()
+	users := userStore{id: user}
+	svc := newTaggedUserService(users)
+
+	// прогреваем кеш по id и по email; по новому email кешируется промах
+	_, err := svc.GetByID(context.Background(), id)
+	assert.NoError(t, err)
+	_, err = svc.GetByEmail(context.Background(), "old@mail.com")
+	assert.NoError(t, err)
+	_, err = svc.GetByEmail(context.Background(), "new@mail.com")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	newEmail := "new@mail.com"
+	newName := "Мария"
+	_, err = svc.Update(context.Background(), id, &dto.UserUpdateRequest{FirstName: &newName, Email: &newEmail})
+	assert.NoError(t, err)
+
+	got, err := svc.GetByID(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Мария", got.FirstName)
+	assert.Equal(t, "new@mail.com", got.Email)
+
+	_, err = svc.GetByEmail(context.Background(), "old@mail.com")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "old email alias must be invalidated")
+
+	got, err = svc.GetByEmail(context.Background(), "new@mail.com")
+	assert.NoError(t, err, "cached miss for the new email must be dropped")
+	assert.Equal(t, "Мария", got.FirstName)
+}
+
+func TestUserService_DeleteLeavesNoStaleEntries(t *testing.T) {
+	user := models.User{Base: models.Base{ID: uuid.New()}, Email: "gone@mail.com"}
+	id := user.ID.String()
+	users := userStore{id: user}
+	svc := newTaggedUserService(users)
+
+	_, err := svc.GetByID(context.Background(), id)
+	assert.NoError(t, err)
+	_, err = svc.GetByEmail(context.Background(), "gone@mail.com")
+	assert.NoError(t, err)
+
+	assert.NoError(t, svc.Delete(context.Background(), id))
+
+	_, err = svc.GetByID(context.Background(), id)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = svc.GetByEmail(context.Background(), "gone@mail.com")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
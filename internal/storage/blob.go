@@ -0,0 +1,50 @@
+// Package storage хранит бинарные файлы (логотипы и другие медиа) в локальной
+// файловой системе или S3-совместимом хранилище.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBlobNotFound = errors.New("файл не найден")
+	ErrInvalidKey   = errors.New("некорректный ключ файла")
+)
+
+// BlobInfo — метаданные сохранённого файла
+type BlobInfo struct {
+	ContentType string
+	Size        int64
+	ETag        string
+	ModTime     time.Time
+}
+
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get открывает файл на чтение; вызывающий закрывает поток
+	Get(ctx context.Context, key string) (io.ReadCloser, *BlobInfo, error)
+
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidKey проверяет, что ключ — относительный путь без выхода за корень хранилища
+func ValidKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	if path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
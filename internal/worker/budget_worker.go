@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"effective-project/internal/service"
+	"log/slog"
+	"time"
+)
+
+// BudgetWorker периодически пересчитывает бюджеты всех пользователей,
+// чтобы пороги срабатывали и без изменений подписок (например, в начале месяца)
+type BudgetWorker struct {
+	budgets  service.BudgetService
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+func NewBudgetWorker(budgets service.BudgetService, interval time.Duration, logger *slog.Logger) *BudgetWorker {
+	return &BudgetWorker{
+		budgets:  budgets,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (w *BudgetWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("budget worker started", slog.Duration("interval", w.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("budget worker stopped")
+			return
+		case <-ticker.C:
+			if err := w.budgets.EvaluateAll(ctx); err != nil {
+				w.logger.Error("worker.budget: failed to evaluate budgets", slog.Any("error", err))
+			}
+		}
+	}
+}
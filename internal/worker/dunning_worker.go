@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"effective-project/internal/service"
+	"log/slog"
+	"time"
+)
+
+// DunningWorker выполняет повторные списания по неудачным платежам согласно политике взыскания
+type DunningWorker struct {
+	dunning  service.DunningService
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+func NewDunningWorker(dunning service.DunningService, interval time.Duration, logger *slog.Logger) *DunningWorker {
+	return &DunningWorker{
+		dunning:  dunning,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (w *DunningWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.logger.Info("dunning worker started", slog.Duration("interval", w.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("dunning worker stopped")
+			return
+		case <-ticker.C:
+			if err := w.dunning.ProcessDue(ctx); err != nil {
+				w.logger.Error("worker.dunning: failed to process due retries", slog.Any("error", err))
+			}
+		}
+	}
+}